@@ -0,0 +1,159 @@
+// Package analysis hands a drill-down bundle to a user-configured external command or HTTP
+// endpoint and returns its summary, so kubeve does not depend on any particular LLM provider.
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/config"
+	"github.com/a0xAi/kubeve/kube"
+)
+
+const (
+	defaultTimeout = 60 * time.Second
+	maxOutputBytes = 64 * 1024
+)
+
+// Bundle is the JSON document sent to the analyzer.
+type Bundle struct {
+	Event    BundleEvent `json:"event"`
+	Describe string      `json:"describe"`
+	Related  string      `json:"related"`
+	Logs     string      `json:"logs"`
+	// Termination is empty unless a container of the inspected pod exited abnormally.
+	Termination string `json:"termination,omitempty"`
+	// Diagnosis is the kind-specific failure analysis, e.g. for Jobs.
+	Diagnosis string `json:"diagnosis,omitempty"`
+	// Note is what the user noted on the event during the session, if anything.
+	Note string `json:"note,omitempty"`
+}
+
+// BundleEvent describes the selected event row.
+type BundleEvent struct {
+	Time      string `json:"time"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace"`
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+}
+
+// NewBundle combines an event row with its resource drill-down.
+func NewBundle(event BundleEvent, drilldown kube.ResourceDrillDown) Bundle {
+	return Bundle{
+		Event:    event,
+		Describe: drilldown.Describe,
+		Related:  drilldown.Related,
+		Logs:     drilldown.Logs,
+
+		Termination: drilldown.Termination,
+		Diagnosis:   drilldown.Diagnosis,
+	}
+}
+
+// Analyzer turns a bundle into a human-readable explanation.
+type Analyzer interface {
+	Analyze(ctx context.Context, bundle Bundle) (string, error)
+	Name() string
+}
+
+// New returns the analyzer configured in cfg, or nil if none is configured.
+// A command takes precedence over a URL.
+func New(cfg config.Analysis) Analyzer {
+	timeout := defaultTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	switch {
+	case len(cfg.Command) > 0 && strings.TrimSpace(cfg.Command[0]) != "":
+		return &commandAnalyzer{argv: cfg.Command, timeout: timeout}
+	case strings.TrimSpace(cfg.URL) != "":
+		return &httpAnalyzer{url: strings.TrimSpace(cfg.URL), client: &http.Client{Timeout: timeout}}
+	default:
+		return nil
+	}
+}
+
+// commandAnalyzer writes the bundle to the command's stdin and reads the summary from stdout.
+type commandAnalyzer struct {
+	argv    []string
+	timeout time.Duration
+}
+
+func (a *commandAnalyzer) Name() string {
+	return a.argv[0]
+}
+
+func (a *commandAnalyzer) Analyze(ctx context.Context, bundle Bundle) (string, error) {
+	payload, err := json.Marshal(bundle)
+	if err != nil {
+		return "", err
+	}
+	runCtx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, a.argv[0], a.argv[1:]...)
+	cmd.Stdin = bytes.NewReader(payload)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", a.Name(), err, truncate(msg))
+		}
+		return "", fmt.Errorf("%s: %w", a.Name(), err)
+	}
+	return truncate(strings.TrimSpace(stdout.String())), nil
+}
+
+// httpAnalyzer POSTs the bundle as JSON and uses the response body as the summary.
+type httpAnalyzer struct {
+	url    string
+	client *http.Client
+}
+
+func (a *httpAnalyzer) Name() string {
+	return a.url
+}
+
+func (a *httpAnalyzer) Analyze(ctx context.Context, bundle Bundle) (string, error) {
+	payload, err := json.Marshal(bundle)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOutputBytes))
+	if err != nil {
+		return "", err
+	}
+	text := strings.TrimSpace(string(body))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("%s: %s: %s", a.url, resp.Status, truncate(text))
+	}
+	return text, nil
+}
+
+func truncate(s string) string {
+	if len(s) <= maxOutputBytes {
+		return s
+	}
+	return s[:maxOutputBytes] + "..."
+}
@@ -0,0 +1,227 @@
+// Package archive keeps a local, append-only copy of watched events so history
+// outlives the cluster's event TTL.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a0xAi/kubeve/config"
+	"github.com/a0xAi/kubeve/kube"
+)
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// snapshotInterval is the minimum time between two snapshots of the same object, so a
+// flapping object does not fill the archive with copies of itself.
+const snapshotInterval = time.Minute
+
+// Record is one archived line: the event and, optionally, a gzip-compressed manifest of
+// its involved object taken when the event was archived.
+type Record struct {
+	kube.Event
+	Snapshot []byte `json:"snapshot,omitempty"`
+}
+
+// Dir returns the directory holding one archive file per cluster.
+func Dir() string {
+	p := config.Path()
+	if p == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(p), "archive")
+}
+
+// Path returns the archive file for cluster.
+func Path(cluster string) string {
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, unsafeName.ReplaceAllString(cluster, "_")+".jsonl")
+}
+
+// Archive appends events to a JSON lines file.
+type Archive struct {
+	mu   sync.Mutex
+	file *os.File
+	out  *bufio.Writer
+	enc  *json.Encoder
+	// seen skips the same occurrence when a watch restarts and re-lists events.
+	seen map[string]time.Time
+	// snapshotted holds when each object was last snapshotted.
+	snapshotted map[string]time.Time
+	path        string
+}
+
+// Open opens (creating if needed) the archive of cluster for appending.
+func Open(cluster string) (*Archive, error) {
+	p := Path(cluster)
+	if p == "" {
+		return nil, errors.New("could not resolve archive path")
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(p, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	out := bufio.NewWriter(file)
+	return &Archive{
+		file:        file,
+		out:         out,
+		enc:         json.NewEncoder(out),
+		seen:        make(map[string]time.Time),
+		snapshotted: make(map[string]time.Time),
+		path:        p,
+	}, nil
+}
+
+// Append writes event unless the same occurrence was already archived by this Archive.
+func (a *Archive) Append(event kube.Event) error {
+	return a.AppendRecord(Record{Event: event})
+}
+
+// AppendRecord is Append for an event with a snapshot of its object.
+func (a *Archive) AppendRecord(record Record) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.archivedLocked(record.Event) {
+		return nil
+	}
+	a.seen[record.UID] = record.Time
+	if record.Snapshot != nil {
+		a.snapshotted[objectKey(record.Event)] = time.Now()
+	}
+	if err := a.enc.Encode(record); err != nil {
+		return err
+	}
+	// Flush per event so a crash or kill loses at most the event being written.
+	return a.out.Flush()
+}
+
+// WantsSnapshot reports whether the object of event should be snapshotted with it: the
+// occurrence is new and the object was not snapshotted within the last minute.
+func (a *Archive) WantsSnapshot(event kube.Event, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return !a.archivedLocked(event) && now.Sub(a.snapshotted[objectKey(event)]) >= snapshotInterval
+}
+
+func (a *Archive) archivedLocked(event kube.Event) bool {
+	last, ok := a.seen[event.UID]
+	return ok && !event.Time.After(last)
+}
+
+// LatestSnapshot returns the newest archived record of the object with a snapshot.
+func (a *Archive) LatestSnapshot(namespace, kind, name string) (Record, bool, error) {
+	a.mu.Lock()
+	err := a.out.Flush()
+	a.mu.Unlock()
+	if err != nil {
+		return Record{}, false, err
+	}
+	file, err := os.Open(a.path)
+	if err != nil {
+		return Record{}, false, err
+	}
+	defer file.Close()
+
+	want := objectKey(kube.Event{Namespace: namespace, Kind: kind, Name: name})
+	var latest Record
+	found := false
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		// Most lines carry no snapshot; skip them without decoding.
+		if !bytes.Contains(line, []byte(`"snapshot"`)) {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(line, &record); err != nil || record.Snapshot == nil {
+			continue
+		}
+		if objectKey(record.Event) == want && (!found || !record.Time.Before(latest.Time)) {
+			latest, found = record, true
+		}
+	}
+	return latest, found, scanner.Err()
+}
+
+func objectKey(event kube.Event) string {
+	return event.Namespace + "/" + strings.ToLower(event.Kind) + "/" + event.Name
+}
+
+// Close flushes and closes the archive file.
+func (a *Archive) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.out.Flush(); err != nil {
+		_ = a.file.Close()
+		return err
+	}
+	return a.file.Close()
+}
+
+// Read returns archived events of cluster that happened at or after since, in file order.
+// Repeated occurrences with the same UID and time are returned once.
+func Read(cluster string, since time.Time) ([]kube.Event, error) {
+	p := Path(cluster)
+	if p == "" {
+		return nil, errors.New("could not resolve archive path")
+	}
+	return readFile(p, since)
+}
+
+// Since is Read for the cluster of a, including what a appended but has not flushed.
+func (a *Archive) Since(since time.Time) ([]kube.Event, error) {
+	a.mu.Lock()
+	err := a.out.Flush()
+	a.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return readFile(a.path, since)
+}
+
+var _ kube.Store = (*Archive)(nil)
+
+func readFile(p string, since time.Time) ([]kube.Event, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var events []kube.Event
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var event kube.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return events, fmt.Errorf("%s:%d: %w", p, line, err)
+		}
+		if event.Time.Before(since) {
+			continue
+		}
+		key := event.UID + "@" + event.Time.Format(time.RFC3339Nano)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
@@ -0,0 +1,125 @@
+// Package audit turns Kubernetes audit log entries (audit.k8s.io/v1) into kubeve events,
+// read from a log file or received from the API server's audit webhook backend.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/a0xAi/kubeve/kube/drilldown"
+)
+
+// Entry is the subset of an audit.k8s.io/v1 Event that kubeve renders.
+type Entry struct {
+	AuditID    string    `json:"auditID"`
+	Stage      string    `json:"stage"`
+	RequestURI string    `json:"requestURI"`
+	Verb       string    `json:"verb"`
+	User       UserInfo  `json:"user"`
+	ObjectRef  ObjectRef `json:"objectRef"`
+	Response   struct {
+		Code int `json:"code"`
+	} `json:"responseStatus"`
+	StageTimestamp time.Time `json:"stageTimestamp"`
+}
+
+type UserInfo struct {
+	Username string `json:"username"`
+}
+
+type ObjectRef struct {
+	Resource    string `json:"resource"`
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	Subresource string `json:"subresource"`
+	UID         string `json:"uid"`
+}
+
+// entryList is the body the webhook backend POSTs.
+type entryList struct {
+	Items []Entry `json:"items"`
+}
+
+// DefaultVerbs are the mutating verbs shown unless configured otherwise; reads would
+// drown the event stream.
+var DefaultVerbs = []string{"create", "update", "patch", "delete", "deletecollection"}
+
+// Filter selects which audit entries become events.
+type Filter struct {
+	Verbs map[string]bool
+	// SkipSystem drops requests from system:* users such as controllers and nodes.
+	SkipSystem bool
+}
+
+// NewFilter builds a filter from a comma separated verb list; empty means DefaultVerbs.
+func NewFilter(verbs string, skipSystem bool) Filter {
+	f := Filter{Verbs: make(map[string]bool), SkipSystem: skipSystem}
+	list := DefaultVerbs
+	if strings.TrimSpace(verbs) != "" {
+		list = strings.Split(verbs, ",")
+	}
+	for _, verb := range list {
+		if verb = strings.ToLower(strings.TrimSpace(verb)); verb != "" {
+			f.Verbs[verb] = true
+		}
+	}
+	return f
+}
+
+// Match reports whether entry should be shown. Only completed requests are considered so
+// each API call appears once.
+func (f Filter) Match(entry Entry) bool {
+	if entry.Stage != "ResponseComplete" && entry.Stage != "Panic" {
+		return false
+	}
+	if !f.Verbs[strings.ToLower(entry.Verb)] {
+		return false
+	}
+	if f.SkipSystem && strings.HasPrefix(entry.User.Username, "system:") {
+		return false
+	}
+	return true
+}
+
+// ToEvent renders an audit entry as an event of type "Audit" whose reason is the verb.
+func ToEvent(entry Entry) kube.Event {
+	resource := entry.ObjectRef.Resource
+	name := entry.ObjectRef.Name
+	if name == "" {
+		name = "*"
+	}
+	// Known resources use their kind so rows open the usual drill-down.
+	if ref, err := drilldown.ParseObjectRef(resource + "/" + name); err == nil && entry.ObjectRef.Subresource == "" {
+		resource = ref.Kind
+	}
+	if entry.ObjectRef.Subresource != "" {
+		resource += "/" + entry.ObjectRef.Subresource
+	}
+	message := fmt.Sprintf("%s %s by %s", strings.ToUpper(entry.Verb), entry.RequestURI, entry.User.Username)
+	if entry.Response.Code != 0 {
+		message += fmt.Sprintf(" (%d)", entry.Response.Code)
+	}
+	event := kube.Event{
+		UID:       entry.AuditID,
+		Time:      entry.StageTimestamp,
+		Namespace: entry.ObjectRef.Namespace,
+		Kind:      resource,
+		Name:      name,
+		ObjectUID: entry.ObjectRef.UID,
+		Type:      "Audit",
+		Reason:    entry.Verb,
+		Message:   message,
+	}
+	event.Fingerprint = kube.Fingerprint(event)
+	return event
+}
+
+// parseLine decodes one line of a JSON audit log.
+func parseLine(line []byte) (Entry, error) {
+	var entry Entry
+	err := json.Unmarshal(line, &entry)
+	return entry, err
+}
@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+)
+
+const tailPollInterval = 500 * time.Millisecond
+
+// TailFile follows a JSON audit log like tail -F, starting at its current end and
+// reopening it after rotation or truncation. Matching entries are passed to handler
+// until ctx is done.
+func TailFile(ctx context.Context, path string, filter Filter, handler func(kube.Event)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReaderSize(file, 64*1024)
+
+	var partial []byte
+	for {
+		line, err := reader.ReadBytes('\n')
+		offset += int64(len(line))
+		if err == nil {
+			line = append(partial, line...)
+			partial = nil
+			if entry, parseErr := parseLine(line); parseErr == nil && filter.Match(entry) {
+				handler(ToEvent(entry))
+			}
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+		// Keep an incomplete last line until the writer finishes it.
+		partial = append(partial, line...)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(tailPollInterval):
+		}
+
+		rotated, truncated := fileChanged(file, path, offset)
+		if rotated {
+			next, openErr := os.Open(path)
+			if openErr != nil {
+				continue
+			}
+			_ = file.Close()
+			file = next
+			offset = 0
+			partial = nil
+			reader.Reset(file)
+		} else if truncated {
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			offset = 0
+			partial = nil
+			reader.Reset(file)
+		}
+	}
+}
+
+// fileChanged reports whether path now names a different file or the open file shrank.
+func fileChanged(file *os.File, path string, offset int64) (rotated bool, truncated bool) {
+	current, err := file.Stat()
+	if err != nil {
+		return false, false
+	}
+	latest, err := os.Stat(path)
+	if err == nil && !os.SameFile(current, latest) {
+		return true, false
+	}
+	return false, current.Size() < offset
+}
@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/a0xAi/kubeve/kube"
+)
+
+const maxWebhookBody = 32 << 20
+
+// WebhookHandler accepts EventList POSTs from the API server's audit webhook backend
+// (--audit-webhook-config-file) and passes matching entries to handler. When handler
+// fails the batch is answered with 503, so the API server retries it instead of
+// dropping the entries.
+func WebhookHandler(filter Filter, handler func(kube.Event) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var list entryList
+		if err := json.Unmarshal(body, &list); err != nil {
+			http.Error(w, "invalid audit EventList: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, entry := range list.Items {
+			if !filter.Match(entry) {
+				continue
+			}
+			if err := handler(ToEvent(entry)); err != nil {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
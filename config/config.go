@@ -11,17 +11,134 @@ import (
 
 type Flags struct {
 	DisableLogo bool `yaml:"disableLogo"`
+	// HideHeader starts without the header; cluster and namespace move to the table title.
+	HideHeader bool `yaml:"hideHeader,omitempty"`
+	// Logo replaces the built-in ASCII logo. tview color tags such as [red] are allowed.
+	Logo string `yaml:"logo,omitempty"`
+	// Mouse lets the mouse select and scroll rows and preview the row under the
+	// pointer. The terminal's own text selection then needs a modifier, usually shift.
+	Mouse bool `yaml:"mouse,omitempty"`
+	// DropDeleted removes the rows of events the cluster deletes, usually when they
+	// expire, instead of greying them out.
+	DropDeleted bool `yaml:"dropDeleted,omitempty"`
+	// WarningsOnly starts with the API server sending only Warning events, like
+	// -warnings-only; ctrl+w toggles it.
+	WarningsOnly bool `yaml:"warningsOnly,omitempty"`
+	// StatusChanges adds rows for Pod and Deployment status transitions, like
+	// -status-changes.
+	StatusChanges bool `yaml:"statusChanges,omitempty"`
+	// Sort orders the table by comma separated keys, e.g. "namespace,-time"; a leading -
+	// sorts descending. Unset keeps arrival order, or the noisiest first when aggregated.
+	Sort string `yaml:"sort,omitempty"`
+	// HideInaccessibleNamespaces leaves namespaces whose events cannot be read out of the
+	// namespace picker instead of listing them last, greyed out.
+	HideInaccessibleNamespaces bool `yaml:"hideInaccessibleNamespaces,omitempty"`
 }
 
+// Theme sets the colors of the UI: a built-in theme by Name, or custom colors. A theme
+// named AutoTheme, or none at all, follows the terminal's background.
 type Theme struct {
 	Name            string `yaml:"name,omitempty"`
 	BackgroundColor string `yaml:"backgroundColor"`
 	TextColor       string `yaml:"textColor"`
 }
 
+// Connection overrides how the API server is reached, on top of the kubeconfig.
+type Connection struct {
+	ProxyURL              string `yaml:"proxyURL,omitempty"`
+	CertificateAuthority  string `yaml:"certificateAuthority,omitempty"`
+	InsecureSkipTLSVerify bool   `yaml:"insecureSkipTLSVerify,omitempty"`
+	// QPS and Burst are the client's request rate limits; unset keeps client-go's 5 and 10.
+	QPS   float32 `yaml:"qps,omitempty"`
+	Burst int     `yaml:"burst,omitempty"`
+	// DisableProtobuf requests JSON instead of protobuf, e.g. for proxies that inspect bodies.
+	DisableProtobuf bool `yaml:"disableProtobuf,omitempty"`
+	// StartupTimeoutSeconds bounds the requests made at start, the namespace list and
+	// the server version; unset is 10.
+	StartupTimeoutSeconds int `yaml:"startupTimeoutSeconds,omitempty"`
+	// SkipVersionCheck never asks for the server version, which is only shown in the header.
+	SkipVersionCheck bool `yaml:"skipVersionCheck,omitempty"`
+}
+
+// Noise tunes detection of objects flooding the event stream.
+type Noise struct {
+	StormThreshold     int `yaml:"stormThreshold"`
+	StormWindowSeconds int `yaml:"stormWindowSeconds"`
+}
+
+// Annotation explains an event reason, optionally narrowed by a message regular expression,
+// and is shown in the details view.
+type Annotation struct {
+	Reason      string `yaml:"reason"`
+	Message     string `yaml:"message,omitempty"`
+	Explanation string `yaml:"explanation"`
+	Runbook     string `yaml:"runbook,omitempty"`
+}
+
+// Analysis configures an external analyzer that explains drill-downs. Command receives the
+// bundle as JSON on stdin and prints the summary; URL receives it as a JSON POST.
+type Analysis struct {
+	Command        []string `yaml:"command,omitempty"`
+	URL            string   `yaml:"url,omitempty"`
+	TimeoutSeconds int      `yaml:"timeoutSeconds,omitempty"`
+}
+
+// Archive controls the local event archive and the warning about short event retention.
+type Archive struct {
+	Enabled                 bool `yaml:"enabled"`
+	RetentionWarningMinutes int  `yaml:"retentionWarningMinutes"`
+	// Snapshots stores a compressed manifest of each event's involved object with it,
+	// at most once a minute per object.
+	Snapshots bool `yaml:"snapshots,omitempty"`
+}
+
+// Layout sets the space between the terminal edge and the UI. Unset values keep the
+// default of one cell; zero is allowed, e.g. for tmux splits.
+type Layout struct {
+	Padding *int `yaml:"padding,omitempty"`
+	// PaddingX and PaddingY override Padding for the left/right and top/bottom edges.
+	PaddingX *int `yaml:"paddingX,omitempty"`
+	PaddingY *int `yaml:"paddingY,omitempty"`
+}
+
+// FramePadding resolves the horizontal and vertical padding.
+func (l Layout) FramePadding() (x, y int) {
+	x, y = 1, 1
+	if l.Padding != nil {
+		x, y = *l.Padding, *l.Padding
+	}
+	if l.PaddingX != nil {
+		x = *l.PaddingX
+	}
+	if l.PaddingY != nil {
+		y = *l.PaddingY
+	}
+	return max(x, 0), max(y, 0)
+}
+
+// Serve holds the forwarder settings of kubeve serve. In-cluster they usually come from
+// the ConfigMap mounted at ~/.kubeve and are reloaded when it changes; flags given on the
+// command line take precedence.
+type Serve struct {
+	Namespace     string `yaml:"namespace,omitempty"`
+	WarningsOnly  bool   `yaml:"warningsOnly,omitempty"`
+	FieldSelector string `yaml:"fieldSelector,omitempty"`
+	ListLimit     int    `yaml:"listLimit,omitempty"`
+}
+
 type Config struct {
-	Flags Flags `yaml:"flags"`
-	Theme Theme `yaml:"theme"`
+	Flags      Flags        `yaml:"flags"`
+	Theme      Theme        `yaml:"theme"`
+	Layout     Layout       `yaml:"layout,omitempty"`
+	Connection Connection   `yaml:"connection,omitempty"`
+	Noise      Noise        `yaml:"noise"`
+	Dictionary []Annotation `yaml:"dictionary,omitempty"`
+	Analysis   Analysis     `yaml:"analysis,omitempty"`
+	Archive    Archive      `yaml:"archive"`
+	// Keys rebinds actions, e.g. last-event: ctrl+e. Values are space separated keys;
+	// an empty value unbinds the action. kubeve keys prints the effective keymap.
+	Keys  map[string]string `yaml:"keys,omitempty"`
+	Serve Serve             `yaml:"serve,omitempty"`
 }
 
 type fileConfig struct {
@@ -29,8 +146,10 @@ type fileConfig struct {
 }
 
 var Default = Config{
-	Flags: Flags{DisableLogo: false},
-	Theme: Theme{Name: "midnight", BackgroundColor: "#000000", TextColor: "#ffffff"},
+	Flags:   Flags{DisableLogo: false},
+	Theme:   Theme{Name: AutoTheme},
+	Noise:   Noise{StormThreshold: 100, StormWindowSeconds: 60},
+	Archive: Archive{RetentionWarningMinutes: 180},
 }
 
 var predefinedThemes = []Theme{
@@ -46,6 +165,34 @@ var predefinedThemes = []Theme{
 	{Name: "ember", BackgroundColor: "#1b0f0a", TextColor: "#ffd3b6"},
 }
 
+// AutoTheme is the theme name that picks darkTheme or lightTheme by the terminal's
+// background color.
+const AutoTheme = "auto"
+
+// darkTheme and lightTheme are the themes AutoTheme picks from. darkTheme also fills in
+// colors a custom theme leaves unset.
+var (
+	darkTheme  = builtinTheme("midnight")
+	lightTheme = builtinTheme("mono-light")
+)
+
+func builtinTheme(name string) Theme {
+	theme, ok := ThemeByName(name)
+	if !ok {
+		panic("config: no built-in theme " + name)
+	}
+	return theme
+}
+
+// ThemeForBackground returns the built-in theme AutoTheme uses on a light or dark
+// terminal background.
+func ThemeForBackground(light bool) Theme {
+	if light {
+		return lightTheme
+	}
+	return darkTheme
+}
+
 // Themes returns all built-in selectable themes.
 func Themes() []Theme {
 	themes := make([]Theme, len(predefinedThemes))
@@ -87,8 +234,12 @@ func themeNameByColors(backgroundColor string, textColor string) string {
 	return ""
 }
 
-// ResolveTheme normalizes a theme and applies defaults.
+// ResolveTheme normalizes a theme and applies defaults. A theme without name or colors
+// resolves to AutoTheme, which has no colors of its own.
 func ResolveTheme(theme Theme) Theme {
+	if theme == (Theme{}) || strings.EqualFold(strings.TrimSpace(theme.Name), AutoTheme) {
+		return Theme{Name: AutoTheme}
+	}
 	if preset, ok := ThemeByName(theme.Name); ok {
 		return preset
 	}
@@ -97,10 +248,10 @@ func ResolveTheme(theme Theme) Theme {
 		resolved.Name = ""
 	}
 	if resolved.BackgroundColor == "" {
-		resolved.BackgroundColor = Default.Theme.BackgroundColor
+		resolved.BackgroundColor = darkTheme.BackgroundColor
 	}
 	if resolved.TextColor == "" {
-		resolved.TextColor = Default.Theme.TextColor
+		resolved.TextColor = darkTheme.TextColor
 	}
 	if resolved.Name == "" {
 		if name := themeNameByColors(resolved.BackgroundColor, resolved.TextColor); name != "" {
@@ -129,13 +280,37 @@ func Load() Config {
 	if err != nil {
 		return Default
 	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return Default
+	}
+	return cfg
+}
+
+// Parse decodes a configuration file and fills in defaults for unset values.
+func Parse(data []byte) (Config, error) {
 	var fc fileConfig
 	if err := yaml.Unmarshal(data, &fc); err != nil {
-		return Default
+		return Default, err
 	}
 	cfg := fc.Config
 	cfg.Theme = ResolveTheme(cfg.Theme)
-	return cfg
+	if cfg.Noise.StormThreshold <= 0 {
+		cfg.Noise.StormThreshold = Default.Noise.StormThreshold
+	}
+	if cfg.Noise.StormWindowSeconds <= 0 {
+		cfg.Noise.StormWindowSeconds = Default.Noise.StormWindowSeconds
+	}
+	if cfg.Archive.RetentionWarningMinutes <= 0 {
+		cfg.Archive.RetentionWarningMinutes = Default.Archive.RetentionWarningMinutes
+	}
+	return cfg, nil
+}
+
+// Marshal renders the configuration in the on-disk file format.
+func Marshal(cfg Config) ([]byte, error) {
+	cfg.Theme = ResolveTheme(cfg.Theme)
+	return yaml.Marshal(fileConfig{Config: cfg})
 }
 
 // Save writes the configuration to disk.
@@ -144,8 +319,7 @@ func Save(cfg Config) error {
 	if p == "" {
 		return fmt.Errorf("could not resolve config path")
 	}
-	cfg.Theme = ResolveTheme(cfg.Theme)
-	payload, err := yaml.Marshal(fileConfig{Config: cfg})
+	payload, err := Marshal(cfg)
 	if err != nil {
 		return err
 	}
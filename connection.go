@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/a0xAi/kubeve/config"
+	"github.com/a0xAi/kubeve/kube/client"
+)
+
+// connectionFlags registers the API connection overrides on fs, defaulting to the values
+// from the config file. The returned function applies them once fs has been parsed.
+func connectionFlags(fs *flag.FlagSet) func() {
+	defaults := config.Load().Connection
+	proxyURL := fs.String("proxy-url", defaults.ProxyURL, "proxy URL for API server requests (HTTPS_PROXY is used when empty)")
+	certificateAuthority := fs.String("certificate-authority", defaults.CertificateAuthority, "path to a CA bundle used to verify the API server")
+	insecure := fs.Bool("insecure-skip-tls-verify", defaults.InsecureSkipTLSVerify, "disable API server certificate verification (insecure)")
+	qps := fs.Float64("qps", float64(defaults.QPS), "API requests per second the client may make (0 for the client-go default of 5)")
+	burst := fs.Int("burst", defaults.Burst, "API requests the client may burst above -qps (0 for the client-go default of 10)")
+
+	return func() {
+		client.SetConnectionOptions(client.ConnectionOptions{
+			ProxyURL:              *proxyURL,
+			CertificateAuthority:  *certificateAuthority,
+			InsecureSkipTLSVerify: *insecure,
+			QPS:                   float32(*qps),
+			Burst:                 *burst,
+			DisableProtobuf:       defaults.DisableProtobuf,
+			StartupTimeout:        time.Duration(defaults.StartupTimeoutSeconds) * time.Second,
+		})
+		if *insecure {
+			fmt.Fprintln(os.Stderr, "WARNING: TLS certificate verification is disabled; the connection to the API server can be intercepted.")
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/archive"
+	"github.com/a0xAi/kubeve/config"
+	"github.com/a0xAi/kubeve/internal/format"
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/a0xAi/kubeve/kube/client"
+	"github.com/a0xAi/kubeve/kube/watch"
+)
+
+// runDigest prints a plain-text summary of recent events for standups and ops reports.
+func runDigest(args []string) {
+	fs := flag.NewFlagSet("digest", flag.ExitOnError)
+	namespace := fs.String("n", "", "namespace to summarize (empty for all namespaces)")
+	since := fs.Duration("since", 24*time.Hour, "how far back to look")
+	useArchive := fs.Bool("archive", config.Load().Archive.Enabled, "also summarize the local event archive of the current context")
+	applyConnection := connectionFlags(fs)
+	fs.Parse(args)
+	applyConnection()
+
+	if *since <= 0 {
+		fmt.Fprintln(os.Stderr, "-since must be positive")
+		os.Exit(2)
+	}
+
+	var store kube.Store
+	if *useArchive {
+		arc, err := openDigestArchive()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening the archive: %v\n", err)
+			os.Exit(1)
+		}
+		if arc != nil {
+			defer arc.Close()
+			store = arc
+		}
+	}
+
+	digest, err := watch.BuildDigest(context.Background(), *namespace, *since, store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error building digest: %v\nRun `kubeve doctor` for diagnostics.\n", err)
+		os.Exit(1)
+	}
+
+	scope := *namespace
+	if scope == "" {
+		scope = "all namespaces"
+	}
+	fmt.Printf("Cluster digest for %s, %s to %s\n\n",
+		scope, digest.Since.Format("2006-01-02 15:04"), digest.Until.Format("2006-01-02 15:04 MST"))
+
+	fmt.Println("Warnings per namespace")
+	if len(digest.Namespaces) == 0 {
+		fmt.Println("  none")
+	}
+	for _, ns := range digest.Namespaces {
+		fmt.Printf("  %-30s %6s  %s\n", ns.Namespace, format.Count(int64(ns.Warnings)), formatReasons(ns.TopReasons))
+	}
+
+	fmt.Println("\nNew crash looping workloads")
+	if len(digest.CrashLoops) == 0 {
+		fmt.Println("  none")
+	}
+	for _, loop := range digest.CrashLoops {
+		fmt.Printf("  %s/%s  since %s, %d pod(s), %d back-offs\n",
+			loop.Namespace, loop.Workload, loop.FirstSeen.Format("01-02 15:04"), loop.Pods, loop.BackOffs)
+	}
+
+	fmt.Println("\nNode condition changes")
+	if len(digest.NodeFlaps) == 0 {
+		fmt.Println("  none")
+	}
+	for _, flap := range digest.NodeFlaps {
+		fmt.Printf("  %-30s %s\n", flap.Node, formatReasons(flap.Reasons))
+	}
+
+	if digest.Archived {
+		fmt.Println("\nBased on the local archive and the events still retained by the API server.")
+	} else {
+		fmt.Println("\nBased on events still retained by the API server (one hour by default). Run :archive in kubeve to cover longer windows.")
+	}
+}
+
+// openDigestArchive opens the archive of the current context, named like the live view
+// names the archive it writes, or returns nil when nothing was archived for it.
+func openDigestArchive() (*archive.Archive, error) {
+	name, err := client.CurrentContext()
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = "in-cluster"
+	}
+	if _, err := os.Stat(archive.Path(name)); err != nil {
+		return nil, nil
+	}
+	return archive.Open(name)
+}
+
+func formatReasons(reasons []watch.ReasonCount) string {
+	parts := make([]string, 0, len(reasons))
+	for _, rc := range reasons {
+		parts = append(parts, rc.Reason+"×"+format.Count(int64(rc.Count)))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/a0xAi/kubeve/kube/client"
+)
+
+// runDoctor prints connectivity diagnostics with remediation hints.
+func runDoctor(args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	namespace := fs.String("n", "", "namespace to check events access for (empty for all namespaces)")
+	applyConnection := connectionFlags(fs)
+	fs.Parse(args)
+	applyConnection()
+
+	failed := false
+	for _, check := range client.Diagnose(context.Background(), *namespace) {
+		marker := "[✓]"
+		switch check.Status {
+		case client.CheckWarn:
+			marker = "[!]"
+		case client.CheckFail:
+			marker = "[✗]"
+			failed = true
+		case client.CheckSkipped:
+			marker = "[-]"
+		}
+		fmt.Printf("%s %-15s %s\n", marker, check.Name, check.Detail)
+		if check.Remedy != "" {
+			fmt.Printf("    %-15s → %s\n", "", check.Remedy)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,81 @@
+// Package format renders durations, timestamps, counts and sizes the same way across the
+// table, drill-downs, the digest and exports.
+package format
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimestampLayout is the layout of event times in table rows; rows are parsed back with it.
+const TimestampLayout = time.RFC3339
+
+// Timestamp renders an absolute time, e.g. 2024-05-01T12:30:00Z.
+func Timestamp(t time.Time) string {
+	return t.Format(TimestampLayout)
+}
+
+// ParseTimestamp parses a time rendered by Timestamp.
+func ParseTimestamp(s string) (time.Time, error) {
+	return time.Parse(TimestampLayout, s)
+}
+
+// Clock renders the time of day of t, for lists of recent events.
+func Clock(t time.Time) string {
+	return t.Format("15:04:05")
+}
+
+// Duration renders d coarsely: 45s, 12m, 2.5h, 3d. Negative durations are rendered
+// by their magnitude.
+func Duration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	switch {
+	case d < time.Minute:
+		return strconv.Itoa(int(d.Round(time.Second).Seconds())) + "s"
+	case d < time.Hour:
+		return strconv.Itoa(int(d.Round(time.Minute).Minutes())) + "m"
+	case d < 48*time.Hour:
+		return decimal(d.Hours()) + "h"
+	default:
+		return decimal(d.Hours()/24) + "d"
+	}
+}
+
+// Ago renders how long before now t was, e.g. "5m ago".
+func Ago(t, now time.Time) string {
+	return Duration(now.Sub(t)) + " ago"
+}
+
+// Count renders n compactly: 999, 1.2k, 3.4M.
+func Count(n int64) string {
+	switch {
+	case n < 0:
+		return "-" + Count(-n)
+	case n < 1000:
+		return strconv.FormatInt(n, 10)
+	case n < 1000000:
+		return decimal(float64(n)/1000) + "k"
+	default:
+		return decimal(float64(n)/1000000) + "M"
+	}
+}
+
+// Bytes renders a size in binary units: 512 B, 1.5 KiB, 3 MiB.
+func Bytes(n int64) string {
+	switch {
+	case n < 1024:
+		return strconv.FormatInt(n, 10) + " B"
+	case n < 1024*1024:
+		return decimal(float64(n)/1024) + " KiB"
+	default:
+		return decimal(float64(n)/(1024*1024)) + " MiB"
+	}
+}
+
+// decimal renders v with at most one decimal, dropping a trailing ".0".
+func decimal(v float64) string {
+	return strings.TrimSuffix(strconv.FormatFloat(v, 'f', 1, 64), ".0")
+}
@@ -0,0 +1,152 @@
+package testcluster
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// AddCustomObject serves obj, e.g. a custom resource, under the resource name plural of
+// its group and version, and lists that resource in discovery.
+func (c *Cluster) AddCustomObject(plural string, namespaced bool, obj *unstructured.Unstructured) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	gvk := obj.GroupVersionKind()
+	gv := gvk.GroupVersion()
+	if c.resources == nil {
+		c.resources = make(map[schema.GroupVersion][]metav1.APIResource)
+		c.objects = make(map[string]*unstructured.Unstructured)
+	}
+	known := false
+	for _, r := range c.resources[gv] {
+		known = known || r.Name == plural
+	}
+	if !known {
+		c.resources[gv] = append(c.resources[gv], metav1.APIResource{
+			Name:         plural,
+			SingularName: strings.ToLower(gvk.Kind),
+			Namespaced:   namespaced,
+			Kind:         gvk.Kind,
+			Verbs:        metav1.Verbs{"get"},
+		})
+	}
+	namespace := ""
+	if namespaced {
+		namespace = obj.GetNamespace()
+	}
+	c.objects[objectPath(gv, namespace, plural, obj.GetName())] = obj.DeepCopy()
+}
+
+func objectPath(gv schema.GroupVersion, namespace, plural, name string) string {
+	if namespace == "" {
+		return path.Join(gv.String(), plural, name)
+	}
+	return path.Join(gv.String(), "namespaces", namespace, plural, name)
+}
+
+// serveCoreDiscovery answers legacy discovery of the core group with the resources the
+// server serves.
+func (c *Cluster) serveCoreDiscovery(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api" {
+		writeJSON(w, metav1.APIVersions{
+			TypeMeta: metav1.TypeMeta{Kind: "APIVersions"},
+			Versions: []string{"v1"},
+		})
+		return
+	}
+	writeJSON(w, metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{APIVersion: "v1", Kind: "APIResourceList"},
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "namespaces", SingularName: "namespace", Kind: "Namespace", Verbs: metav1.Verbs{"list", "watch"}},
+		},
+	})
+}
+
+// serveGroups answers legacy discovery of the API groups: events.k8s.io and the groups
+// of custom objects.
+func (c *Cluster) serveGroups(w http.ResponseWriter, _ *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	versions := map[string][]string{"events.k8s.io": {"v1"}}
+	for gv := range c.resources {
+		versions[gv.Group] = append(versions[gv.Group], gv.Version)
+	}
+	list := metav1.APIGroupList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "APIGroupList"}}
+	for group, vs := range versions {
+		sort.Strings(vs)
+		g := metav1.APIGroup{Name: group}
+		for _, v := range vs {
+			g.Versions = append(g.Versions, metav1.GroupVersionForDiscovery{GroupVersion: group + "/" + v, Version: v})
+		}
+		g.PreferredVersion = g.Versions[0]
+		list.Groups = append(list.Groups, g)
+	}
+	sort.Slice(list.Groups, func(i, j int) bool { return list.Groups[i].Name < list.Groups[j].Name })
+	writeJSON(w, list)
+}
+
+// serveGroupVersion answers legacy discovery of one group version.
+func (c *Cluster) serveGroupVersion(w http.ResponseWriter, r *http.Request) {
+	gv := schema.GroupVersion{Group: r.PathValue("group"), Version: r.PathValue("version")}
+	c.mu.Lock()
+	resources := append([]metav1.APIResource(nil), c.resources[gv]...)
+	c.mu.Unlock()
+	if gv.Group == "events.k8s.io" && gv.Version == "v1" {
+		resources = append(resources, metav1.APIResource{Name: "events", SingularName: "event", Namespaced: true, Kind: "Event", Verbs: metav1.Verbs{"list", "watch"}})
+	}
+	if len(resources) == 0 {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, r.URL.Path+" not found")
+		return
+	}
+	writeJSON(w, metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{APIVersion: "v1", Kind: "APIResourceList"},
+		GroupVersion: gv.String(),
+		APIResources: resources,
+	})
+}
+
+// serveCustomObject returns an object added with AddCustomObject.
+func (c *Cluster) serveCustomObject(w http.ResponseWriter, r *http.Request) {
+	gv := schema.GroupVersion{Group: r.PathValue("group"), Version: r.PathValue("version")}
+	key := objectPath(gv, r.PathValue("namespace"), r.PathValue("resource"), r.PathValue("name"))
+	c.mu.Lock()
+	obj, ok := c.objects[key]
+	c.mu.Unlock()
+	if !ok {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, r.URL.Path+" not found")
+		return
+	}
+	writeJSON(w, obj.Object)
+}
+
+// serveAccessReview answers a SelfSubjectAccessReview, allowing everything except
+// reading events in namespaces passed to DenyEvents. The typed client posts protobuf.
+func (c *Cluster) serveAccessReview(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+		return
+	}
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(body, nil, nil)
+	review, ok := obj.(*authorizationv1.SelfSubjectAccessReview)
+	if err != nil || !ok || review.Spec.ResourceAttributes == nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, "expected a SelfSubjectAccessReview of a resource")
+		return
+	}
+	attrs := review.Spec.ResourceAttributes
+	c.mu.Lock()
+	denied := attrs.Resource == "events" && c.deniedEvents[attrs.Namespace]
+	c.mu.Unlock()
+	review.APIVersion, review.Kind = "authorization.k8s.io/v1", "SelfSubjectAccessReview"
+	review.Status.Allowed = !denied
+	writeJSON(w, review)
+}
@@ -0,0 +1,715 @@
+// Package testcluster runs a minimal fake Kubernetes API server for integration tests.
+// It serves what kubeve needs to start and stream events: the server version, the
+// namespace list and events.k8s.io/v1 events with list and watch, and namespace deletions,
+// plus pods (also listed by label selector) and their logs, discovery, access reviews
+// and custom objects added with AddCustomObject. With ServeCoreEvents it also lists the
+// events as core/v1 events by field selector, as drill-downs read them.
+// Everything else answers 404, which kubeve treats like a cluster without that API or
+// object.
+package testcluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// Cluster is a running fake API server with a kubeconfig pointing at it.
+type Cluster struct {
+	server *httptest.Server
+
+	mu         sync.Mutex
+	namespaces []string
+	rv         int
+	events     map[types.UID]*eventsv1.Event
+	order      []types.UID
+	history    []change
+	watchers   map[*watcher]bool
+	nextUID    int
+	// nsHistory and nsWatchers are history and watchers for namespaces.
+	nsHistory  []namespaceChange
+	nsWatchers map[chan namespaceChange]bool
+	// unauthorized rejects every request with 401, like a server after the
+	// client's credentials expired.
+	unauthorized bool
+	// minor is the Kubernetes minor version the server reports.
+	minor string
+	// deniedEvents holds namespaces whose events access reviews deny.
+	deniedEvents map[string]bool
+	// pods are served by namespace/name.
+	pods map[string]*corev1.Pod
+	// logs are served by namespace/pod/container, with a "/previous" suffix for the
+	// previous run.
+	logs map[string]string
+	// logsChanged is closed and replaced whenever logs change, waking followers.
+	logsChanged chan struct{}
+	// logFollowers counts the open requests following logs.
+	logFollowers int
+	// coreEvents serves the events as core/v1 events too.
+	coreEvents bool
+	// resources and objects are the custom objects added with AddCustomObject and
+	// their resources for discovery.
+	resources map[schema.GroupVersion][]metav1.APIResource
+	objects   map[string]*unstructured.Unstructured
+}
+
+type change struct {
+	rv    int
+	kind  string
+	event *eventsv1.Event
+}
+
+type namespaceChange struct {
+	rv   int
+	kind string
+	name string
+}
+
+type watcher struct {
+	namespace string
+	selector  fields.Selector
+	changes   chan change
+}
+
+// Start runs a fake API server serving namespaces until the test ends and points
+// KUBECONFIG at it. HOME is moved to a temporary directory, so the test does not read
+// or write the user's kubeve config.
+func Start(t testing.TB, namespaces ...string) *Cluster {
+	t.Helper()
+	c := &Cluster{
+		namespaces:  namespaces,
+		events:      make(map[types.UID]*eventsv1.Event),
+		watchers:    make(map[*watcher]bool),
+		nsWatchers:  make(map[chan namespaceChange]bool),
+		logsChanged: make(chan struct{}),
+		minor:       "33",
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /version", c.serveVersion)
+	mux.HandleFunc("GET /api/v1/namespaces", c.serveNamespaces)
+	mux.HandleFunc("GET /apis/events.k8s.io/v1/events", c.serveEvents)
+	mux.HandleFunc("GET /apis/events.k8s.io/v1/namespaces/{namespace}/events", c.serveEvents)
+	mux.HandleFunc("POST /apis/authorization.k8s.io/v1/selfsubjectaccessreviews", c.serveAccessReview)
+	mux.HandleFunc("GET /api/v1/namespaces/{namespace}/events", c.serveCoreEvents)
+	mux.HandleFunc("GET /api/v1/namespaces/{namespace}/pods", c.servePods)
+	mux.HandleFunc("GET /api/v1/namespaces/{namespace}/pods/{name}", c.servePod)
+	mux.HandleFunc("GET /api/v1/namespaces/{namespace}/pods/{name}/log", c.serveLogs)
+	mux.HandleFunc("GET /api", c.serveCoreDiscovery)
+	mux.HandleFunc("GET /api/v1", c.serveCoreDiscovery)
+	mux.HandleFunc("GET /apis", c.serveGroups)
+	mux.HandleFunc("GET /apis/{group}/{version}", c.serveGroupVersion)
+	mux.HandleFunc("GET /apis/{group}/{version}/{resource}/{name}", c.serveCustomObject)
+	mux.HandleFunc("GET /apis/{group}/{version}/namespaces/{namespace}/{resource}/{name}", c.serveCustomObject)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, r.URL.Path+" not found")
+	})
+	c.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mu.Lock()
+		unauthorized := c.unauthorized
+		c.mu.Unlock()
+		if unauthorized {
+			writeStatus(w, http.StatusUnauthorized, metav1.StatusReasonUnauthorized, "Unauthorized")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(c.close)
+
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	namespace := metav1.NamespaceDefault
+	if len(namespaces) > 0 {
+		namespace = namespaces[0]
+	}
+	content := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+    namespace: %s
+current-context: test
+users:
+- name: test
+  user:
+    token: test
+`, c.server.URL, namespace)
+	if err := os.WriteFile(kubeconfig, []byte(content), 0o600); err != nil {
+		t.Fatalf("write kubeconfig: %v", err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+	t.Setenv("HOME", dir)
+	return c
+}
+
+// close ends open watches before shutting the server down, which otherwise waits for them.
+func (c *Cluster) close() {
+	c.mu.Lock()
+	for w := range c.watchers {
+		close(w.changes)
+		delete(c.watchers, w)
+	}
+	for ch := range c.nsWatchers {
+		close(ch)
+		delete(c.nsWatchers, ch)
+	}
+	c.mu.Unlock()
+	c.server.Close()
+}
+
+// SetUnauthorized makes the server reject all requests with 401 Unauthorized until it
+// is called with false.
+func (c *Cluster) SetUnauthorized(unauthorized bool) {
+	c.mu.Lock()
+	c.unauthorized = unauthorized
+	c.mu.Unlock()
+}
+
+// SetMinorVersion makes /version report Kubernetes 1.<minor>, e.g. a release newer
+// than the client libraries.
+func (c *Cluster) SetMinorVersion(minor string) {
+	c.mu.Lock()
+	c.minor = minor
+	c.mu.Unlock()
+}
+
+// DenyEvents makes access reviews deny reading events in namespace. Only reviews are
+// affected, not the requests themselves.
+func (c *Cluster) DenyEvents(namespace string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.deniedEvents == nil {
+		c.deniedEvents = make(map[string]bool)
+	}
+	c.deniedEvents[namespace] = true
+}
+
+// AddPod serves pod, e.g. for drill-downs, until the test ends.
+func (c *Cluster) AddPod(pod *corev1.Pod) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pods == nil {
+		c.pods = make(map[string]*corev1.Pod)
+	}
+	c.pods[pod.Namespace+"/"+pod.Name] = pod.DeepCopy()
+}
+
+// SetLogs serves logs as the output of a container of a pod added with AddPod, or of
+// its previous run when previous is set.
+func (c *Cluster) SetLogs(namespace, pod, container string, previous bool, logs string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.logs == nil {
+		c.logs = make(map[string]string)
+	}
+	c.logs[logsKey(namespace, pod, container, previous)] = logs
+	c.notifyLogsLocked()
+}
+
+// AppendLogs adds output to a container's logs, as it writes it, for clients that
+// follow them.
+func (c *Cluster) AppendLogs(namespace, pod, container, output string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.logs == nil {
+		c.logs = make(map[string]string)
+	}
+	c.logs[logsKey(namespace, pod, container, false)] += output
+	c.notifyLogsLocked()
+}
+
+func (c *Cluster) notifyLogsLocked() {
+	close(c.logsChanged)
+	c.logsChanged = make(chan struct{})
+}
+
+// LogFollowers returns how many clients are following logs.
+func (c *Cluster) LogFollowers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.logFollowers
+}
+
+func logsKey(namespace, pod, container string, previous bool) string {
+	key := namespace + "/" + pod + "/" + container
+	if previous {
+		key += "/previous"
+	}
+	return key
+}
+
+// WaitForWatch blocks until a client watches events, so events emitted afterwards are
+// delivered as changes rather than being part of the initial list.
+func (c *Cluster) WaitForWatch(t testing.TB) {
+	t.Helper()
+	c.waitForWatcher(t, "no client started watching events", func(*watcher) bool { return true })
+}
+
+// WaitForWatchSelecting is WaitForWatch for a watch with field selector selector, e.g.
+// "type=Warning", for tests that restart the watch with other filters.
+func (c *Cluster) WaitForWatchSelecting(t testing.TB, selector string) {
+	t.Helper()
+	c.waitForWatcher(t, "no client started watching events with "+selector, func(w *watcher) bool {
+		return w.selector.String() == selector
+	})
+}
+
+func (c *Cluster) waitForWatcher(t testing.TB, failure string, match func(*watcher) bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		watching := false
+		for w := range c.watchers {
+			watching = watching || match(w)
+		}
+		c.mu.Unlock()
+		if watching {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal(failure)
+}
+
+// WaitForNamespaceWatch blocks until a client watches namespaces.
+func (c *Cluster) WaitForNamespaceWatch(t testing.TB) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		watching := len(c.nsWatchers) > 0
+		c.mu.Unlock()
+		if watching {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("no client started watching namespaces")
+}
+
+// DeleteNamespace removes a namespace, as the API server does once its deletion is
+// finished. Its events are left alone.
+func (c *Cluster) DeleteNamespace(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, ns := range c.namespaces {
+		if ns == name {
+			c.namespaces = append(c.namespaces[:i], c.namespaces[i+1:]...)
+			break
+		}
+	}
+	c.rv++
+	ch := namespaceChange{rv: c.rv, kind: "DELETED", name: name}
+	c.nsHistory = append(c.nsHistory, ch)
+	for w := range c.nsWatchers {
+		w <- ch
+	}
+}
+
+// Emit creates event, filling in its UID, name and resourceVersion when unset, and
+// returns the stored copy.
+func (c *Cluster) Emit(event *eventsv1.Event) *eventsv1.Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	event = event.DeepCopy()
+	c.nextUID++
+	if event.UID == "" {
+		event.UID = types.UID(fmt.Sprintf("event-%d", c.nextUID))
+	}
+	if event.Name == "" {
+		event.Name = fmt.Sprintf("%s.%d", event.Regarding.Name, c.nextUID)
+	}
+	c.order = append(c.order, event.UID)
+	return c.storeLocked("ADDED", event)
+}
+
+// Update replaces a previously emitted event, e.g. with a higher series count.
+func (c *Cluster) Update(event *eventsv1.Event) *eventsv1.Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.storeLocked("MODIFIED", event.DeepCopy())
+}
+
+// Delete removes a previously emitted event, as the API server does when it expires.
+func (c *Cluster) Delete(event *eventsv1.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stored, ok := c.events[event.UID]
+	if !ok {
+		return
+	}
+	c.storeLocked("DELETED", stored.DeepCopy())
+	delete(c.events, event.UID)
+	for i, uid := range c.order {
+		if uid == event.UID {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (c *Cluster) storeLocked(kind string, event *eventsv1.Event) *eventsv1.Event {
+	c.rv++
+	event.ResourceVersion = strconv.Itoa(c.rv)
+	c.events[event.UID] = event
+	ch := change{rv: c.rv, kind: kind, event: event}
+	c.history = append(c.history, ch)
+	for w := range c.watchers {
+		if w.namespace == "" || w.namespace == event.Namespace {
+			w.changes <- ch
+		}
+	}
+	return event.DeepCopy()
+}
+
+// PodEvent returns an event about a Pod, reported by the kubelet now.
+func PodEvent(namespace, pod, eventType, reason, note string) *eventsv1.Event {
+	now := time.Now()
+	return &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, CreationTimestamp: metav1.NewTime(now)},
+		EventTime:  metav1.NewMicroTime(now),
+		Regarding: corev1.ObjectReference{
+			Kind:       "Pod",
+			Namespace:  namespace,
+			Name:       pod,
+			UID:        types.UID("pod-" + namespace + "-" + pod),
+			APIVersion: "v1",
+		},
+		Type:                eventType,
+		Reason:              reason,
+		Note:                note,
+		ReportingController: "kubelet",
+		ReportingInstance:   "kubelet-test",
+		Action:              reason,
+	}
+}
+
+func (c *Cluster) serveVersion(w http.ResponseWriter, _ *http.Request) {
+	c.mu.Lock()
+	minor := c.minor
+	c.mu.Unlock()
+	writeJSON(w, version.Info{Major: "1", Minor: minor, GitVersion: "v1." + minor + ".0", Platform: "linux/amd64"})
+}
+
+func (c *Cluster) servePod(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	pod, ok := c.pods[r.PathValue("namespace")+"/"+r.PathValue("name")]
+	if ok {
+		pod = pod.DeepCopy()
+	}
+	c.mu.Unlock()
+	if !ok {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, r.URL.Path+" not found")
+		return
+	}
+	pod.APIVersion, pod.Kind = "v1", "Pod"
+	writeJSON(w, pod)
+}
+
+// servePods lists the pods of a namespace added with AddPod that match the request's
+// label selector.
+func (c *Cluster) servePods(w http.ResponseWriter, r *http.Request) {
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+		return
+	}
+	list := corev1.PodList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PodList"}}
+	c.mu.Lock()
+	for _, pod := range c.pods {
+		if pod.Namespace == r.PathValue("namespace") && selector.Matches(labels.Set(pod.Labels)) {
+			list.Items = append(list.Items, *pod.DeepCopy())
+		}
+	}
+	c.mu.Unlock()
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Name < list.Items[j].Name })
+	writeJSON(w, list)
+}
+
+// serveLogs returns logs set with SetLogs and, when following, what AppendLogs adds
+// until the client goes away. Like the kubelet, it answers 400 for a container without
+// them.
+func (c *Cluster) serveLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	key := logsKey(r.PathValue("namespace"), r.PathValue("name"), query.Get("container"), query.Get("previous") == "true")
+	c.mu.Lock()
+	logs, ok := c.logs[key]
+	changed := c.logsChanged
+	if ok && query.Get("follow") == "true" {
+		c.logFollowers++
+		defer func() {
+			c.mu.Lock()
+			c.logFollowers--
+			c.mu.Unlock()
+		}()
+	} else {
+		changed = nil
+	}
+	c.mu.Unlock()
+	if !ok {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, "no logs for "+key)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprint(w, logs)
+	sent := len(logs)
+	for changed != nil {
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changed:
+		}
+		c.mu.Lock()
+		logs, changed = c.logs[key], c.logsChanged
+		c.mu.Unlock()
+		if len(logs) > sent {
+			fmt.Fprint(w, logs[sent:])
+			sent = len(logs)
+		}
+	}
+}
+
+func (c *Cluster) serveNamespaces(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("watch") == "true" {
+		c.watchNamespaces(w, r)
+		return
+	}
+	c.mu.Lock()
+	list := corev1.NamespaceList{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "NamespaceList"},
+		ListMeta: metav1.ListMeta{ResourceVersion: strconv.Itoa(c.rv)},
+	}
+	for _, name := range c.namespaces {
+		list.Items = append(list.Items, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	c.mu.Unlock()
+	writeJSON(w, list)
+}
+
+// watchNamespaces streams namespace changes after the requested resourceVersion until
+// the client goes away or the cluster is closed.
+func (c *Cluster) watchNamespaces(w http.ResponseWriter, r *http.Request) {
+	from, _ := strconv.Atoi(r.URL.Query().Get("resourceVersion"))
+	c.mu.Lock()
+	changes := make(chan namespaceChange, 1024)
+	for _, ch := range c.nsHistory {
+		if ch.rv > from {
+			changes <- ch
+		}
+	}
+	c.nsWatchers[changes] = true
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.nsWatchers, changes)
+		c.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ch, ok := <-changes:
+			if !ok {
+				return
+			}
+			namespace := corev1.Namespace{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+				ObjectMeta: metav1.ObjectMeta{Name: ch.name, ResourceVersion: strconv.Itoa(ch.rv)},
+			}
+			if err := enc.Encode(map[string]any{"type": ch.kind, "object": namespace}); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func (c *Cluster) serveEvents(w http.ResponseWriter, r *http.Request) {
+	namespace := r.PathValue("namespace")
+	selector, err := fields.ParseSelector(r.URL.Query().Get("fieldSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+		return
+	}
+	if r.URL.Query().Get("watch") == "true" {
+		c.watchEvents(w, r, namespace, selector)
+		return
+	}
+	c.mu.Lock()
+	list := eventsv1.EventList{
+		TypeMeta: metav1.TypeMeta{APIVersion: "events.k8s.io/v1", Kind: "EventList"},
+		ListMeta: metav1.ListMeta{ResourceVersion: strconv.Itoa(c.rv)},
+	}
+	for _, uid := range c.order {
+		event := c.events[uid]
+		if (namespace == "" || event.Namespace == namespace) && selector.Matches(eventFields(event)) {
+			list.Items = append(list.Items, *withTypeMeta(event))
+		}
+	}
+	c.mu.Unlock()
+	writeJSON(w, list)
+}
+
+// ServeCoreEvents also serves the events as core/v1 events, so drill-downs list the
+// events of the object and of its owner chain. Without it they show only the event
+// the drill-down was opened on.
+func (c *Cluster) ServeCoreEvents() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.coreEvents = true
+}
+
+// serveCoreEvents lists the events of a namespace as core/v1 events that match the
+// request's field selector on involvedObject.kind and involvedObject.name.
+func (c *Cluster) serveCoreEvents(w http.ResponseWriter, r *http.Request) {
+	selector, err := fields.ParseSelector(r.URL.Query().Get("fieldSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+		return
+	}
+	list := corev1.EventList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"}}
+	c.mu.Lock()
+	if !c.coreEvents {
+		c.mu.Unlock()
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, r.URL.Path+" not found")
+		return
+	}
+	for _, uid := range c.order {
+		event := c.events[uid]
+		if event.Namespace != r.PathValue("namespace") || !selector.Matches(fields.Set{
+			"involvedObject.kind": event.Regarding.Kind,
+			"involvedObject.name": event.Regarding.Name,
+		}) {
+			continue
+		}
+		list.Items = append(list.Items, corev1.Event{
+			ObjectMeta:     *event.ObjectMeta.DeepCopy(),
+			InvolvedObject: event.Regarding,
+			Reason:         event.Reason,
+			Message:        event.Note,
+			Type:           event.Type,
+			EventTime:      event.EventTime,
+		})
+	}
+	c.mu.Unlock()
+	writeJSON(w, list)
+}
+
+// watchEvents streams the changes after the requested resourceVersion that match
+// selector until the client goes away or the cluster is closed.
+func (c *Cluster) watchEvents(w http.ResponseWriter, r *http.Request, namespace string, selector fields.Selector) {
+	from, _ := strconv.Atoi(r.URL.Query().Get("resourceVersion"))
+	c.mu.Lock()
+	watch := &watcher{namespace: namespace, selector: selector, changes: make(chan change, 1024)}
+	for _, ch := range c.history {
+		if ch.rv > from && (namespace == "" || ch.event.Namespace == namespace) {
+			watch.changes <- ch
+		}
+	}
+	c.watchers[watch] = true
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.watchers, watch)
+		c.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ch, ok := <-watch.changes:
+			if !ok {
+				return
+			}
+			if !selector.Matches(eventFields(ch.event)) {
+				continue
+			}
+			if err := enc.Encode(map[string]any{"type": ch.kind, "object": withTypeMeta(ch.event)}); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// eventFields are the fields of event field selectors of events.k8s.io/v1 lists and
+// watches can match.
+func eventFields(event *eventsv1.Event) fields.Set {
+	return fields.Set{
+		"type":                event.Type,
+		"reason":              event.Reason,
+		"regarding.kind":      event.Regarding.Kind,
+		"regarding.name":      event.Regarding.Name,
+		"reportingController": event.ReportingController,
+	}
+}
+
+func withTypeMeta(event *eventsv1.Event) *eventsv1.Event {
+	event = event.DeepCopy()
+	event.APIVersion, event.Kind = "events.k8s.io/v1", "Event"
+	return event
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeStatus(w http.ResponseWriter, code int, reason metav1.StatusReason, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(metav1.Status{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Status"},
+		Status:   metav1.StatusFailure,
+		Code:     int32(code),
+		Reason:   reason,
+		Message:  message,
+	})
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/a0xAi/kubeve/config"
+	"github.com/a0xAi/kubeve/ui"
+)
+
+// runKeys prints the effective keymap after the config file's overrides, and the keys
+// that clash with each other or with a terminal multiplexer.
+func runKeys(args []string) {
+	fs := flag.NewFlagSet("keys", flag.ExitOnError)
+	fs.Parse(args)
+
+	keys, errs := ui.NewKeymap(config.Load().Keys)
+	rows := [][]string{{"ACTION", "KEYS", "SOURCE", "DESCRIPTION"}}
+	for _, binding := range keys.Bindings() {
+		source := "default"
+		switch {
+		case binding.Fixed:
+			source = "fixed"
+		case binding.Configured:
+			source = "config"
+		}
+		bound := strings.Join(binding.Keys, " ")
+		if bound == "" {
+			bound = "-"
+		}
+		rows = append(rows, []string{binding.Action, bound, source, binding.Description})
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	for _, row := range rows {
+		fmt.Printf("%-*s  %-*s  %-*s  %s\n", widths[0], row[0], widths[1], row[1], widths[2], row[2], row[3])
+	}
+
+	if conflicts := keys.Conflicts(); len(conflicts) > 0 {
+		fmt.Println("\nConflicts:")
+		for _, conflict := range conflicts {
+			with := conflict.Actions
+			if conflict.Multiplexer != "" {
+				with = append(with, conflict.Multiplexer)
+			}
+			fmt.Printf("  %-10s %s\n", conflict.Key, strings.Join(with, ", "))
+		}
+	}
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const execPluginTimeout = 30 * time.Second
+
+// ExecPluginOutput runs the current context's exec credential plugin the same way client-go
+// does and returns its stderr, which client-go otherwise discards behind the TUI. ok is false
+// when the context does not use an exec plugin.
+func ExecPluginOutput(ctx context.Context) (command string, output string, ok bool, err error) {
+	rawCfg, err := loadClientConfig().RawConfig()
+	if err != nil {
+		return "", "", false, err
+	}
+	ctxCfg, found := rawCfg.Contexts[rawCfg.CurrentContext]
+	if !found || ctxCfg == nil {
+		return "", "", false, nil
+	}
+	authInfo, found := rawCfg.AuthInfos[ctxCfg.AuthInfo]
+	if !found || authInfo == nil || authInfo.Exec == nil {
+		return "", "", false, nil
+	}
+
+	execCfg := authInfo.Exec
+	runCtx, cancel := context.WithTimeout(ctx, execPluginTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(runCtx, execCfg.Command, execCfg.Args...)
+	cmd.Env = os.Environ()
+	for _, env := range execCfg.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	cmd.Env = append(cmd.Env, fmt.Sprintf(
+		`KUBERNETES_EXEC_INFO={"apiVersion":%q,"kind":"ExecCredential","spec":{"interactive":false}}`,
+		execCfg.APIVersion,
+	))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	cmd.Stdout = nil
+
+	command = filepath.Base(execCfg.Command)
+	runErr := cmd.Run()
+	return command, strings.TrimSpace(stderr.String()), true, runErr
+}
@@ -0,0 +1,324 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// CheckStatus is the outcome of a single diagnostic check.
+type CheckStatus string
+
+const (
+	CheckOK      CheckStatus = "ok"
+	CheckWarn    CheckStatus = "warn"
+	CheckFail    CheckStatus = "fail"
+	CheckSkipped CheckStatus = "skipped"
+)
+
+// Check is one diagnostic result with an optional remediation hint.
+type Check struct {
+	Name   string
+	Status CheckStatus
+	Detail string
+	Remedy string
+}
+
+const doctorTimeout = 10 * time.Second
+
+// Diagnose runs connectivity checks in order: kubeconfig, credentials, API reachability,
+// events and drill-down RBAC and metrics-server. Checks depending on a failed step are skipped.
+func Diagnose(ctx context.Context, namespace string) []Check {
+	var checks []Check
+	skipRest := func(names ...string) []Check {
+		for _, name := range names {
+			checks = append(checks, Check{Name: name, Status: CheckSkipped, Detail: "previous check failed"})
+		}
+		return checks
+	}
+
+	clientConfig := loadClientConfig()
+	rawCfg, err := clientConfig.RawConfig()
+	if err != nil {
+		checks = append(checks, Check{
+			Name:   "kubeconfig",
+			Status: CheckFail,
+			Detail: err.Error(),
+			Remedy: "Fix the kubeconfig syntax or point KUBECONFIG at a valid file.",
+		})
+		return skipRest("credentials", "api server", "events access", "drill-down access", "metrics-server")
+	}
+	kubeconfigCheck, authInfo := checkKubeconfig(rawCfg)
+	checks = append(checks, kubeconfigCheck)
+	if kubeconfigCheck.Status == CheckFail {
+		return skipRest("credentials", "api server", "events access", "drill-down access", "metrics-server")
+	}
+
+	restCfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		checks = append(checks, Check{
+			Name:   "credentials",
+			Status: CheckFail,
+			Detail: err.Error(),
+			Remedy: credentialRemedy(authInfo),
+		})
+		return skipRest("api server", "events access", "drill-down access", "metrics-server")
+	}
+	restCfg.Timeout = doctorTimeout
+	clientset, err := newClientset(restCfg)
+	if err != nil {
+		checks = append(checks, Check{Name: "credentials", Status: CheckFail, Detail: err.Error()})
+		return skipRest("api server", "events access", "drill-down access", "metrics-server")
+	}
+
+	version, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		checks = append(checks, classifyConnectError(err, authInfo)...)
+		return skipRest("events access", "drill-down access", "metrics-server")
+	}
+	checks = append(checks, credentialCheck(authInfo))
+	checks = append(checks, Check{
+		Name:   "api server",
+		Status: CheckOK,
+		Detail: fmt.Sprintf("%s reachable, Kubernetes %s", restCfg.Host, version.GitVersion),
+	})
+
+	checks = append(checks, checkEventsAccess(ctx, clientset, namespace))
+	checks = append(checks, checkDrillDownAccess(ctx, clientset, namespace))
+	checks = append(checks, checkMetricsServer(ctx, clientset, namespace))
+	return checks
+}
+
+func checkKubeconfig(rawCfg clientcmdapi.Config) (Check, *clientcmdapi.AuthInfo) {
+	check := Check{Name: "kubeconfig"}
+	if rawCfg.CurrentContext == "" {
+		if len(rawCfg.Contexts) == 0 {
+			check.Status = CheckWarn
+			check.Detail = "no kubeconfig contexts found, relying on in-cluster configuration"
+			return check, nil
+		}
+		check.Status = CheckFail
+		check.Detail = "no current-context set"
+		check.Remedy = "Select a context with: kubectl config use-context <name>"
+		return check, nil
+	}
+	ctxCfg, ok := rawCfg.Contexts[rawCfg.CurrentContext]
+	if !ok || ctxCfg == nil {
+		check.Status = CheckFail
+		check.Detail = fmt.Sprintf("current-context %q does not exist", rawCfg.CurrentContext)
+		check.Remedy = "List contexts with kubectl config get-contexts and pick an existing one."
+		return check, nil
+	}
+	if _, ok := rawCfg.Clusters[ctxCfg.Cluster]; !ok {
+		check.Status = CheckFail
+		check.Detail = fmt.Sprintf("context %q references unknown cluster %q", rawCfg.CurrentContext, ctxCfg.Cluster)
+		check.Remedy = "Re-create the cluster entry, e.g. with your provider's get-credentials command."
+		return check, nil
+	}
+	authInfo, ok := rawCfg.AuthInfos[ctxCfg.AuthInfo]
+	if !ok {
+		check.Status = CheckFail
+		check.Detail = fmt.Sprintf("context %q references unknown user %q", rawCfg.CurrentContext, ctxCfg.AuthInfo)
+		check.Remedy = "Re-create the user entry, e.g. with your provider's get-credentials command."
+		return check, nil
+	}
+	check.Status = CheckOK
+	check.Detail = fmt.Sprintf("context %q (cluster %q, user %q)", rawCfg.CurrentContext, ctxCfg.Cluster, ctxCfg.AuthInfo)
+	return check, authInfo
+}
+
+func credentialCheck(authInfo *clientcmdapi.AuthInfo) Check {
+	check := Check{Name: "credentials", Status: CheckOK, Detail: "accepted by the API server"}
+	if authInfo != nil && authInfo.Exec != nil {
+		check.Detail = fmt.Sprintf("exec plugin %q returned valid credentials", filepath.Base(authInfo.Exec.Command))
+	}
+	return check
+}
+
+// classifyConnectError splits a failed discovery call into a credential or reachability failure.
+func classifyConnectError(err error, authInfo *clientcmdapi.AuthInfo) []Check {
+	msg := err.Error()
+	switch {
+	case apierrors.IsUnauthorized(err), strings.Contains(msg, "getting credentials"), strings.Contains(msg, "exec plugin"):
+		return []Check{
+			{Name: "credentials", Status: CheckFail, Detail: msg, Remedy: credentialRemedy(authInfo)},
+			{Name: "api server", Status: CheckSkipped, Detail: "previous check failed"},
+		}
+	case strings.Contains(msg, "x509"), strings.Contains(msg, "certificate"):
+		return []Check{
+			{Name: "credentials", Status: CheckSkipped, Detail: "API server not reachable"},
+			{Name: "api server", Status: CheckFail, Detail: msg, Remedy: "The server certificate is not trusted. Check certificate-authority-data in your kubeconfig or your proxy's CA."},
+		}
+	default:
+		return []Check{
+			{Name: "credentials", Status: CheckSkipped, Detail: "API server not reachable"},
+			{Name: "api server", Status: CheckFail, Detail: msg, Remedy: "Check VPN/proxy connectivity and that the cluster endpoint in your kubeconfig is correct."},
+		}
+	}
+}
+
+// credentialRemedy suggests the login command for well-known exec credential plugins.
+func credentialRemedy(authInfo *clientcmdapi.AuthInfo) string {
+	if authInfo == nil || authInfo.Exec == nil {
+		return "Refresh the credentials for this context, e.g. with your provider's get-credentials command."
+	}
+	command := filepath.Base(authInfo.Exec.Command)
+	switch command {
+	case "aws", "aws-iam-authenticator":
+		return "AWS credentials look expired. Run: aws sso login (add --profile if your kubeconfig sets AWS_PROFILE)."
+	case "gke-gcloud-auth-plugin", "gcloud":
+		return "Google credentials look expired. Run: gcloud auth login"
+	case "kubelogin":
+		return "Azure credentials look expired. Run: az login (or kubelogin convert-kubeconfig for non-interactive logins)."
+	case "oidc-login", "kubectl-oidc_login":
+		return "OIDC token looks expired. Run any kubectl command to re-open the browser login."
+	default:
+		return fmt.Sprintf("The exec credential plugin %q failed. Run it manually to see its error and re-authenticate.", command)
+	}
+}
+
+func checkEventsAccess(ctx context.Context, clientset *kubernetes.Clientset, namespace string) Check {
+	scope := namespace
+	if scope == "" {
+		scope = "all namespaces"
+	}
+	check := Check{Name: "events access"}
+	var denied []string
+	for _, verb := range []string{"list", "watch"} {
+		reqCtx, cancel := context.WithTimeout(ctx, doctorTimeout)
+		review, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(reqCtx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      verb,
+					Resource:  "events",
+				},
+			},
+		}, metav1.CreateOptions{})
+		cancel()
+		if err != nil {
+			check.Status = CheckWarn
+			check.Detail = fmt.Sprintf("could not verify access: %v", err)
+			return check
+		}
+		if !review.Status.Allowed {
+			denied = append(denied, verb)
+		}
+	}
+	if len(denied) > 0 {
+		check.Status = CheckFail
+		check.Detail = fmt.Sprintf("not allowed to %s events in %s", strings.Join(denied, "/"), scope)
+		check.Remedy = "Ask a cluster admin to apply the output of: kubeve rbac -features events"
+		if namespace == "" {
+			check.Remedy += " (or start kubeve with -n <namespace> you can read)"
+		}
+		return check
+	}
+	check.Status = CheckOK
+	check.Detail = fmt.Sprintf("list/watch events allowed in %s", scope)
+	return check
+}
+
+// drillDownAccess lists what drill-downs read; vclusters and aggregated endpoints often
+// allow events but not all of these.
+var drillDownAccess = []authorizationv1.ResourceAttributes{
+	{Verb: "list", Resource: "pods"},
+	{Verb: "get", Resource: "pods", Subresource: "log"},
+	{Verb: "list", Group: "apps", Resource: "replicasets"},
+	{Verb: "list", Group: "batch", Resource: "jobs"},
+	{Verb: "get", Resource: "nodes"},
+	{Verb: "list", Resource: "namespaces"},
+}
+
+func checkDrillDownAccess(ctx context.Context, clientset *kubernetes.Clientset, namespace string) Check {
+	check := Check{Name: "drill-down access"}
+	var denied []string
+	for _, attrs := range drillDownAccess {
+		if attrs.Resource != "nodes" && attrs.Resource != "namespaces" {
+			attrs.Namespace = namespace
+		}
+		allowed, err := accessAllowed(ctx, clientset, attrs)
+		if err != nil {
+			check.Status = CheckWarn
+			check.Detail = fmt.Sprintf("could not verify access: %v", err)
+			return check
+		}
+		if !allowed {
+			resource := attrs.Resource
+			if attrs.Subresource != "" {
+				resource += "/" + attrs.Subresource
+			}
+			denied = append(denied, attrs.Verb+" "+resource)
+		}
+	}
+	if len(denied) > 0 {
+		check.Status = CheckWarn
+		check.Detail = "not allowed: " + strings.Join(denied, ", ")
+		check.Remedy = "Events still stream; drill-downs show less. For full access apply: kubeve rbac -features events,namespaces,drilldown,logs (add secrets to also drill into Secrets and TLS certificates)"
+		return check
+	}
+	check.Status = CheckOK
+	check.Detail = "describe, related resources and logs available"
+	return check
+}
+
+// accessAllowed asks the API server whether the current user may act on attrs.
+func accessAllowed(ctx context.Context, clientset *kubernetes.Clientset, attrs authorizationv1.ResourceAttributes) (bool, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+	review, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(reqCtx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attrs},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// metricsAccess lists the metrics drill-downs read: pod usage in the namespace and node usage.
+var metricsAccess = []authorizationv1.ResourceAttributes{
+	{Verb: "get", Group: "metrics.k8s.io", Resource: "pods"},
+	{Verb: "get", Group: "metrics.k8s.io", Resource: "nodes"},
+}
+
+func checkMetricsServer(ctx context.Context, clientset *kubernetes.Clientset, namespace string) Check {
+	check := Check{Name: "metrics-server"}
+	if _, err := clientset.Discovery().ServerResourcesForGroupVersion("metrics.k8s.io/v1beta1"); err != nil {
+		check.Status = CheckWarn
+		check.Detail = "metrics.k8s.io API not available"
+		check.Remedy = "Optional: install metrics-server to see CPU/memory usage in drill-downs."
+		return check
+	}
+	var denied []string
+	for _, attrs := range metricsAccess {
+		if attrs.Resource == "pods" {
+			attrs.Namespace = namespace
+		}
+		allowed, err := accessAllowed(ctx, clientset, attrs)
+		if err != nil {
+			check.Status = CheckWarn
+			check.Detail = fmt.Sprintf("metrics.k8s.io/v1beta1 available, could not verify access: %v", err)
+			return check
+		}
+		if !allowed {
+			denied = append(denied, attrs.Verb+" "+attrs.Resource+"."+attrs.Group)
+		}
+	}
+	if len(denied) > 0 {
+		check.Status = CheckWarn
+		check.Detail = "metrics.k8s.io/v1beta1 available but not allowed: " + strings.Join(denied, ", ")
+		check.Remedy = "Drill-downs leave out CPU/memory usage. For access apply: kubeve rbac -features drilldown"
+		return check
+	}
+	check.Status = CheckOK
+	check.Detail = "metrics.k8s.io/v1beta1 available and readable"
+	return check
+}
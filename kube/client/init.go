@@ -0,0 +1,235 @@
+// Package client builds Kubernetes clients from the kubeconfig, the way kubectl does,
+// and checks that a cluster can be reached and its events read.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// currentContext replaces the kubeconfig's current context when set.
+var currentContext string
+
+// SetContext makes the client helpers use a kubeconfig context other than the current
+// one; "" restores the current context.
+func SetContext(name string) {
+	currentContext = name
+}
+
+// CurrentContext returns the name of the kubeconfig context the client helpers use, ""
+// when the kubeconfig has none, e.g. in-cluster.
+func CurrentContext() (string, error) {
+	if currentContext != "" {
+		return currentContext, nil
+	}
+	rawCfg, err := loadClientConfig().RawConfig()
+	if err != nil {
+		return "", err
+	}
+	return rawCfg.CurrentContext, nil
+}
+
+// Kinit sets up the Kubernetes client and returns the namespace, raw kubeconfig, clientset, and namespace list.
+func Kinit(overrideNamespace string) (string, clientcmdapi.Config, *kubernetes.Clientset, []string, error) {
+	clientConfig := loadClientConfig()
+
+	// Determine namespace: override or default
+	ns := overrideNamespace
+	if ns == "" {
+		var err error
+		ns, _, err = clientConfig.Namespace()
+		if err != nil {
+			ns = metav1.NamespaceDefault
+		}
+	}
+
+	// Load raw config
+	rawCfg, err := clientConfig.RawConfig()
+	if err != nil {
+		return "", clientcmdapi.Config{}, nil, nil, err
+	}
+	if currentContext != "" {
+		rawCfg.CurrentContext = currentContext
+	}
+
+	// Falls back to the in-cluster service account when no kubeconfig is present
+	restCfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		return "", rawCfg, nil, nil, err
+	}
+	clientset, err := newClientset(restCfg)
+	if err != nil {
+		return "", rawCfg, nil, nil, err
+	}
+
+	// The namespace list is optional; without it namespaces are switched to as typed.
+	ctx, cancel := context.WithTimeout(context.Background(), StartupTimeout())
+	defer cancel()
+	nsList, _ := NamespaceNames(ctx, clientset)
+
+	return ns, rawCfg, clientset, nsList, nil
+}
+
+// NamespaceNames lists the names of the cluster's namespaces.
+func NamespaceNames(ctx context.Context, clientset *kubernetes.Clientset) ([]string, error) {
+	nsItems, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(nsItems.Items))
+	for _, item := range nsItems.Items {
+		names = append(names, item.Name)
+	}
+	return names, nil
+}
+
+// ContextClient returns a clientset for a kubeconfig context, or for the current
+// context when kubeContext is empty.
+func ContextClient(kubeContext string) (*kubernetes.Clientset, error) {
+	restCfg, err := loadClientConfigFor(kubeContext).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return newClientset(restCfg)
+}
+
+// newClientset creates a clientset with the client rate limits and content type from
+// the connection options.
+func newClientset(restCfg *rest.Config) (*kubernetes.Clientset, error) {
+	restCfg = rest.CopyConfig(restCfg)
+	if connection.QPS > 0 {
+		restCfg.QPS = connection.QPS
+	}
+	if connection.Burst > 0 {
+		restCfg.Burst = connection.Burst
+	}
+	if !connection.DisableProtobuf {
+		// Built-in types, events among them, are much smaller as protobuf. JSON stays
+		// acceptable for servers and proxies that do not speak it.
+		restCfg.ContentType = runtime.ContentTypeProtobuf
+		restCfg.AcceptContentTypes = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
+	}
+	clientset, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return nil, err
+	}
+	clientConfigs.Store(clientset, restCfg)
+	return clientset, nil
+}
+
+// clientConfigs maps each clientset from newClientset to its config, so dynamic clients
+// for the same cluster can be derived from it.
+var clientConfigs sync.Map
+
+// Dynamic returns a dynamic client for the cluster of clientset, which must have been
+// created by this package.
+func Dynamic(clientset *kubernetes.Clientset) (*dynamic.DynamicClient, error) {
+	restCfg, ok := clientConfigs.Load(clientset)
+	if !ok {
+		return nil, errors.New("no client configuration for this cluster")
+	}
+	return newDynamicClient(restCfg.(*rest.Config))
+}
+
+// newDynamicClient creates a dynamic client with the client rate limits from the
+// connection options. It always speaks JSON, which keeps fields no typed struct knows.
+func newDynamicClient(restCfg *rest.Config) (*dynamic.DynamicClient, error) {
+	restCfg = rest.CopyConfig(restCfg)
+	if connection.QPS > 0 {
+		restCfg.QPS = connection.QPS
+	}
+	if connection.Burst > 0 {
+		restCfg.Burst = connection.Burst
+	}
+	return dynamic.NewForConfig(restCfg)
+}
+
+// loadClientConfig returns the deferred kubeconfig loader shared by the client helpers.
+func loadClientConfig() clientcmd.ClientConfig {
+	return loadClientConfigFor("")
+}
+
+// loadClientConfigFor is loadClientConfig for a kubeconfig context, the current one when empty.
+func loadClientConfigFor(kubeContext string) clientcmd.ClientConfig {
+	// Respect KUBECONFIG env var if set, else fallback to default
+	kubeconfigEnv := os.Getenv("KUBECONFIG")
+	// Load kubeconfig rules and overrides
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfigEnv != "" {
+		rules.ExplicitPath = kubeconfigEnv
+	}
+	if kubeContext == "" {
+		kubeContext = currentContext
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+	overrides.ClusterInfo.ProxyURL = connection.ProxyURL
+	overrides.ClusterInfo.CertificateAuthority = connection.CertificateAuthority
+	overrides.ClusterInfo.InsecureSkipTLSVerify = connection.InsecureSkipTLSVerify
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+}
+
+// ConnectionOptions override how the API server is reached, on top of the kubeconfig.
+// HTTPS_PROXY/NO_PROXY from the environment are honored unless ProxyURL is set.
+type ConnectionOptions struct {
+	ProxyURL              string
+	CertificateAuthority  string
+	InsecureSkipTLSVerify bool
+	// QPS and Burst raise client-go's request rate limits of 5 and 10 when set.
+	QPS   float32
+	Burst int
+	// DisableProtobuf makes requests in JSON only.
+	DisableProtobuf bool
+	// StartupTimeout bounds the requests made at start; 0 is defaultStartupTimeout.
+	StartupTimeout time.Duration
+}
+
+// defaultStartupTimeout is how long start-up requests may take unless configured.
+const defaultStartupTimeout = 10 * time.Second
+
+var connection ConnectionOptions
+
+// SetConnectionOptions applies connection overrides to every client created afterwards.
+func SetConnectionOptions(opts ConnectionOptions) {
+	connection = opts
+}
+
+// StartupTimeout returns how long the requests made at start may take.
+func StartupTimeout() time.Duration {
+	if connection.StartupTimeout > 0 {
+		return connection.StartupTimeout
+	}
+	return defaultStartupTimeout
+}
+
+// ServerVersion asks the API server for its version. Unlike the discovery client's
+// ServerVersion it gives up when ctx is done.
+func ServerVersion(ctx context.Context, clientset *kubernetes.Clientset) (*version.Info, error) {
+	body, err := clientset.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return nil, err
+	}
+	var info version.Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("parse server version: %w", err)
+	}
+	return &info, nil
+}
+
+// InsecureTLS reports whether server certificate verification has been disabled.
+func InsecureTLS() bool {
+	return connection.InsecureSkipTLSVerify
+}
@@ -0,0 +1,169 @@
+package drilldown
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// Adapter drills into the objects of one kind. Failures are reported in the returned
+// text, like the built-in adapters do, rather than returned. namespace is "" for
+// cluster-scoped kinds.
+type Adapter interface {
+	// Describe renders the object like kubectl describe.
+	Describe(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string
+	// Related lists the objects related to it, split into pages when they are many,
+	// and names the pod whose logs and last termination the drill-down shows, or "".
+	Related(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (pages []string, logPod string)
+	// Logs returns the object's recent logs given the pod Related named, or "" when
+	// it has none.
+	Logs(ctx context.Context, clientset *kubernetes.Clientset, namespace, name, logPod string) string
+}
+
+// ClusterScoped is implemented by adapters of kinds whose objects have no namespace,
+// such as Nodes. Objects of other kinds without a namespace are looked up in "default".
+type ClusterScoped interface {
+	ClusterScoped() bool
+}
+
+// Diagnoser is implemented by adapters that explain why an object of their kind is
+// failing, such as a Job's failed pods. The drill-down shows it under Diagnosis.
+type Diagnoser interface {
+	// Diagnose returns "" when there is nothing to explain.
+	Diagnose(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string
+}
+
+var (
+	adaptersMu sync.RWMutex
+	// adapters holds the adapter of each kind and alias, by lower-case name.
+	adapters = map[string]Adapter{}
+)
+
+// Register makes adapter drill into objects of kind, also when events name the kind
+// by one of aliases, e.g. "hpa" for HorizontalPodAutoscaler. Kinds match regardless of
+// case. It replaces an earlier adapter of the kind, built-in ones included. Kinds
+// without an adapter are read through the dynamic client.
+func Register(adapter Adapter, kind string, aliases ...string) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+	for _, name := range append([]string{kind}, aliases...) {
+		adapters[strings.ToLower(strings.TrimSpace(name))] = adapter
+	}
+}
+
+// adapterFor returns the adapter of a lower-case kind or alias.
+func adapterFor(kind string) (Adapter, bool) {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+	adapter, ok := adapters[kind]
+	return adapter, ok
+}
+
+// builtinAdapter is an Adapter made of a built-in kind's describe and related functions.
+type builtinAdapter struct {
+	kind          string
+	clusterScoped bool
+	describe      func(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string
+	related       func(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) ([]string, string)
+	diagnose      describeFunc
+}
+
+func (a builtinAdapter) Describe(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	return a.describe(ctx, clientset, namespace, name)
+}
+
+func (a builtinAdapter) Related(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) ([]string, string) {
+	return a.related(ctx, clientset, namespace, name)
+}
+
+// Logs merges the logs of all pods of a workload, or reads those of logPod.
+func (a builtinAdapter) Logs(ctx context.Context, clientset *kubernetes.Clientset, namespace, name, logPod string) string {
+	if logPod == "" {
+		return ""
+	}
+	return recentLogs(ctx, clientset, namespace, a.kind, name, logPod)
+}
+
+func (a builtinAdapter) ClusterScoped() bool {
+	return a.clusterScoped
+}
+
+func (a builtinAdapter) Diagnose(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	if a.diagnose == nil {
+		return ""
+	}
+	return a.diagnose(ctx, clientset, namespace, name)
+}
+
+// diagnosedBy returns a with diagnose as its failure analysis.
+func (a builtinAdapter) diagnosedBy(diagnose describeFunc) builtinAdapter {
+	a.diagnose = diagnose
+	return a
+}
+
+// registerBuiltin registers a built-in adapter under its kind and aliases.
+func registerBuiltin(adapter builtinAdapter, aliases ...string) {
+	Register(adapter, adapter.kind, aliases...)
+}
+
+type (
+	describeFunc        = func(context.Context, *kubernetes.Clientset, string, string) string
+	relatedFunc         = func(context.Context, *kubernetes.Clientset, string, string) (string, string)
+	clusterDescribeFunc = func(context.Context, *kubernetes.Clientset, string) string
+	clusterRelatedFunc  = func(context.Context, *kubernetes.Clientset, string) string
+	clusterPagesFunc    = func(context.Context, *kubernetes.Clientset, string) []string
+)
+
+// namespacedAdapter adapts the describe and related functions of a namespaced kind.
+func namespacedAdapter(kind string, describe describeFunc, related relatedFunc) builtinAdapter {
+	return builtinAdapter{
+		kind:     kind,
+		describe: describe,
+		related: func(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) ([]string, string) {
+			text, logPod := related(ctx, clientset, namespace, name)
+			return []string{text}, logPod
+		},
+	}
+}
+
+// clusterAdapter adapts the describe and related functions of a cluster-scoped kind.
+func clusterAdapter(kind string, describe clusterDescribeFunc, related clusterPagesFunc) builtinAdapter {
+	return builtinAdapter{
+		kind:          kind,
+		clusterScoped: true,
+		describe: func(ctx context.Context, clientset *kubernetes.Clientset, _, name string) string {
+			return describe(ctx, clientset, name)
+		},
+		related: func(ctx context.Context, clientset *kubernetes.Clientset, _, name string) ([]string, string) {
+			return related(ctx, clientset, name), ""
+		},
+	}
+}
+
+// onePage returns the related text of a cluster-scoped kind as a single page.
+func onePage(related clusterRelatedFunc) clusterPagesFunc {
+	return func(ctx context.Context, clientset *kubernetes.Clientset, name string) []string {
+		return []string{related(ctx, clientset, name)}
+	}
+}
+
+func init() {
+	registerBuiltin(namespacedAdapter("pod", describePod, relatedForPod))
+	registerBuiltin(namespacedAdapter("deployment", describeDeployment, relatedForDeployment))
+	registerBuiltin(namespacedAdapter("replicaset", describeReplicaSet, relatedForReplicaSet))
+	registerBuiltin(namespacedAdapter("statefulset", describeStatefulSet, relatedForStatefulSet))
+	registerBuiltin(namespacedAdapter("daemonset", describeDaemonSet, relatedForDaemonSet))
+	registerBuiltin(namespacedAdapter("job", describeJob, relatedForJob).diagnosedBy(diagnoseJob))
+	registerBuiltin(namespacedAdapter("cronjob", describeCronJob, relatedForCronJob))
+	registerBuiltin(namespacedAdapter("service", describeService, relatedForService))
+	registerBuiltin(namespacedAdapter("horizontalpodautoscaler", describeHPA, relatedForHPA).diagnosedBy(diagnoseHPA), "hpa")
+	registerBuiltin(namespacedAdapter("ingress", describeIngress, relatedForIngress), "ingresses", "ing")
+	registerBuiltin(namespacedAdapter("configmap", describeConfigMap, relatedForConfigMap), "cm")
+	registerBuiltin(namespacedAdapter("secret", describeSecret, relatedForSecret))
+	registerBuiltin(namespacedAdapter("persistentvolumeclaim", describePVC, relatedForPVC).diagnosedBy(diagnosePVC), "pvc")
+	registerBuiltin(clusterAdapter("persistentvolume", describePV, onePage(relatedForPV)), "pv")
+	registerBuiltin(clusterAdapter("namespace", describeNamespace, onePage(relatedForNamespace)), "ns")
+	registerBuiltin(clusterAdapter("node", describeNode, relatedForNode))
+}
@@ -0,0 +1,165 @@
+package drilldown
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/internal/format"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// certExpiryWarning is how close to notAfter a certificate is flagged as expiring soon.
+const certExpiryWarning = 30 * 24 * time.Hour
+
+var (
+	tlsMessage    = regexp.MustCompile(`(?i)(x509|tls|certificate)`)
+	secretMessage = regexp.MustCompile(`(?i)secrets? (?:"([^"/]+)"|([a-z0-9][a-z0-9.-]*)/([a-z0-9][a-z0-9.-]*))`)
+)
+
+// certificateKeys are the Secret keys that hold PEM certificates.
+var certificateKeys = []string{corev1.TLSCertKey, "ca.crt"}
+
+// diagnoseCertificates inspects the certificates of the Secrets a TLS error likely refers
+// to: those named in the message, or else the TLS secrets of the Ingress or Pod involved.
+func diagnoseCertificates(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name, message string) string {
+	if !tlsMessage.MatchString(message) {
+		return ""
+	}
+	secrets := referencedSecrets(ctx, clientset, namespace, kind, name, message)
+	if len(secrets) == 0 {
+		return ""
+	}
+	lines := []string{"TLS certificates"}
+	now := time.Now()
+	for _, ref := range secrets {
+		secret, err := clientset.CoreV1().Secrets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("Secret %s/%s: %s", ref.Namespace, ref.Name, failure("load secret", err)))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("Secret %s/%s", secret.Namespace, secret.Name))
+		found := false
+		for _, key := range certificateKeys {
+			data, ok := secret.Data[key]
+			if !ok {
+				continue
+			}
+			found = true
+			lines = append(lines, "  "+key+":")
+			lines = append(lines, certificateLines(data, now)...)
+		}
+		if !found {
+			lines = append(lines, "  no tls.crt or ca.crt key")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// referencedSecrets returns the secrets named in message or, failing that, the TLS
+// secrets used by the involved Ingress or Pod.
+func referencedSecrets(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name, message string) []types.NamespacedName {
+	seen := map[types.NamespacedName]bool{}
+	var refs []types.NamespacedName
+	add := func(ns, secret string) {
+		ref := types.NamespacedName{Namespace: ns, Name: secret}
+		if secret == "" || seen[ref] {
+			return
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	for _, match := range secretMessage.FindAllStringSubmatch(message, -1) {
+		if match[1] != "" {
+			add(namespace, match[1])
+		} else {
+			add(match[2], match[3])
+		}
+	}
+	if len(refs) > 0 {
+		return refs
+	}
+
+	switch strings.ToLower(kind) {
+	case "ingress":
+		ing, err := clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		for _, tls := range ing.Spec.TLS {
+			add(namespace, tls.SecretName)
+		}
+	case "pod":
+		pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.Secret != nil {
+				add(namespace, volume.Secret.SecretName)
+			}
+		}
+	}
+	return refs
+}
+
+// certificateLines describes each certificate of a PEM chain, flagging expired and
+// soon-expiring ones.
+func certificateLines(data []byte, now time.Time) []string {
+	var lines []string
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("    unparseable certificate: %v", err))
+			continue
+		}
+		status := "valid"
+		switch {
+		case now.After(cert.NotAfter):
+			status = "EXPIRED " + format.Ago(cert.NotAfter, now)
+		case now.Before(cert.NotBefore):
+			status = "NOT YET VALID"
+		case cert.NotAfter.Sub(now) < certExpiryWarning:
+			status = "EXPIRES in " + format.Duration(cert.NotAfter.Sub(now))
+		}
+		lines = append(lines,
+			fmt.Sprintf("    - %s (issuer %s)", cert.Subject.CommonName, cert.Issuer.CommonName),
+			fmt.Sprintf("      notAfter: %s [%s]", format.Timestamp(cert.NotAfter), status),
+		)
+		if sans := certificateSANs(cert); len(sans) > 0 {
+			lines = append(lines, "      SANs: "+strings.Join(sans, ", "))
+		}
+	}
+	if len(lines) == 0 {
+		return []string{"    no PEM certificates found"}
+	}
+	return lines
+}
+
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := append([]string(nil), cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	sort.Strings(sans)
+	return sans
+}
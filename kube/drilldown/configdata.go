@@ -0,0 +1,204 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/a0xAi/kubeve/internal/format"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func describeConfigMap(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load configmap", err)
+	}
+	sizes := make(map[string]string, len(cm.Data)+len(cm.BinaryData))
+	for key, value := range cm.Data {
+		sizes[key] = format.Bytes(int64(len(value)))
+	}
+	for key, value := range cm.BinaryData {
+		sizes[key] = "binary, " + format.Bytes(int64(len(value)))
+	}
+	lines := []string{
+		"Kind: ConfigMap",
+		fmt.Sprintf("Name: %s", cm.Name),
+		fmt.Sprintf("Namespace: %s", cm.Namespace),
+	}
+	if boolOrDefault(cm.Immutable) {
+		lines = append(lines, "Immutable: true")
+	}
+	return strings.Join(append(lines, keySizeLines(sizes)...), "\n")
+}
+
+// describeSecret lists the keys of a secret with their sizes. Values are never shown
+// here; SecretValues reads them when the user asks to reveal them.
+func describeSecret(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load secret", err)
+	}
+	sizes := make(map[string]string, len(secret.Data))
+	for key, value := range secret.Data {
+		sizes[key] = format.Bytes(int64(len(value)))
+	}
+	lines := []string{
+		"Kind: Secret",
+		fmt.Sprintf("Name: %s", secret.Name),
+		fmt.Sprintf("Namespace: %s", secret.Namespace),
+		fmt.Sprintf("Type: %s", secret.Type),
+	}
+	if account := secret.Annotations[corev1.ServiceAccountNameKey]; account != "" {
+		lines = append(lines, fmt.Sprintf("Service account: %s", account))
+	}
+	if boolOrDefault(secret.Immutable) {
+		lines = append(lines, "Immutable: true")
+	}
+	lines = append(lines, keySizeLines(sizes)...)
+	return strings.Join(append(lines, "Values: redacted"), "\n")
+}
+
+// keySizeLines renders the keys of a ConfigMap or Secret in order with their sizes.
+func keySizeLines(sizes map[string]string) []string {
+	if len(sizes) == 0 {
+		return []string{"Keys: none"}
+	}
+	keys := make([]string, 0, len(sizes))
+	for key := range sizes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := []string{fmt.Sprintf("Keys: %d", len(keys))}
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("- %s (%s)", key, sizes[key]))
+	}
+	return lines
+}
+
+// SecretValues renders every value of a secret, for the drill-down's reveal step.
+// Values that are not text are shown by their size only.
+func SecretValues(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	if clientset == nil {
+		return "Kubernetes client is not available."
+	}
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load secret", err)
+	}
+	if len(secret.Data) == 0 {
+		return "Secret has no values."
+	}
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var lines []string
+	for _, key := range keys {
+		value := secret.Data[key]
+		switch {
+		case !utf8.Valid(value) || strings.ContainsRune(string(value), 0):
+			lines = append(lines, fmt.Sprintf("%s: <binary, %s>", key, format.Bytes(int64(len(value)))))
+		case strings.Contains(string(value), "\n"):
+			lines = append(lines, key+":")
+			for _, line := range strings.Split(strings.TrimRight(string(value), "\n"), "\n") {
+				lines = append(lines, "  "+line)
+			}
+		default:
+			lines = append(lines, fmt.Sprintf("%s: %s", key, value))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func relatedForConfigMap(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	return relatedForConfigSource(ctx, clientset, namespace, "ConfigMap", name)
+}
+
+func relatedForSecret(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	return relatedForConfigSource(ctx, clientset, namespace, "Secret", name)
+}
+
+// relatedForConfigSource lists the pods that mount or reference a ConfigMap or Secret
+// and how each one uses it.
+func relatedForConfigSource(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) (string, string) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return failure("list pods", err), ""
+	}
+	var users []corev1.Pod
+	var lines []string
+	now := time.Now()
+	for _, pod := range pods.Items {
+		uses := configSourceUses(&pod, kind, name)
+		if len(uses) == 0 {
+			continue
+		}
+		users = append(users, pod)
+		if len(users) <= 8 {
+			lines = append(lines, fmt.Sprintf("- %s (%s) via %s", pod.Name, podStatusText(&pod, now), strings.Join(uses, ", ")))
+		}
+	}
+	if len(users) == 0 {
+		return fmt.Sprintf("No pods mount or reference this %s.", kind), ""
+	}
+	if len(users) > 8 {
+		lines = append(lines, fmt.Sprintf("... +%d more", len(users)-8))
+	}
+	header := fmt.Sprintf("Pods using this %s: %d", kind, len(users))
+	return header + "\n" + strings.Join(lines, "\n"), pickPodForLogs(users)
+}
+
+// configSourceUses describes how a pod uses a ConfigMap or Secret: as a volume, a
+// projected volume source, environment variables or an image pull secret.
+func configSourceUses(pod *corev1.Pod, kind, name string) []string {
+	isConfigMap := kind == "ConfigMap"
+	var uses []string
+	for _, volume := range pod.Spec.Volumes {
+		switch {
+		case isConfigMap && volume.ConfigMap != nil && volume.ConfigMap.Name == name,
+			!isConfigMap && volume.Secret != nil && volume.Secret.SecretName == name:
+			uses = append(uses, "volume "+volume.Name)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if isConfigMap && source.ConfigMap != nil && source.ConfigMap.Name == name ||
+					!isConfigMap && source.Secret != nil && source.Secret.Name == name {
+					uses = append(uses, "projected volume "+volume.Name)
+					break
+				}
+			}
+		}
+	}
+	for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+		for _, from := range container.EnvFrom {
+			if isConfigMap && from.ConfigMapRef != nil && from.ConfigMapRef.Name == name ||
+				!isConfigMap && from.SecretRef != nil && from.SecretRef.Name == name {
+				uses = append(uses, "envFrom in "+container.Name)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if isConfigMap && env.ValueFrom.ConfigMapKeyRef != nil && env.ValueFrom.ConfigMapKeyRef.Name == name ||
+				!isConfigMap && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == name {
+				uses = append(uses, fmt.Sprintf("env %s in %s", env.Name, container.Name))
+			}
+		}
+	}
+	if !isConfigMap {
+		for _, ref := range pod.Spec.ImagePullSecrets {
+			if ref.Name == name {
+				uses = append(uses, "imagePullSecrets")
+			}
+		}
+	}
+	return uses
+}
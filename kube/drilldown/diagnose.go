@@ -0,0 +1,151 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	diagnosisPodLimit = 5
+	errorLogTail      = int64(200)
+)
+
+// errorLine matches log lines that most likely carry the failure cause.
+var errorLine = regexp.MustCompile(`(?i)(error|exception|fatal|panic|failed|traceback)`)
+
+// DiagnoseMessage runs the analyses triggered by an event's message rather than its
+// object, returning "" when the message matches none of them.
+func DiagnoseMessage(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name, message string) string {
+	if clientset == nil {
+		return ""
+	}
+	var sections []string
+	if section := diagnoseWebhook(ctx, clientset, message); section != "" {
+		sections = append(sections, section)
+	}
+	if section := diagnoseCertificates(ctx, clientset, namespace, kind, name, message); section != "" {
+		sections = append(sections, section)
+	}
+	if section := diagnoseQuota(ctx, clientset, namespace, message); section != "" {
+		sections = append(sections, section)
+	}
+	if section := diagnosePreemption(ctx, clientset, namespace, kind, name, message); section != "" {
+		sections = append(sections, section)
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// diagnoseJob explains a failing Job: its failure conditions, how much of the backoff
+// limit is used, and for each failed pod the exit reason and last error log line.
+func diagnoseJob(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	job, err := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	var lines []string
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		if cond.Type != batchv1.JobFailed && cond.Type != batchv1.JobFailureTarget {
+			continue
+		}
+		line := fmt.Sprintf("Condition %s: %s", cond.Type, cond.Reason)
+		if cond.Message != "" {
+			line += " - " + cond.Message
+		}
+		lines = append(lines, line)
+	}
+	if job.Status.Failed == 0 && len(lines) == 0 {
+		return ""
+	}
+
+	backoffLimit := int32(6)
+	if job.Spec.BackoffLimit != nil {
+		backoffLimit = *job.Spec.BackoffLimit
+	}
+	lines = append(lines, fmt.Sprintf("Failed pods: %d of backoffLimit %d", job.Status.Failed, backoffLimit))
+	if job.Spec.ActiveDeadlineSeconds != nil {
+		lines = append(lines, fmt.Sprintf("Active deadline: %ds", *job.Spec.ActiveDeadlineSeconds))
+	}
+
+	pods, err := podsForJob(ctx, clientset, namespace, job)
+	if err != nil {
+		lines = append(lines, failure("list job pods", err))
+		return strings.Join(lines, "\n")
+	}
+	shown := 0
+	for i := range pods {
+		pod := &pods[i]
+		terminations := terminationLines(pod)
+		if pod.Status.Phase != corev1.PodFailed && len(terminations) == 0 {
+			continue
+		}
+		if shown == diagnosisPodLimit {
+			lines = append(lines, "... more failed pods not shown")
+			break
+		}
+		shown++
+		header := fmt.Sprintf("Pod %s (%s)", pod.Name, pod.Status.Phase)
+		if pod.Status.Reason != "" {
+			header += ": " + pod.Status.Reason
+			if pod.Status.Message != "" {
+				header += " - " + trimString(pod.Status.Message, 200)
+			}
+		}
+		lines = append(lines, header)
+		for _, line := range terminations {
+			lines = append(lines, "  "+line)
+		}
+		if errLine := lastErrorLogLine(ctx, clientset, pod); errLine != "" {
+			lines = append(lines, "  last error: "+errLine)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lastErrorLogLine returns the last log line that looks like an error from the first
+// container that exited non-zero, falling back to the last line of its output.
+func lastErrorLogLine(ctx context.Context, clientset *kubernetes.Clientset, pod *corev1.Pod) string {
+	if isRestrictedAction("fetch pod logs") {
+		return ""
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		previous := false
+		term := cs.State.Terminated
+		if term == nil || term.ExitCode == 0 {
+			term = cs.LastTerminationState.Terminated
+			previous = true
+		}
+		if term == nil || term.ExitCode == 0 {
+			continue
+		}
+		tail := errorLogTail
+		data, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+			Container: cs.Name,
+			TailLines: &tail,
+			Previous:  previous,
+		}).DoRaw(ctx)
+		if err != nil {
+			if IsRestricted(err) {
+				restrictedActions.Store("fetch pod logs", true)
+			}
+			return ""
+		}
+		logLines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		for i := len(logLines) - 1; i >= 0; i-- {
+			if errorLine.MatchString(logLines[i]) {
+				return trimString(strings.TrimSpace(logLines[i]), 300)
+			}
+		}
+		return trimString(strings.TrimSpace(logLines[len(logLines)-1]), 300)
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/a0xAi/kubeve/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// clusterDNSSelector is the label both CoreDNS and kube-dns deployments carry.
+const clusterDNSSelector = "k8s-app=kube-dns"
+
+var dnsFailure = regexp.MustCompile(`(?i)(no such host|server misbehaving|NXDOMAIN|SERVFAIL|temporary failure in name resolution|could not resolve host|name or service not known|lookup \S+ on \S+:53|dial udp \S+:53)`)
+
+// IsDNSFailure reports whether text contains a name resolution error.
+func IsDNSFailure(text string) bool {
+	return dnsFailure.MatchString(text)
+}
+
+// CoreDNSHealth describes the cluster DNS deployment in kube-system: replica readiness,
+// pod health and its recent Warning events.
+func CoreDNSHealth(ctx context.Context, clientset *kubernetes.Clientset) string {
+	if clientset == nil {
+		return "Kubernetes client is not available."
+	}
+	deployments, err := clientset.AppsV1().Deployments(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{LabelSelector: clusterDNSSelector})
+	if err != nil {
+		return failure("list cluster DNS deployments", err)
+	}
+	if len(deployments.Items) == 0 {
+		return fmt.Sprintf("No deployment labelled %s in %s; the cluster may use a DNS add-on kubeve does not know.", clusterDNSSelector, metav1.NamespaceSystem)
+	}
+
+	var lines []string
+	prefixes := make([]string, 0, len(deployments.Items))
+	for _, dep := range deployments.Items {
+		prefixes = append(prefixes, dep.Name)
+		lines = append(lines, fmt.Sprintf("Deployment %s/%s: %d/%d ready, %d available",
+			dep.Namespace, dep.Name, dep.Status.ReadyReplicas, valueOrDefault(dep.Spec.Replicas), dep.Status.AvailableReplicas))
+		if dep.Status.ReadyReplicas < valueOrDefault(dep.Spec.Replicas) {
+			lines = append(lines, "  Not all replicas are ready: lookups may time out or fail intermittently.")
+		}
+	}
+
+	pods, err := listPodsBySelector(ctx, clientset, metav1.NamespaceSystem, clusterDNSSelector)
+	if err != nil {
+		lines = append(lines, failure("list cluster DNS pods", err))
+	} else {
+		lines = append(lines, "Pods:")
+		for _, pod := range pods {
+			lines = append(lines, podHealth(&pod))
+		}
+	}
+
+	events, err := clientset.CoreV1().Events(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("type", corev1.EventTypeWarning).String(),
+	})
+	if err != nil {
+		lines = append(lines, failure("list cluster DNS events", err))
+		return strings.Join(lines, "\n")
+	}
+	var warnings []corev1.Event
+	for _, event := range events.Items {
+		for _, prefix := range prefixes {
+			if event.InvolvedObject.Name == prefix || strings.HasPrefix(event.InvolvedObject.Name, prefix+"-") {
+				warnings = append(warnings, event)
+				break
+			}
+		}
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		return kube.EventTime(warnings[i]).After(kube.EventTime(warnings[j]))
+	})
+	if len(warnings) == 0 {
+		lines = append(lines, "Recent Warning events: none")
+	} else {
+		lines = append(lines, "Recent Warning events:")
+		lines = append(lines, formatObjectEvents(warnings, 10)...)
+	}
+	return strings.Join(lines, "\n")
+}
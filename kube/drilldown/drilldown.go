@@ -0,0 +1,1101 @@
+// Package drilldown explains the objects events are about: describe-like text, related
+// objects, logs, manifests and, for common failures, a diagnosis.
+package drilldown
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/a0xAi/kubeve/internal/format"
+	"github.com/a0xAi/kubeve/kube"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Get inspects the object kind/name in namespace. Failures are reported in the text of
+// the result rather than returned.
+func Get(
+	ctx context.Context,
+	clientset *kubernetes.Clientset,
+	namespace string,
+	kind string,
+	name string,
+) kube.ResourceDrillDown {
+	res := kube.ResourceDrillDown{
+		Describe: "No describe information available.",
+		Related:  "No related resources found.",
+		Logs:     "No logs available for this resource.",
+	}
+
+	if clientset == nil {
+		res.Describe = "Kubernetes client is not available."
+		return res
+	}
+
+	normalizedKind := strings.ToLower(strings.TrimSpace(kind))
+	resourceName := strings.TrimSpace(name)
+	if normalizedKind == "" || resourceName == "" {
+		res.Describe = "Resource kind/name is not available."
+		return res
+	}
+
+	resourceNamespace := namespace
+	if resourceNamespace == "" && isNamespacedKind(normalizedKind) {
+		resourceNamespace = metav1.NamespaceDefault
+	}
+
+	var logPod string
+	adapter, ok := adapterFor(normalizedKind)
+	if ok {
+		res.Describe = adapter.Describe(ctx, clientset, resourceNamespace, resourceName)
+		var pages []string
+		pages, logPod = adapter.Related(ctx, clientset, resourceNamespace, resourceName)
+		if len(pages) > 0 {
+			res.Related = pages[0]
+		}
+		if len(pages) > 1 {
+			res.RelatedPages = pages
+		}
+		if diagnoser, ok := adapter.(Diagnoser); ok {
+			res.Diagnosis = diagnoser.Diagnose(ctx, clientset, resourceNamespace, resourceName)
+		}
+	} else {
+		res.Describe, res.Related = drillDownGeneric(ctx, clientset, resourceNamespace, kind, resourceName)
+	}
+
+	if logPod != "" {
+		res.Termination = podTermination(ctx, clientset, resourceNamespace, logPod)
+	}
+	if ok {
+		if logs := adapter.Logs(ctx, clientset, resourceNamespace, resourceName, logPod); logs != "" {
+			res.Logs = logs
+		}
+	}
+
+	eventsSummary := recentObjectEvents(ctx, clientset, namespace, kind, resourceName)
+	if eventsSummary != "" {
+		res.Describe = strings.TrimSpace(res.Describe) + "\n\nRecent object events:\n" + eventsSummary
+	}
+	res.RelatedObjects = relatedObjects(ctx, clientset, resourceNamespace, kind, resourceName)
+	if related := relatedObjectEvents(ctx, clientset, resourceNamespace, res.RelatedObjects); related != "" {
+		res.Describe = strings.TrimSpace(res.Describe) + "\n\nRelated events (owners and owned objects):\n" + related
+	}
+
+	return res
+}
+
+// drillDownGeneric describes an object of a kind without an adapter, such as a custom
+// resource, through the dynamic client, and lists its owner chain as related resources.
+func drillDownGeneric(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) (string, string) {
+	noAdapter := fmt.Sprintf("No describe adapter for kind %q", kind)
+	client, err := genericClientFor(clientset)
+	if err != nil {
+		return noAdapter + ": " + err.Error(), "No related adapter for this resource kind yet."
+	}
+	obj, mapping, err := client.get(ctx, namespace, kind, name)
+	if meta.IsNoMatchError(err) {
+		return noAdapter + ", and the server does not serve it.", "No related resources found."
+	}
+	if err != nil {
+		return failure("load "+strings.ToLower(kind), err), "No related resources found."
+	}
+	return describeGeneric(obj, mapping), relatedForGeneric(ctx, client, obj)
+}
+
+// isNamespacedKind reports whether objects of a lower-case kind live in a namespace:
+// those of every kind but the ones whose adapter is ClusterScoped.
+func isNamespacedKind(kind string) bool {
+	adapter, ok := adapterFor(kind)
+	if !ok {
+		return true
+	}
+	scoped, ok := adapter.(ClusterScoped)
+	return !ok || !scoped.ClusterScoped()
+}
+
+func describeDeployment(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	dep, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load deployment", err)
+	}
+	desired := int32(1)
+	if dep.Spec.Replicas != nil {
+		desired = *dep.Spec.Replicas
+	}
+	lines := []string{
+		"Kind: Deployment",
+		fmt.Sprintf("Name: %s", dep.Name),
+		fmt.Sprintf("Namespace: %s", dep.Namespace),
+		fmt.Sprintf("Selector: %s", metav1.FormatLabelSelector(dep.Spec.Selector)),
+		fmt.Sprintf(
+			"Replicas: desired=%d updated=%d ready=%d available=%d unavailable=%d",
+			desired, dep.Status.UpdatedReplicas, dep.Status.ReadyReplicas, dep.Status.AvailableReplicas, dep.Status.UnavailableReplicas,
+		),
+		fmt.Sprintf("Strategy: %s", dep.Spec.Strategy.Type),
+	}
+	return strings.Join(lines, "\n")
+}
+
+func describeReplicaSet(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	rs, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load replicaset", err)
+	}
+	desired := int32(1)
+	if rs.Spec.Replicas != nil {
+		desired = *rs.Spec.Replicas
+	}
+	lines := []string{
+		"Kind: ReplicaSet",
+		fmt.Sprintf("Name: %s", rs.Name),
+		fmt.Sprintf("Namespace: %s", rs.Namespace),
+		fmt.Sprintf("Selector: %s", metav1.FormatLabelSelector(rs.Spec.Selector)),
+		fmt.Sprintf("Replicas: desired=%d ready=%d available=%d", desired, rs.Status.ReadyReplicas, rs.Status.AvailableReplicas),
+	}
+	return strings.Join(lines, "\n")
+}
+
+func describeStatefulSet(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	sts, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load statefulset", err)
+	}
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	lines := []string{
+		"Kind: StatefulSet",
+		fmt.Sprintf("Name: %s", sts.Name),
+		fmt.Sprintf("Namespace: %s", sts.Namespace),
+		fmt.Sprintf("Service: %s", sts.Spec.ServiceName),
+		fmt.Sprintf("Selector: %s", metav1.FormatLabelSelector(sts.Spec.Selector)),
+		fmt.Sprintf("Replicas: desired=%d ready=%d current=%d updated=%d", desired, sts.Status.ReadyReplicas, sts.Status.CurrentReplicas, sts.Status.UpdatedReplicas),
+	}
+	return strings.Join(lines, "\n")
+}
+
+func describeDaemonSet(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load daemonset", err)
+	}
+	lines := []string{
+		"Kind: DaemonSet",
+		fmt.Sprintf("Name: %s", ds.Name),
+		fmt.Sprintf("Namespace: %s", ds.Namespace),
+		fmt.Sprintf("Selector: %s", metav1.FormatLabelSelector(ds.Spec.Selector)),
+		fmt.Sprintf(
+			"Pods: desired=%d current=%d ready=%d updated=%d available=%d",
+			ds.Status.DesiredNumberScheduled,
+			ds.Status.CurrentNumberScheduled,
+			ds.Status.NumberReady,
+			ds.Status.UpdatedNumberScheduled,
+			ds.Status.NumberAvailable,
+		),
+	}
+	return strings.Join(lines, "\n")
+}
+
+func describeJob(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	job, err := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load job", err)
+	}
+	lines := []string{
+		"Kind: Job",
+		fmt.Sprintf("Name: %s", job.Name),
+		fmt.Sprintf("Namespace: %s", job.Namespace),
+		fmt.Sprintf("Completions: %d", valueOrDefault(job.Spec.Completions)),
+		fmt.Sprintf("Parallelism: %d", valueOrDefault(job.Spec.Parallelism)),
+		fmt.Sprintf("Status: active=%d succeeded=%d failed=%d", job.Status.Active, job.Status.Succeeded, job.Status.Failed),
+	}
+	if job.Status.StartTime != nil {
+		lines = append(lines, fmt.Sprintf("Started: %s", format.Timestamp(job.Status.StartTime.Time)))
+	}
+	if job.Status.CompletionTime != nil {
+		lines = append(lines, fmt.Sprintf("Completed: %s", format.Timestamp(job.Status.CompletionTime.Time)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func describeCronJob(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	cron, err := clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load cronjob", err)
+	}
+	lines := []string{
+		"Kind: CronJob",
+		fmt.Sprintf("Name: %s", cron.Name),
+		fmt.Sprintf("Namespace: %s", cron.Namespace),
+		fmt.Sprintf("Schedule: %s", cron.Spec.Schedule),
+		fmt.Sprintf("Suspend: %t", boolOrDefault(cron.Spec.Suspend)),
+		fmt.Sprintf("ConcurrencyPolicy: %s", cron.Spec.ConcurrencyPolicy),
+	}
+	if cron.Status.LastScheduleTime != nil {
+		lines = append(lines, fmt.Sprintf("Last scheduled: %s", format.Timestamp(cron.Status.LastScheduleTime.Time)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func describeService(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	svc, err := clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load service", err)
+	}
+	lines := []string{
+		"Kind: Service",
+		fmt.Sprintf("Name: %s", svc.Name),
+		fmt.Sprintf("Namespace: %s", svc.Namespace),
+		fmt.Sprintf("Type: %s", svc.Spec.Type),
+		fmt.Sprintf("ClusterIP: %s", svc.Spec.ClusterIP),
+	}
+	if len(svc.Spec.Selector) > 0 {
+		pairs := make([]string, 0, len(svc.Spec.Selector))
+		for k, v := range svc.Spec.Selector {
+			pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+		}
+		sort.Strings(pairs)
+		lines = append(lines, "Selector: "+strings.Join(pairs, ", "))
+	}
+	if len(svc.Spec.Ports) > 0 {
+		lines = append(lines, "Ports:")
+		for _, p := range svc.Spec.Ports {
+			lines = append(lines, fmt.Sprintf("- %s %d->%s/%s", p.Name, p.Port, p.TargetPort.String(), p.Protocol))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func describeNode(ctx context.Context, clientset *kubernetes.Clientset, name string) string {
+	node, err := clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load node", err)
+	}
+	lines := []string{
+		"Kind: Node",
+		fmt.Sprintf("Name: %s", node.Name),
+		fmt.Sprintf("Kubelet: %s", node.Status.NodeInfo.KubeletVersion),
+		fmt.Sprintf("Container Runtime: %s", node.Status.NodeInfo.ContainerRuntimeVersion),
+		fmt.Sprintf("OS Image: %s", node.Status.NodeInfo.OSImage),
+		fmt.Sprintf("Kernel: %s", node.Status.NodeInfo.KernelVersion),
+	}
+	if allocatable := resourceListText(node.Status.Allocatable); allocatable != "" {
+		lines = append(lines, "Allocatable: "+allocatable)
+	}
+	if usage := usageText(nodeUsage(ctx, clientset, name), usageBase{"allocatable", node.Status.Allocatable}); usage != "" {
+		lines = append(lines, "Usage: "+usage)
+	}
+
+	cond := make([]string, 0, len(node.Status.Conditions))
+	for _, c := range node.Status.Conditions {
+		if c.Status == corev1.ConditionTrue {
+			cond = append(cond, string(c.Type))
+		}
+	}
+	if len(cond) > 0 {
+		sort.Strings(cond)
+		lines = append(lines, "Healthy conditions: "+strings.Join(cond, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func relatedForPod(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load pod relationship", err), ""
+	}
+
+	lines := []string{fmt.Sprintf("Pod: %s", pod.Name)}
+	if len(pod.OwnerReferences) > 0 {
+		for _, ref := range pod.OwnerReferences {
+			lines = append(lines, fmt.Sprintf("Owner: %s/%s", ref.Kind, ref.Name))
+			if ref.Kind == "ReplicaSet" {
+				rs, rsErr := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+				if rsErr == nil {
+					if dep := ownerName(rs.OwnerReferences, "Deployment"); dep != "" {
+						lines = append(lines, "Deployment: "+dep)
+					}
+				}
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), pod.Name
+}
+
+func relatedForDeployment(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	dep, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load deployment relationship", err), ""
+	}
+
+	lines := []string{
+		fmt.Sprintf("Deployment: %s", dep.Name),
+		fmt.Sprintf("Selector: %s", metav1.FormatLabelSelector(dep.Spec.Selector)),
+	}
+	rsList, err := clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err == nil {
+		lines = append(lines, rolloutHistory(dep, rsList.Items, time.Now())...)
+	}
+
+	pods, podErr := listPodsBySelector(ctx, clientset, namespace, metav1.FormatLabelSelector(dep.Spec.Selector))
+	if podErr != nil {
+		lines = append(lines, fmt.Sprintf("Pods: failed to list (%v)", podErr))
+		return strings.Join(lines, "\n"), ""
+	}
+	lines = append(lines, summarizePods(pods)...)
+	return strings.Join(lines, "\n"), pickPodForLogs(pods)
+}
+
+func relatedForReplicaSet(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	rs, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load replicaset relationship", err), ""
+	}
+	lines := []string{
+		fmt.Sprintf("ReplicaSet: %s", rs.Name),
+		fmt.Sprintf("Selector: %s", metav1.FormatLabelSelector(rs.Spec.Selector)),
+	}
+	if dep := ownerName(rs.OwnerReferences, "Deployment"); dep != "" {
+		lines = append(lines, "Deployment: "+dep)
+	}
+	pods, podErr := listPodsBySelector(ctx, clientset, namespace, metav1.FormatLabelSelector(rs.Spec.Selector))
+	if podErr != nil {
+		lines = append(lines, fmt.Sprintf("Pods: failed to list (%v)", podErr))
+		return strings.Join(lines, "\n"), ""
+	}
+	lines = append(lines, summarizePods(pods)...)
+	return strings.Join(lines, "\n"), pickPodForLogs(pods)
+}
+
+func relatedForStatefulSet(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	sts, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load statefulset relationship", err), ""
+	}
+	lines := []string{
+		fmt.Sprintf("StatefulSet: %s", sts.Name),
+		fmt.Sprintf("Selector: %s", metav1.FormatLabelSelector(sts.Spec.Selector)),
+	}
+	pods, podErr := listPodsBySelector(ctx, clientset, namespace, metav1.FormatLabelSelector(sts.Spec.Selector))
+	if podErr != nil {
+		lines = append(lines, fmt.Sprintf("Pods: failed to list (%v)", podErr))
+		return strings.Join(lines, "\n"), ""
+	}
+	lines = append(lines, summarizePods(pods)...)
+	return strings.Join(lines, "\n"), pickPodForLogs(pods)
+}
+
+func relatedForDaemonSet(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load daemonset relationship", err), ""
+	}
+	lines := []string{
+		fmt.Sprintf("DaemonSet: %s", ds.Name),
+		fmt.Sprintf("Selector: %s", metav1.FormatLabelSelector(ds.Spec.Selector)),
+	}
+	pods, podErr := listPodsBySelector(ctx, clientset, namespace, metav1.FormatLabelSelector(ds.Spec.Selector))
+	if podErr != nil {
+		lines = append(lines, fmt.Sprintf("Pods: failed to list (%v)", podErr))
+		return strings.Join(lines, "\n"), ""
+	}
+	lines = append(lines, summarizePods(pods)...)
+	return strings.Join(lines, "\n"), pickPodForLogs(pods)
+}
+
+func relatedForJob(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	job, err := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load job relationship", err), ""
+	}
+	lines := []string{fmt.Sprintf("Job: %s", job.Name)}
+	pods, podErr := podsForJob(ctx, clientset, namespace, job)
+	if podErr != nil {
+		lines = append(lines, fmt.Sprintf("Pods: failed to list (%v)", podErr))
+		return strings.Join(lines, "\n"), ""
+	}
+	lines = append(lines, summarizePods(pods)...)
+	return strings.Join(lines, "\n"), pickPodForLogs(pods)
+}
+
+func relatedForCronJob(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	cron, err := clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load cronjob relationship", err), ""
+	}
+	lines := []string{fmt.Sprintf("CronJob: %s", cron.Name)}
+	jobs, err := clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err == nil {
+		matched := make([]batchv1.Job, 0)
+		for _, job := range jobs.Items {
+			if ownerName(job.OwnerReferences, "CronJob") == cron.Name {
+				matched = append(matched, job)
+			}
+		}
+		sort.Slice(matched, func(i, j int) bool {
+			return matched[i].CreationTimestamp.Time.After(matched[j].CreationTimestamp.Time)
+		})
+		if len(matched) > 0 {
+			lines = append(lines, "Recent Jobs:")
+			limit := 5
+			if len(matched) < limit {
+				limit = len(matched)
+			}
+			for _, job := range matched[:limit] {
+				lines = append(lines, fmt.Sprintf("- %s active=%d succeeded=%d failed=%d", job.Name, job.Status.Active, job.Status.Succeeded, job.Status.Failed))
+			}
+			pods, podErr := podsForJob(ctx, clientset, namespace, &matched[0])
+			if podErr == nil {
+				lines = append(lines, summarizePods(pods)...)
+				return strings.Join(lines, "\n"), pickPodForLogs(pods)
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), ""
+}
+
+func relatedForService(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	svc, err := clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load service relationship", err), ""
+	}
+	lines := []string{fmt.Sprintf("Service: %s", svc.Name)}
+	if len(svc.Spec.Selector) == 0 {
+		lines = append(lines, "No selector configured.")
+		return strings.Join(lines, "\n"), ""
+	}
+	selectorParts := make([]string, 0, len(svc.Spec.Selector))
+	for k, v := range svc.Spec.Selector {
+		selectorParts = append(selectorParts, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(selectorParts)
+	selector := strings.Join(selectorParts, ",")
+	lines = append(lines, "Selector: "+selector)
+	pods, podErr := listPodsBySelector(ctx, clientset, namespace, selector)
+	if podErr != nil {
+		lines = append(lines, fmt.Sprintf("Pods: failed to list (%v)", podErr))
+		return strings.Join(lines, "\n"), ""
+	}
+	lines = append(lines, summarizePods(pods)...)
+	return strings.Join(lines, "\n"), pickPodForLogs(pods)
+}
+
+// nodePodsPerPage is how many pods one page of a node's related resources lists.
+const nodePodsPerPage = 20
+
+// relatedForNode lists the pods on a node with their phase counts, in pages of
+// nodePodsPerPage pods. It returns at least one page.
+func relatedForNode(ctx context.Context, clientset *kubernetes.Clientset, nodeName string) []string {
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return []string{failure("load pods on node", err)}
+	}
+	header := fmt.Sprintf("Node: %s", nodeName)
+	if len(pods.Items) == 0 {
+		return []string{header + "\nNo pods scheduled on this node."}
+	}
+	sorted := append([]corev1.Pod(nil), pods.Items...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Namespace != sorted[j].Namespace {
+			return sorted[i].Namespace < sorted[j].Namespace
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	header += fmt.Sprintf("\nPods on node: %d (%s)", len(sorted), podPhaseCounts(sorted))
+
+	now := time.Now()
+	pageCount := (len(sorted) + nodePodsPerPage - 1) / nodePodsPerPage
+	pages := make([]string, 0, pageCount)
+	for start := 0; start < len(sorted); start += nodePodsPerPage {
+		lines := []string{header}
+		for _, pod := range sorted[start:min(start+nodePodsPerPage, len(sorted))] {
+			lines = append(lines, fmt.Sprintf("- %s/%s (%s)", pod.Namespace, pod.Name, podStatusText(&pod, now)))
+		}
+		if pageCount > 1 {
+			lines = append(lines, fmt.Sprintf("Page %d/%d", len(pages)+1, pageCount))
+		}
+		pages = append(pages, strings.Join(lines, "\n"))
+	}
+	return pages
+}
+
+// podPhaseCounts renders how many pods are in each phase, e.g. "3 Running, 1 Pending".
+func podPhaseCounts(pods []corev1.Pod) string {
+	phases := make(map[corev1.PodPhase]int)
+	for _, pod := range pods {
+		phases[pod.Status.Phase]++
+	}
+	var counts []string
+	for _, phase := range []corev1.PodPhase{corev1.PodRunning, corev1.PodPending, corev1.PodSucceeded, corev1.PodFailed, corev1.PodUnknown} {
+		if phases[phase] > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", phases[phase], phase))
+		}
+	}
+	return strings.Join(counts, ", ")
+}
+
+func recentObjectEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) string {
+	if strings.TrimSpace(name) == "" || strings.TrimSpace(kind) == "" {
+		return ""
+	}
+	sorted, err := listObjectEvents(ctx, clientset, namespace, kind, name)
+	if err != nil || len(sorted) == 0 {
+		return ""
+	}
+
+	// Objects re-created under the same name get a new UID; keep their histories apart.
+	currentUID := currentObjectUID(ctx, clientset, namespace, kind, name)
+	if currentUID == "" {
+		currentUID = string(sorted[0].InvolvedObject.UID)
+	}
+	var current []corev1.Event
+	previous := make(map[types.UID][]corev1.Event)
+	var previousOrder []types.UID
+	for _, event := range sorted {
+		uid := event.InvolvedObject.UID
+		if uid == "" || string(uid) == currentUID {
+			current = append(current, event)
+			continue
+		}
+		if _, seen := previous[uid]; !seen {
+			previousOrder = append(previousOrder, uid)
+		}
+		previous[uid] = append(previous[uid], event)
+	}
+
+	lines := formatObjectEvents(current, 6)
+	if len(current) == 0 {
+		lines = append(lines, "- none for the current object")
+	}
+	if len(previousOrder) > 0 {
+		lines = append(lines, "", "Previous objects with this name:")
+		for _, uid := range previousOrder {
+			group := previous[uid]
+			lines = append(lines, fmt.Sprintf("UID %s (last event %s, %d events):",
+				uid, format.Clock(kube.EventTime(group[0])), len(group)))
+			lines = append(lines, formatObjectEvents(group, 3)...)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// listObjectEvents lists the events about the object kind/name, newest first.
+func listObjectEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) ([]corev1.Event, error) {
+	eventNamespace := namespace
+	if eventNamespace == "" {
+		eventNamespace = metav1.NamespaceAll
+	}
+	selector := fields.AndSelectors(
+		fields.OneTermEqualSelector("involvedObject.name", strings.TrimSpace(name)),
+		fields.OneTermEqualSelector("involvedObject.kind", strings.TrimSpace(kind)),
+	).String()
+	events, err := clientset.CoreV1().Events(eventNamespace).List(ctx, metav1.ListOptions{
+		FieldSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sorted := append([]corev1.Event(nil), events.Items...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return kube.EventTime(sorted[i]).After(kube.EventTime(sorted[j]))
+	})
+	return sorted, nil
+}
+
+func formatObjectEvents(events []corev1.Event, limit int) []string {
+	if len(events) < limit {
+		limit = len(events)
+	}
+	lines := make([]string, 0, limit)
+	for _, event := range events[:limit] {
+		lines = append(lines, fmt.Sprintf(
+			"- %s %s/%s: %s",
+			format.Clock(kube.EventTime(event)),
+			event.Type,
+			event.Reason,
+			trimString(event.Message, 140),
+		))
+	}
+	return lines
+}
+
+// currentObjectUID returns the UID of the live object, or "" when it is gone or the kind
+// is not looked up.
+func currentObjectUID(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) string {
+	ref, err := ParseObjectRef(kind + "/" + name)
+	if err != nil {
+		return ""
+	}
+	if ref.Kind == "Pod" {
+		pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return ""
+		}
+		return string(pod.UID)
+	}
+	uid, ok, err := ownerUID(ctx, clientset, namespace, ref)
+	if err != nil || !ok {
+		return ""
+	}
+	return string(uid)
+}
+
+func podsForJob(ctx context.Context, clientset *kubernetes.Clientset, namespace string, job *batchv1.Job) ([]corev1.Pod, error) {
+	if job.Spec.Selector == nil {
+		return []corev1.Pod{}, nil
+	}
+	return listPodsBySelector(ctx, clientset, namespace, metav1.FormatLabelSelector(job.Spec.Selector))
+}
+
+func listPodsBySelector(
+	ctx context.Context,
+	clientset *kubernetes.Clientset,
+	namespace string,
+	selector string,
+) ([]corev1.Pod, error) {
+	if strings.TrimSpace(selector) == "" {
+		return []corev1.Pod{}, nil
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sorted := append([]corev1.Pod(nil), pods.Items...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Status.Phase != sorted[j].Status.Phase {
+			return sorted[i].Status.Phase == corev1.PodRunning
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted, nil
+}
+
+func summarizePods(pods []corev1.Pod) []string {
+	if len(pods) == 0 {
+		return []string{"Pods: none"}
+	}
+	lines := []string{"Pods:"}
+	limit := 8
+	if len(pods) < limit {
+		limit = len(pods)
+	}
+	now := time.Now()
+	for _, pod := range pods[:limit] {
+		lines = append(lines, fmt.Sprintf("- %s (%s)", pod.Name, podStatusText(&pod, now)))
+	}
+	if len(pods) > limit {
+		lines = append(lines, fmt.Sprintf("... +%d more", len(pods)-limit))
+	}
+	return lines
+}
+
+// podStatusText renders a pod's phase with its ready containers, the reason a container
+// is waiting, restarts and age, e.g. "Running, 0/1 ready, CrashLoopBackOff, 12 restarts,
+// age 2h": a crash looping pod is Running between its restarts.
+func podStatusText(pod *corev1.Pod, now time.Time) string {
+	ready, restarts := 0, int32(0)
+	waiting := ""
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			ready++
+		}
+		restarts += status.RestartCount
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" && waiting == "" {
+			waiting = status.State.Waiting.Reason
+		}
+	}
+	parts := []string{string(pod.Status.Phase), fmt.Sprintf("%d/%d ready", ready, len(pod.Spec.Containers))}
+	if waiting != "" {
+		parts = append(parts, waiting)
+	}
+	switch restarts {
+	case 0:
+	case 1:
+		parts = append(parts, "1 restart")
+	default:
+		parts = append(parts, fmt.Sprintf("%d restarts", restarts))
+	}
+	if !pod.CreationTimestamp.IsZero() {
+		parts = append(parts, "age "+format.Duration(now.Sub(pod.CreationTimestamp.Time)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func pickPodForLogs(pods []corev1.Pod) string {
+	if len(pods) == 0 {
+		return ""
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning {
+			return pod.Name
+		}
+	}
+	return pods[0].Name
+}
+
+var signalNames = map[int32]string{
+	1: "SIGHUP", 2: "SIGINT", 3: "SIGQUIT", 6: "SIGABRT", 7: "SIGBUS",
+	8: "SIGFPE", 9: "SIGKILL", 11: "SIGSEGV", 13: "SIGPIPE", 15: "SIGTERM",
+}
+
+// podTermination reports the current or last terminated state of each container that
+// exited abnormally, since "Back-off restarting failed container" never says why.
+func podTermination(ctx context.Context, clientset *kubernetes.Clientset, namespace, podName string) string {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	return strings.Join(terminationLines(pod), "\n")
+}
+
+func terminationLines(pod *corev1.Pod) []string {
+	statuses := append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	var lines []string
+	for _, cs := range statuses {
+		term := cs.State.Terminated
+		when := "exited"
+		if term == nil || term.ExitCode == 0 {
+			term = cs.LastTerminationState.Terminated
+			when = "last exited"
+		}
+		if term == nil || (term.ExitCode == 0 && term.Signal == 0 && term.Reason != "OOMKilled") {
+			continue
+		}
+		line := fmt.Sprintf("%s/%s %s with code %d", pod.Name, cs.Name, when, term.ExitCode)
+		signal := term.Signal
+		if signal == 0 && term.ExitCode > 128 {
+			signal = term.ExitCode - 128
+		}
+		if name, ok := signalNames[signal]; ok {
+			line += " (" + name + ")"
+		} else if signal != 0 {
+			line += fmt.Sprintf(" (signal %d)", signal)
+		}
+		if term.Reason != "" {
+			line += ", reason " + term.Reason
+		}
+		if !term.FinishedAt.IsZero() {
+			line += ", at " + format.Timestamp(term.FinishedAt.Time)
+		}
+		if cs.RestartCount > 0 {
+			line += fmt.Sprintf(", %d restarts", cs.RestartCount)
+		}
+		lines = append(lines, line)
+		if message := strings.TrimSpace(term.Message); message != "" {
+			lines = append(lines, "  message: "+trimString(message, 600))
+		}
+	}
+	return lines
+}
+
+const defaultLogTailLines = 80
+
+// logTailLines is how many log lines the drill-down shows of a pod, 0 for the default.
+// It is atomic since the UI changes it while drill-downs load in the background.
+var logTailLines atomic.Int64
+
+// SetLogTailLines sets how many log lines the drill-down shows of a pod.
+func SetLogTailLines(lines int64) {
+	logTailLines.Store(max(lines, 1))
+}
+
+// LogTailLines returns the number set by SetLogTailLines.
+func LogTailLines() int64 {
+	if lines := logTailLines.Load(); lines > 0 {
+		return lines
+	}
+	return defaultLogTailLines
+}
+
+func podLogs(ctx context.Context, clientset *kubernetes.Clientset, namespace, podName string) string {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return failure("load pod for logs", err)
+	}
+	container := pickContainerName(pod)
+	if container == "" {
+		return "Pod has no containers."
+	}
+	if isRestrictedAction("fetch pod logs") {
+		return "Not available in this context (fetch pod logs is not permitted)."
+	}
+	text, err := readPodLogs(ctx, clientset, namespace, podName, container, false)
+	if err != nil {
+		return logsFailure(podName, container, err)
+	}
+	if text == "" {
+		return fmt.Sprintf("No recent logs in pod %s (container %s).", podName, container)
+	}
+	return fmt.Sprintf("Pod: %s\nContainer: %s\n\n%s", podName, container, text)
+}
+
+// podPreviousLogs returns the logs of the previous run of the pod's container that
+// restarted, preferring one whose last run failed, headed by how that run ended.
+func podPreviousLogs(ctx context.Context, clientset *kubernetes.Clientset, namespace, podName string) string {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return failure("load pod for logs", err)
+	}
+	cs := pickRestartedContainer(pod)
+	if cs == nil {
+		return fmt.Sprintf("No previous run in pod %s: none of its containers has restarted.", podName)
+	}
+	if isRestrictedAction("fetch pod logs") {
+		return "Not available in this context (fetch pod logs is not permitted)."
+	}
+	text, err := readPodLogs(ctx, clientset, namespace, podName, cs.Name, true)
+	if err != nil {
+		return logsFailure(podName, cs.Name, err)
+	}
+	header := fmt.Sprintf("Pod: %s\nContainer: %s (previous run)\nLast state: %s", podName, cs.Name, containerStateText(cs.LastTerminationState))
+	if text == "" {
+		return header + "\n\nThe previous run wrote no logs."
+	}
+	return header + "\n\n" + text
+}
+
+// readPodLogs reads the last LogTailLines lines of a container's logs, of its previous
+// run when previous is set, with timestamps.
+func readPodLogs(ctx context.Context, clientset *kubernetes.Clientset, namespace, podName, container string, previous bool) (string, error) {
+	tail := LogTailLines()
+	req := clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container:  container,
+		TailLines:  &tail,
+		Timestamps: true,
+		Previous:   previous,
+	})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	data, err := io.ReadAll(io.LimitReader(stream, 64*1024))
+	if err != nil {
+		return "", fmt.Errorf("read logs stream: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func logsFailure(podName, container string, err error) string {
+	if IsRestricted(err) {
+		return failure("fetch pod logs", err)
+	}
+	return fmt.Sprintf("Failed to fetch logs for pod %s (container %s): %v", podName, container, err)
+}
+
+// HasLogs reports whether ObjectLogs can read logs for objects of kind.
+func HasLogs(kind string) bool {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "pod", "deployment", "replicaset", "statefulset", "daemonset", "job", "cronjob":
+		return true
+	default:
+		return false
+	}
+}
+
+// ObjectLogs returns the recent logs of a pod, or of the pods of a workload merged by
+// time, without loading the rest of the drill-down. With previous set they are the
+// logs of the last run of a restarted container, e.g. after a CrashLoopBackOff.
+func ObjectLogs(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string, previous bool) string {
+	if clientset == nil {
+		return "Kubernetes client is not available."
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	logPod, message := podForLogs(ctx, clientset, namespace, kind, name)
+	if logPod == "" {
+		return message
+	}
+	if previous {
+		return podPreviousLogs(ctx, clientset, namespace, logPod)
+	}
+	return recentLogs(ctx, clientset, namespace, kind, name, logPod)
+}
+
+// podForLogs returns the pod whose logs stand for an object: the pod itself, or the one
+// the drill-down picks for a workload. Without one it returns why instead.
+func podForLogs(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) (pod, message string) {
+	pod = name
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "pod":
+	case "deployment":
+		_, pod = relatedForDeployment(ctx, clientset, namespace, name)
+	case "replicaset":
+		_, pod = relatedForReplicaSet(ctx, clientset, namespace, name)
+	case "statefulset":
+		_, pod = relatedForStatefulSet(ctx, clientset, namespace, name)
+	case "daemonset":
+		_, pod = relatedForDaemonSet(ctx, clientset, namespace, name)
+	case "job":
+		_, pod = relatedForJob(ctx, clientset, namespace, name)
+	case "cronjob":
+		_, pod = relatedForCronJob(ctx, clientset, namespace, name)
+	default:
+		return "", "No logs available for this resource."
+	}
+	if pod == "" {
+		return "", fmt.Sprintf("No pod of %s/%s to read logs from.", kind, name)
+	}
+	return pod, ""
+}
+
+// FollowLogs streams the logs of a pod, or of the pod the drill-down picks for a
+// workload, as they are written: onLine gets the pod and container first, then the last LogTailLines lines and every line after them,
+// with timestamps. It returns once ctx is done, with "", or when the stream cannot start
+// or ends, e.g. because the container exited, with why.
+func FollowLogs(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string, onLine func(line string)) string {
+	if clientset == nil {
+		return "Kubernetes client is not available."
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	podName, message := podForLogs(ctx, clientset, namespace, kind, name)
+	if podName == "" {
+		return message
+	}
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return failure("load pod for logs", err)
+	}
+	container := pickContainerName(pod)
+	if container == "" {
+		return "Pod has no containers."
+	}
+	if isRestrictedAction("fetch pod logs") {
+		return "Not available in this context (fetch pod logs is not permitted)."
+	}
+	tail := LogTailLines()
+	stream, err := clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container:  container,
+		TailLines:  &tail,
+		Timestamps: true,
+		Follow:     true,
+	}).Stream(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ""
+		}
+		return logsFailure(podName, container, err)
+	}
+	defer stream.Close()
+
+	onLine("Pod: " + podName)
+	onLine("Container: " + container)
+	onLine("")
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		onLine(strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if ctx.Err() != nil {
+		return ""
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Sprintf("Log stream of pod %s (container %s) broke: %v", podName, container, err)
+	}
+	return fmt.Sprintf("Log stream of pod %s (container %s) ended.", podName, container)
+}
+
+// pickRestartedContainer returns the status of the container, init containers
+// included, whose previous run the logs should show: the first whose last run failed,
+// else the first that ran before. It returns nil when no container restarted.
+func pickRestartedContainer(pod *corev1.Pod) *corev1.ContainerStatus {
+	statuses := append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	var restarted *corev1.ContainerStatus
+	for i := range statuses {
+		last := statuses[i].LastTerminationState.Terminated
+		if last == nil {
+			continue
+		}
+		if last.ExitCode != 0 {
+			return &statuses[i]
+		}
+		if restarted == nil {
+			restarted = &statuses[i]
+		}
+	}
+	return restarted
+}
+
+func pickContainerName(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Running != nil {
+			return cs.Name
+		}
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
+
+func ownerName(refs []metav1.OwnerReference, kind string) string {
+	for _, ref := range refs {
+		if ref.Kind == kind {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+func valueOrDefault(v *int32) int32 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func boolOrDefault(v *bool) bool {
+	if v == nil {
+		return false
+	}
+	return *v
+}
+
+func trimString(s string, limit int) string {
+	if limit <= 0 || len(s) <= limit {
+		return s
+	}
+	return s[:limit-3] + "..."
+}
+
+// Inspector is the kube.ObjectInspector of the cluster Clientset talks to.
+type Inspector struct {
+	Clientset *kubernetes.Clientset
+}
+
+var _ kube.ObjectInspector = Inspector{}
+
+// DrillDown is Get.
+func (i Inspector) DrillDown(ctx context.Context, namespace, kind, name string) kube.ResourceDrillDown {
+	return Get(ctx, i.Clientset, namespace, kind, name)
+}
+
+// YAML is ObjectYAML.
+func (i Inspector) YAML(ctx context.Context, namespace, kind, name string) (string, error) {
+	return ObjectYAML(ctx, i.Clientset, namespace, kind, name)
+}
+
+// Logs is ObjectLogs.
+func (i Inspector) Logs(ctx context.Context, namespace, kind, name string, previous bool) string {
+	return ObjectLogs(ctx, i.Clientset, namespace, kind, name, previous)
+}
@@ -0,0 +1,166 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/a0xAi/kubeve/kube/client"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+// maxOwnerChain bounds how far relatedForGeneric follows owner references.
+const maxOwnerChain = 8
+
+// genericClient reads objects of any kind the server serves, custom resources among
+// them, for the drill-down of kinds without an adapter.
+type genericClient struct {
+	dynamic dynamic.Interface
+	mapper  meta.ResettableRESTMapper
+}
+
+// genericClients caches a genericClient per clientset, so discovery runs once.
+var genericClients sync.Map
+
+// genericClientFor returns the genericClient for the cluster of clientset, which must
+// come from the client package.
+func genericClientFor(clientset *kubernetes.Clientset) (*genericClient, error) {
+	if cached, ok := genericClients.Load(clientset); ok {
+		return cached.(*genericClient), nil
+	}
+	dyn, err := client.Dynamic(clientset)
+	if err != nil {
+		return nil, err
+	}
+	generic := &genericClient{
+		dynamic: dyn,
+		mapper:  restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery())),
+	}
+	actual, _ := genericClients.LoadOrStore(clientset, generic)
+	return actual.(*genericClient), nil
+}
+
+// get reads the object of a kind named as in an event, e.g. Certificate. When several
+// API groups serve the kind, the first that has the object wins.
+func (c *genericClient) get(ctx context.Context, namespace, kind, name string) (*unstructured.Unstructured, *meta.RESTMapping, error) {
+	kinds, err := c.mapper.KindsFor(schema.GroupVersionResource{Resource: strings.ToLower(kind)})
+	if err != nil {
+		return nil, nil, err
+	}
+	seen := make(map[schema.GroupKind]bool)
+	var firstErr error
+	for _, gvk := range kinds {
+		if seen[gvk.GroupKind()] {
+			continue
+		}
+		seen[gvk.GroupKind()] = true
+		obj, mapping, err := c.getMapped(ctx, gvk.GroupKind(), gvk.Version, namespace, name)
+		if err == nil {
+			return obj, mapping, nil
+		}
+		if firstErr == nil || !apierrors.IsNotFound(err) && apierrors.IsNotFound(firstErr) {
+			firstErr = err
+		}
+	}
+	return nil, nil, firstErr
+}
+
+// getMapped reads an object of a known group and kind; namespace is ignored for
+// cluster-scoped kinds.
+func (c *genericClient) getMapped(ctx context.Context, gk schema.GroupKind, version, namespace, name string) (*unstructured.Unstructured, *meta.RESTMapping, error) {
+	mapping, err := c.mapper.RESTMapping(gk, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	var resource dynamic.ResourceInterface = c.dynamic.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		resource = c.dynamic.Resource(mapping.Resource).Namespace(namespace)
+	}
+	obj, err := resource.Get(ctx, name, metav1.GetOptions{})
+	return obj, mapping, err
+}
+
+// describeGeneric renders an object of a kind without an adapter as YAML: metadata
+// without managedFields and the last-applied annotation, spec and status.
+func describeGeneric(obj *unstructured.Unstructured, mapping *meta.RESTMapping) string {
+	lines := []string{
+		fmt.Sprintf("Kind: %s", obj.GetKind()),
+		fmt.Sprintf("API version: %s", obj.GetAPIVersion()),
+		fmt.Sprintf("Name: %s", obj.GetName()),
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		lines = append(lines, fmt.Sprintf("Namespace: %s", obj.GetNamespace()))
+	}
+
+	obj = obj.DeepCopy()
+	obj.SetManagedFields(nil)
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+		obj.SetAnnotations(annotations)
+	}
+	for _, field := range []string{"metadata", "spec", "status"} {
+		value, ok := obj.Object[field]
+		if !ok {
+			continue
+		}
+		out, err := yaml.Marshal(map[string]any{field: value})
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("%s: <%v>", field, err))
+			continue
+		}
+		lines = append(lines, "", strings.TrimRight(string(out), "\n"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// relatedForGeneric lists the owner chain of an object, following the controller
+// reference, or the first owner without one, up to maxOwnerChain levels.
+func relatedForGeneric(ctx context.Context, client *genericClient, obj *unstructured.Unstructured) string {
+	var lines []string
+	namespace := obj.GetNamespace()
+	for depth := 0; depth < maxOwnerChain; depth++ {
+		owners := obj.GetOwnerReferences()
+		if len(owners) == 0 {
+			break
+		}
+		owner := owners[0]
+		if controller := metav1.GetControllerOfNoCopy(obj); controller != nil {
+			owner = *controller
+		}
+		indent := strings.Repeat("  ", depth)
+		label := fmt.Sprintf("%s- %s/%s", indent, owner.Kind, owner.Name)
+		gv, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil {
+			lines = append(lines, label+" (invalid apiVersion)")
+			break
+		}
+		parent, _, err := client.getMapped(ctx, gv.WithKind(owner.Kind).GroupKind(), gv.Version, namespace, owner.Name)
+		switch {
+		case apierrors.IsNotFound(err):
+			lines = append(lines, label+" (gone)")
+		case err != nil:
+			lines = append(lines, label+" ("+trimString(err.Error(), 120)+")")
+		case parent.GetUID() != owner.UID:
+			lines = append(lines, label+" (replaced by a newer object)")
+		default:
+			lines = append(lines, label)
+			obj = parent
+			continue
+		}
+		break
+	}
+	if len(lines) == 0 {
+		return fmt.Sprintf("%s/%s has no owners.", obj.GetKind(), obj.GetName())
+	}
+	return "Owner chain:\n" + strings.Join(lines, "\n")
+}
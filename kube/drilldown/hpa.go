@@ -0,0 +1,254 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/internal/format"
+	"github.com/a0xAi/kubeve/kube"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+func describeHPA(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	hpa, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load horizontalpodautoscaler", err)
+	}
+	ref := hpa.Spec.ScaleTargetRef
+	lines := []string{
+		"Kind: HorizontalPodAutoscaler",
+		fmt.Sprintf("Name: %s", hpa.Name),
+		fmt.Sprintf("Namespace: %s", hpa.Namespace),
+		fmt.Sprintf("Scale target: %s/%s", ref.Kind, ref.Name),
+		fmt.Sprintf("Replicas: min=%d max=%d current=%d desired=%d", valueOrDefault(hpa.Spec.MinReplicas), hpa.Spec.MaxReplicas, hpa.Status.CurrentReplicas, hpa.Status.DesiredReplicas),
+	}
+	if hpa.Status.LastScaleTime != nil {
+		lines = append(lines, fmt.Sprintf("Last scaled: %s (%s)", format.Timestamp(hpa.Status.LastScaleTime.Time), format.Ago(hpa.Status.LastScaleTime.Time, time.Now())))
+	}
+
+	current := make(map[string]string, len(hpa.Status.CurrentMetrics))
+	for _, status := range hpa.Status.CurrentMetrics {
+		key, value := metricStatusValue(status)
+		current[key] = value
+	}
+	if len(hpa.Spec.Metrics) > 0 {
+		lines = append(lines, "Metrics (current / target):")
+	}
+	for _, spec := range hpa.Spec.Metrics {
+		key, target := metricSpecTarget(spec)
+		value, ok := current[key]
+		if !ok {
+			value = "<unknown>"
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s / %s", key, value, target))
+	}
+
+	if behavior := hpa.Spec.Behavior; behavior != nil {
+		if rules := scalingRulesSummary(behavior.ScaleUp); rules != "" {
+			lines = append(lines, "Scale up: "+rules)
+		}
+		if rules := scalingRulesSummary(behavior.ScaleDown); rules != "" {
+			lines = append(lines, "Scale down: "+rules)
+		}
+	}
+	lines = append(lines, hpaScalingHistory(ctx, clientset, namespace, name)...)
+	return strings.Join(lines, "\n")
+}
+
+// hpaScalingHistoryLimit is how many rescales the scaling history lists.
+const hpaScalingHistoryLimit = 5
+
+// hpaScalingHistory lists the autoscaler's latest SuccessfulRescale events, newest first,
+// e.g. "- 5m ago: New size: 5; reason: cpu resource utilization above target".
+func hpaScalingHistory(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) []string {
+	events, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.AndSelectors(
+			fields.OneTermEqualSelector("involvedObject.kind", "HorizontalPodAutoscaler"),
+			fields.OneTermEqualSelector("involvedObject.name", name),
+			fields.OneTermEqualSelector("reason", "SuccessfulRescale"),
+		).String(),
+	})
+	if err != nil || len(events.Items) == 0 {
+		return nil
+	}
+	sorted := append([]corev1.Event(nil), events.Items...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return kube.EventTime(sorted[i]).After(kube.EventTime(sorted[j]))
+	})
+	now := time.Now()
+	lines := []string{"Scaling history:"}
+	for _, event := range sorted[:min(len(sorted), hpaScalingHistoryLimit)] {
+		lines = append(lines, fmt.Sprintf("- %s: %s", format.Ago(kube.EventTime(event), now), event.Message))
+	}
+	return lines
+}
+
+// relatedForHPA shows the scale target through the matching adapter, so the drill-down
+// lists the pods the autoscaler is sizing.
+func relatedForHPA(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	hpa, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load horizontalpodautoscaler relationship", err), ""
+	}
+	ref := hpa.Spec.ScaleTargetRef
+	header := fmt.Sprintf("Scale target: %s/%s", ref.Kind, ref.Name)
+	var related, logPod string
+	switch ref.Kind {
+	case "Deployment":
+		related, logPod = relatedForDeployment(ctx, clientset, namespace, ref.Name)
+	case "ReplicaSet":
+		related, logPod = relatedForReplicaSet(ctx, clientset, namespace, ref.Name)
+	case "StatefulSet":
+		related, logPod = relatedForStatefulSet(ctx, clientset, namespace, ref.Name)
+	default:
+		return header, ""
+	}
+	return header + "\n" + related, logPod
+}
+
+// diagnoseHPA maps the autoscaler's conditions to what its events mean: whether it can
+// read metrics, whether it may scale and whether min/max replicas are holding it back.
+func diagnoseHPA(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	hpa, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	var lines []string
+	for _, cond := range hpa.Status.Conditions {
+		line := fmt.Sprintf("%s=%s: %s", cond.Type, cond.Status, cond.Reason)
+		if cond.Message != "" {
+			line += " - " + cond.Message
+		}
+		if !cond.LastTransitionTime.IsZero() {
+			line += fmt.Sprintf(" (since %s)", format.Ago(cond.LastTransitionTime.Time, time.Now()))
+		}
+		lines = append(lines, line)
+		if hint := hpaConditionHint(cond); hint != "" {
+			lines = append(lines, "  "+hint)
+		}
+	}
+	if hpa.Status.DesiredReplicas != hpa.Status.CurrentReplicas {
+		lines = append(lines, fmt.Sprintf("Scaling from %d to %d replicas is in progress.", hpa.Status.CurrentReplicas, hpa.Status.DesiredReplicas))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func hpaConditionHint(cond autoscalingv2.HorizontalPodAutoscalerCondition) string {
+	switch {
+	case cond.Type == autoscalingv2.ScalingActive && cond.Status == corev1.ConditionFalse:
+		if strings.HasPrefix(cond.Reason, "FailedGet") {
+			return "Metrics cannot be read; check metrics-server (or the custom metrics adapter) and container resource requests."
+		}
+		return "Autoscaling is disabled, e.g. because the target is scaled to zero."
+	case cond.Type == autoscalingv2.AbleToScale && cond.Status == corev1.ConditionFalse:
+		return "The scale subresource of the target cannot be read or updated."
+	case cond.Type == autoscalingv2.AbleToScale && cond.Reason == "ScaleDownStabilized":
+		return "A recent higher recommendation is held by the scale-down stabilization window."
+	case cond.Type == autoscalingv2.ScalingLimited && cond.Status == corev1.ConditionTrue:
+		return "The desired replica count is clamped by minReplicas/maxReplicas or a scaling policy."
+	default:
+		return ""
+	}
+}
+
+func metricSpecTarget(spec autoscalingv2.MetricSpec) (string, string) {
+	switch spec.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if spec.Resource != nil {
+			return "resource " + string(spec.Resource.Name), metricTargetValue(spec.Resource.Target)
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if spec.ContainerResource != nil {
+			return "resource " + string(spec.ContainerResource.Name) + " of " + spec.ContainerResource.Container, metricTargetValue(spec.ContainerResource.Target)
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if spec.Pods != nil {
+			return "pods " + spec.Pods.Metric.Name, metricTargetValue(spec.Pods.Target)
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if spec.Object != nil {
+			return "object " + spec.Object.Metric.Name, metricTargetValue(spec.Object.Target)
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if spec.External != nil {
+			return "external " + spec.External.Metric.Name, metricTargetValue(spec.External.Target)
+		}
+	}
+	return string(spec.Type), "<unknown>"
+}
+
+func metricStatusValue(status autoscalingv2.MetricStatus) (string, string) {
+	switch status.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if status.Resource != nil {
+			return "resource " + string(status.Resource.Name), metricCurrentValue(status.Resource.Current)
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if status.ContainerResource != nil {
+			return "resource " + string(status.ContainerResource.Name) + " of " + status.ContainerResource.Container, metricCurrentValue(status.ContainerResource.Current)
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if status.Pods != nil {
+			return "pods " + status.Pods.Metric.Name, metricCurrentValue(status.Pods.Current)
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if status.Object != nil {
+			return "object " + status.Object.Metric.Name, metricCurrentValue(status.Object.Current)
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if status.External != nil {
+			return "external " + status.External.Metric.Name, metricCurrentValue(status.External.Current)
+		}
+	}
+	return string(status.Type), "<unknown>"
+}
+
+func metricTargetValue(target autoscalingv2.MetricTarget) string {
+	switch {
+	case target.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		return target.AverageValue.String() + " (avg)"
+	case target.Value != nil:
+		return target.Value.String()
+	default:
+		return "<unknown>"
+	}
+}
+
+func metricCurrentValue(current autoscalingv2.MetricValueStatus) string {
+	switch {
+	case current.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *current.AverageUtilization)
+	case current.AverageValue != nil:
+		return current.AverageValue.String() + " (avg)"
+	case current.Value != nil:
+		return current.Value.String()
+	default:
+		return "<unknown>"
+	}
+}
+
+func scalingRulesSummary(rules *autoscalingv2.HPAScalingRules) string {
+	if rules == nil {
+		return ""
+	}
+	var parts []string
+	if rules.StabilizationWindowSeconds != nil {
+		parts = append(parts, fmt.Sprintf("stabilization %ds", *rules.StabilizationWindowSeconds))
+	}
+	for _, policy := range rules.Policies {
+		parts = append(parts, fmt.Sprintf("%d %s per %ds", policy.Value, policy.Type, policy.PeriodSeconds))
+	}
+	if rules.SelectPolicy != nil {
+		parts = append(parts, "select "+string(*rules.SelectPolicy))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,155 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func describeIngress(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	ingress, err := clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load ingress", err)
+	}
+	class := "<default>"
+	if ingress.Spec.IngressClassName != nil {
+		class = *ingress.Spec.IngressClassName
+	} else if annotated := ingress.Annotations["kubernetes.io/ingress.class"]; annotated != "" {
+		class = annotated
+	}
+	lines := []string{
+		"Kind: Ingress",
+		fmt.Sprintf("Name: %s", ingress.Name),
+		fmt.Sprintf("Namespace: %s", ingress.Namespace),
+		fmt.Sprintf("Class: %s", class),
+	}
+	if ingress.Spec.DefaultBackend != nil {
+		lines = append(lines, "Default backend: "+ingressBackendText(*ingress.Spec.DefaultBackend))
+	}
+	if len(ingress.Spec.TLS) > 0 {
+		lines = append(lines, "TLS:")
+		for _, tls := range ingress.Spec.TLS {
+			hosts := strings.Join(tls.Hosts, ", ")
+			if hosts == "" {
+				hosts = "*"
+			}
+			secret := tls.SecretName
+			if secret == "" {
+				secret = "<controller default>"
+			}
+			lines = append(lines, fmt.Sprintf("- %s (secret %s)", hosts, secret))
+		}
+	}
+	if len(ingress.Spec.Rules) > 0 {
+		lines = append(lines, "Rules:")
+	}
+	for _, rule := range ingress.Spec.Rules {
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		if rule.HTTP == nil || len(rule.HTTP.Paths) == 0 {
+			lines = append(lines, fmt.Sprintf("- %s (no paths)", host))
+			continue
+		}
+		lines = append(lines, "- "+host)
+		for _, path := range rule.HTTP.Paths {
+			pathType := "ImplementationSpecific"
+			if path.PathType != nil {
+				pathType = string(*path.PathType)
+			}
+			route := path.Path
+			if route == "" {
+				route = "/"
+			}
+			lines = append(lines, fmt.Sprintf("  %s (%s) -> %s", route, pathType, ingressBackendText(path.Backend)))
+		}
+	}
+
+	var addresses []string
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		address := lb.IP
+		if lb.Hostname != "" {
+			address = lb.Hostname
+		}
+		if address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		lines = append(lines, "Load balancer: <pending>")
+	} else {
+		lines = append(lines, "Load balancer: "+strings.Join(addresses, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// relatedForIngress shows each Service the ingress routes to through the Service
+// adapter, so the drill-down lists the pods that end up serving its traffic.
+func relatedForIngress(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	ingress, err := clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load ingress relationship", err), ""
+	}
+	backends := ingressBackends(ingress)
+	if len(backends) == 0 {
+		return fmt.Sprintf("Ingress: %s\nNo backends configured.", ingress.Name), ""
+	}
+
+	var sections []string
+	var logPod string
+	seen := make(map[string]bool)
+	for _, backend := range backends {
+		if backend.Resource != nil {
+			sections = append(sections, "Resource backend: "+ingressBackendText(backend))
+			continue
+		}
+		service := backend.Service.Name
+		if seen[service] {
+			continue
+		}
+		seen[service] = true
+		related, pod := relatedForService(ctx, clientset, namespace, service)
+		sections = append(sections, related)
+		if logPod == "" {
+			logPod = pod
+		}
+	}
+	return strings.Join(sections, "\n\n"), logPod
+}
+
+// ingressBackends returns the default backend followed by the backends of every path.
+func ingressBackends(ingress *networkingv1.Ingress) []networkingv1.IngressBackend {
+	var backends []networkingv1.IngressBackend
+	if ingress.Spec.DefaultBackend != nil {
+		backends = append(backends, *ingress.Spec.DefaultBackend)
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			backends = append(backends, path.Backend)
+		}
+	}
+	return backends
+}
+
+// ingressBackendText renders a backend as service:port or Kind/name.
+func ingressBackendText(backend networkingv1.IngressBackend) string {
+	if backend.Resource != nil {
+		return fmt.Sprintf("%s/%s", backend.Resource.Kind, backend.Resource.Name)
+	}
+	if backend.Service == nil {
+		return "<none>"
+	}
+	port := backend.Service.Port.Name
+	if port == "" {
+		port = fmt.Sprint(backend.Service.Port.Number)
+	}
+	return backend.Service.Name + ":" + port
+}
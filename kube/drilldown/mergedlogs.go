@@ -0,0 +1,149 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxMergedLogPods caps how many pods of a workload merged logs are read from, so a
+// large DaemonSet does not cost one log request per node.
+const maxMergedLogPods = 10
+
+// recentLogs returns the recent logs of an object: those of all pods of a workload
+// merged, when it has several, else those of logPod.
+func recentLogs(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name, logPod string) string {
+	if pods, err := workloadPods(ctx, clientset, namespace, kind, name); err == nil && len(pods) > 1 {
+		return mergedPodLogs(ctx, clientset, namespace, pods)
+	}
+	return podLogs(ctx, clientset, namespace, logPod)
+}
+
+// workloadPods returns the pods of a Deployment, ReplicaSet, StatefulSet or DaemonSet,
+// running ones first, and none for other kinds.
+func workloadPods(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) ([]corev1.Pod, error) {
+	var selector *metav1.LabelSelector
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "deployment":
+		dep, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = dep.Spec.Selector
+	case "replicaset":
+		rs, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = rs.Spec.Selector
+	case "statefulset":
+		sts, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = sts.Spec.Selector
+	case "daemonset":
+		ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = ds.Spec.Selector
+	default:
+		return nil, nil
+	}
+	return listPodsBySelector(ctx, clientset, namespace, metav1.FormatLabelSelector(selector))
+}
+
+// mergedLogLine is a log line of one pod of a merged log.
+type mergedLogLine struct {
+	time time.Time
+	text string
+}
+
+// mergedPodLogs reads the recent logs of up to maxMergedLogPods pods concurrently and
+// interleaves them by timestamp, each line prefixed like kubectl logs --prefix.
+func mergedPodLogs(ctx context.Context, clientset *kubernetes.Clientset, namespace string, pods []corev1.Pod) string {
+	if isRestrictedAction("fetch pod logs") {
+		return "Not available in this context (fetch pod logs is not permitted)."
+	}
+	total := len(pods)
+	if len(pods) > maxMergedLogPods {
+		pods = pods[:maxMergedLogPods]
+	}
+	lines := make([][]mergedLogLine, len(pods))
+	failures := make([]string, len(pods))
+	var wg sync.WaitGroup
+	for i := range pods {
+		pod := &pods[i]
+		container := pickContainerName(pod)
+		if container == "" {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			text, err := readPodLogs(ctx, clientset, namespace, pod.Name, container, false)
+			if err != nil {
+				failures[i] = logsFailure(pod.Name, container, err)
+				return
+			}
+			lines[i] = prefixLogLines(text, fmt.Sprintf("[pod/%s/%s] ", pod.Name, container))
+		}()
+	}
+	wg.Wait()
+
+	var merged []mergedLogLine
+	for _, podLines := range lines {
+		merged = append(merged, podLines...)
+	}
+	// Stable, so lines with the same timestamp keep their pod's order.
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].time.Before(merged[j].time) })
+
+	names := make([]string, len(pods))
+	for i, pod := range pods {
+		names[i] = pod.Name
+	}
+	header := fmt.Sprintf("Pods: %s (merged by time)", strings.Join(names, ", "))
+	if total > len(pods) {
+		header = fmt.Sprintf("Pods: %s (merged by time, %d of %d pods)", strings.Join(names, ", "), len(pods), total)
+	}
+	out := []string{header}
+	for _, failure := range failures {
+		if failure != "" {
+			out = append(out, failure)
+		}
+	}
+	out = append(out, "")
+	if len(merged) == 0 {
+		return strings.Join(append(out, "No recent logs in these pods."), "\n")
+	}
+	for _, line := range merged {
+		out = append(out, line.text)
+	}
+	return strings.Join(out, "\n")
+}
+
+// prefixLogLines splits logs read with timestamps into lines prefixed with prefix. A
+// line without a timestamp sorts with the line before it.
+func prefixLogLines(text, prefix string) []mergedLogLine {
+	if text == "" {
+		return nil
+	}
+	var lines []mergedLogLine
+	var last time.Time
+	for _, line := range strings.Split(text, "\n") {
+		stamp, _, _ := strings.Cut(line, " ")
+		if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+			last = t
+		}
+		lines = append(lines, mergedLogLine{time: last, text: prefix + line})
+	}
+	return lines
+}
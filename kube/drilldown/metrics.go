@@ -0,0 +1,118 @@
+package drilldown
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+)
+
+// metricsTimeout bounds a metrics query: with metrics-server installed but unhealthy,
+// the API server waits on it before failing.
+const metricsTimeout = 3 * time.Second
+
+// podMetrics and nodeMetrics are the parts of metrics.k8s.io/v1beta1 PodMetrics and
+// NodeMetrics that describes show. The metrics API is only served where metrics-server
+// or an equivalent runs, so it is read as plain JSON instead of through its client.
+type podMetrics struct {
+	Containers []struct {
+		Name  string              `json:"name"`
+		Usage corev1.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+type nodeMetrics struct {
+	Usage corev1.ResourceList `json:"usage"`
+}
+
+// getMetrics reads the metrics.k8s.io object at path into v. It reports false when the
+// cluster serves no metrics API or has no sample for the object yet, which describes
+// treat as nothing to show.
+func getMetrics(ctx context.Context, clientset *kubernetes.Clientset, v any, path ...string) bool {
+	if isRestrictedAction("read metrics") {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, metricsTimeout)
+	defer cancel()
+	data, err := clientset.CoreV1().RESTClient().Get().
+		AbsPath(append([]string{"/apis/metrics.k8s.io/v1beta1"}, path...)...).
+		DoRaw(ctx)
+	if err != nil {
+		if IsRestricted(err) {
+			restrictedActions.Store("read metrics", true)
+		}
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// containerUsage returns the current usage of each container of a pod by name, or nil.
+func containerUsage(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) map[string]corev1.ResourceList {
+	var metrics podMetrics
+	if !getMetrics(ctx, clientset, &metrics, "namespaces", namespace, "pods", name) {
+		return nil
+	}
+	usage := make(map[string]corev1.ResourceList, len(metrics.Containers))
+	for _, container := range metrics.Containers {
+		usage[container.Name] = container.Usage
+	}
+	return usage
+}
+
+// nodeUsage returns the current usage of a node, or nil.
+func nodeUsage(ctx context.Context, clientset *kubernetes.Clientset, name string) corev1.ResourceList {
+	var metrics nodeMetrics
+	if !getMetrics(ctx, clientset, &metrics, "nodes", name) {
+		return nil
+	}
+	return metrics.Usage
+}
+
+// usageBase is what usage is compared with, e.g. a container's limits.
+type usageBase struct {
+	label     string
+	resources corev1.ResourceList
+}
+
+// usageText renders CPU and memory usage and its share of each base that sets the
+// resource, e.g. "cpu=12m (12% of request), memory=120Mi (94% of limit)".
+func usageText(usage corev1.ResourceList, bases ...usageBase) string {
+	var parts []string
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		used, ok := usage[name]
+		if !ok {
+			continue
+		}
+		text := string(name) + "=" + usageQuantityText(name, used)
+		var shares []string
+		for _, base := range bases {
+			if total, ok := base.resources[name]; ok && !total.IsZero() {
+				shares = append(shares, percentOf(used, total)+" of "+base.label)
+			}
+		}
+		if len(shares) > 0 {
+			text += " (" + strings.Join(shares, ", ") + ")"
+		}
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// usageQuantityText renders a sampled quantity readably: metrics-server reports CPU in
+// nanocores and memory in Ki, which kubectl top shows as millicores and Mi.
+func usageQuantityText(name corev1.ResourceName, q resource.Quantity) string {
+	if name == corev1.ResourceCPU {
+		return fmt.Sprintf("%dm", q.MilliValue())
+	}
+	const mi = 1 << 20
+	bytes := q.Value()
+	if bytes >= 10*1024*mi {
+		return fmt.Sprintf("%.1fGi", float64(bytes)/(1024*mi))
+	}
+	return fmt.Sprintf("%dMi", (bytes+mi/2)/mi)
+}
@@ -0,0 +1,71 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/a0xAi/kubeve/internal/format"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// describeNamespace renders a namespace's phase with its ResourceQuota usage and
+// LimitRanges.
+func describeNamespace(ctx context.Context, clientset *kubernetes.Clientset, name string) string {
+	namespace, err := clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load namespace", err)
+	}
+	lines := []string{
+		"Kind: Namespace",
+		fmt.Sprintf("Name: %s", namespace.Name),
+		fmt.Sprintf("Phase: %s", namespace.Status.Phase),
+		fmt.Sprintf("Created: %s", format.Timestamp(namespace.CreationTimestamp.Time)),
+	}
+	if namespace.DeletionTimestamp != nil {
+		lines = append(lines, fmt.Sprintf("Deleting since: %s", format.Timestamp(namespace.DeletionTimestamp.Time)))
+	}
+	for _, cond := range namespace.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		line := fmt.Sprintf("Condition %s=%s", cond.Type, cond.Status)
+		if cond.Message != "" {
+			line += ": " + cond.Message
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, quotaLines(ctx, clientset, name, "", nil)...)
+	return strings.Join(lines, "\n")
+}
+
+// relatedForNamespace counts the pods, deployments and services inside a namespace.
+func relatedForNamespace(ctx context.Context, clientset *kubernetes.Clientset, name string) string {
+	lines := []string{fmt.Sprintf("Namespace: %s", name)}
+	if pods, err := clientset.CoreV1().Pods(name).List(ctx, metav1.ListOptions{}); err != nil {
+		lines = append(lines, failure("list pods", err))
+	} else if len(pods.Items) == 0 {
+		lines = append(lines, "Pods: 0")
+	} else {
+		lines = append(lines, fmt.Sprintf("Pods: %d (%s)", len(pods.Items), podPhaseCounts(pods.Items)))
+	}
+	if deployments, err := clientset.AppsV1().Deployments(name).List(ctx, metav1.ListOptions{}); err != nil {
+		lines = append(lines, failure("list deployments", err))
+	} else {
+		available := 0
+		for _, deployment := range deployments.Items {
+			if deployment.Status.AvailableReplicas >= valueOrDefault(deployment.Spec.Replicas) {
+				available++
+			}
+		}
+		lines = append(lines, fmt.Sprintf("Deployments: %d (%d fully available)", len(deployments.Items), available))
+	}
+	if services, err := clientset.CoreV1().Services(name).List(ctx, metav1.ListOptions{}); err != nil {
+		lines = append(lines, failure("list services", err))
+	} else {
+		lines = append(lines, fmt.Sprintf("Services: %d", len(services.Items)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,185 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/a0xAi/kubeve/internal/format"
+	"github.com/a0xAi/kubeve/kube"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxRelatedEventObjects caps the owners and descendants whose events are read, one
+// request each, so drilling into a Deployment with many pods stays quick.
+const maxRelatedEventObjects = 20
+
+// maxRelatedEvents is how many events of the owner chain a drill-down shows.
+const maxRelatedEvents = 10
+
+// ownerChainKinds are the kinds whose owners and descendants are followed.
+var ownerChainKinds = map[string]bool{
+	"Pod": true, "ReplicaSet": true, "Deployment": true, "StatefulSet": true,
+	"DaemonSet": true, "Job": true, "CronJob": true,
+}
+
+// relatedObjects returns the objects a drill-down links to: an object's controllers,
+// e.g. the ReplicaSet and Deployment of a pod, and what it owns, e.g. the ReplicaSets
+// and pods of a Deployment, or the pods a Service selects.
+func relatedObjects(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) []kube.RelatedObject {
+	ref, err := ParseObjectRef(kind + "/" + name)
+	if err != nil || namespace == "" || (!ownerChainKinds[ref.Kind] && ref.Kind != "Service") {
+		return nil
+	}
+	var related []kube.RelatedObject
+	for _, owner := range ownerChain(ctx, clientset, namespace, ref) {
+		related = append(related, kube.RelatedObject{Kind: owner.Kind, Name: owner.Name, Relation: "owner"})
+	}
+	relation := "owned"
+	if ref.Kind == "Service" {
+		relation = "selected"
+	}
+	for _, owned := range ownedObjects(ctx, clientset, namespace, ref) {
+		related = append(related, kube.RelatedObject{Kind: owned.Kind, Name: owned.Name, Relation: relation})
+	}
+	return related
+}
+
+// relatedObjectEvents returns the events of the owners and owned objects among related,
+// merged newest first, each with the object it is about. The inspected object's own
+// events are left to recentObjectEvents. It returns "" when they have no events.
+func relatedObjectEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace string, related []kube.RelatedObject) string {
+	var refs []ObjectRef
+	for _, object := range related {
+		if object.Relation == "owner" || object.Relation == "owned" {
+			refs = append(refs, ObjectRef{Kind: object.Kind, Name: object.Name})
+		}
+	}
+	if len(refs) > maxRelatedEventObjects {
+		refs = refs[:maxRelatedEventObjects]
+	}
+	if len(refs) == 0 {
+		return ""
+	}
+
+	type objectEvent struct {
+		ref   ObjectRef
+		event corev1.Event
+	}
+	results := make([][]corev1.Event, len(refs))
+	var wg sync.WaitGroup
+	for i, related := range refs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = listObjectEvents(ctx, clientset, namespace, related.Kind, related.Name)
+		}()
+	}
+	wg.Wait()
+
+	var merged []objectEvent
+	for i, events := range results {
+		for _, event := range events {
+			merged = append(merged, objectEvent{ref: refs[i], event: event})
+		}
+	}
+	if len(merged) == 0 {
+		return ""
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return kube.EventTime(merged[i].event).After(kube.EventTime(merged[j].event))
+	})
+
+	shown := merged
+	if len(shown) > maxRelatedEvents {
+		shown = shown[:maxRelatedEvents]
+	}
+	lines := make([]string, 0, len(shown)+1)
+	for _, item := range shown {
+		lines = append(lines, fmt.Sprintf(
+			"- %s %s/%s %s: %s",
+			format.Clock(kube.EventTime(item.event)),
+			item.event.Type,
+			item.event.Reason,
+			item.ref,
+			trimString(item.event.Message, 140),
+		))
+	}
+	if older := len(merged) - len(shown); older > 0 {
+		lines = append(lines, fmt.Sprintf("- and %d older", older))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ownerChain returns the controllers of an object from the nearest up, e.g. the
+// ReplicaSet and then the Deployment of a pod.
+func ownerChain(ctx context.Context, clientset *kubernetes.Clientset, namespace string, ref ObjectRef) []ObjectRef {
+	var chain []ObjectRef
+	// Controllers nest a few levels at most; the bound guards against cycles.
+	for len(chain) < 4 {
+		owner := controllerOf(ctx, clientset, namespace, ref)
+		if owner == nil {
+			break
+		}
+		ref = ObjectRef{Kind: owner.Kind, Name: owner.Name}
+		chain = append(chain, ref)
+	}
+	return chain
+}
+
+// controllerOf returns the controller of a Pod, ReplicaSet or Job, or nil.
+func controllerOf(ctx context.Context, clientset *kubernetes.Clientset, namespace string, ref ObjectRef) *metav1.OwnerReference {
+	var meta metav1.Object
+	switch ref.Kind {
+	case "Pod":
+		pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		meta = pod
+	case "ReplicaSet":
+		rs, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		meta = rs
+	case "Job":
+		job, err := clientset.BatchV1().Jobs(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		meta = job
+	default:
+		return nil
+	}
+	return metav1.GetControllerOf(meta)
+}
+
+// ownedObjects returns the objects an object owns transitively, ReplicaSets and Jobs
+// before pods, or the pods a Service selects.
+func ownedObjects(ctx context.Context, clientset *kubernetes.Clientset, namespace string, ref ObjectRef) []ObjectRef {
+	if ref.Kind == "Pod" {
+		return nil
+	}
+	tree, err := ResolveDescendants(ctx, clientset, namespace, ref)
+	if err != nil {
+		return nil
+	}
+	owned := make([]ObjectRef, 0, len(tree))
+	for object := range tree {
+		if object != ref {
+			owned = append(owned, object)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		if pi, pj := owned[i].Kind == "Pod", owned[j].Kind == "Pod"; pi != pj {
+			return pj
+		}
+		return owned[i].String() < owned[j].String()
+	})
+	return owned
+}
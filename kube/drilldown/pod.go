@@ -0,0 +1,452 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/a0xAi/kubeve/internal/format"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// describePod renders a pod much like kubectl describe: its placement and scheduling
+// constraints, every container with its state, last termination, resources and probes,
+// the pod's conditions and its volumes. Containers show their current CPU and memory
+// usage when the cluster serves the metrics API.
+func describePod(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load pod", err)
+	}
+
+	lines := []string{
+		"Kind: Pod",
+		fmt.Sprintf("Name: %s", pod.Name),
+		fmt.Sprintf("Namespace: %s", pod.Namespace),
+		fmt.Sprintf("Phase: %s", pod.Status.Phase),
+	}
+	if pod.Status.Reason != "" {
+		lines = append(lines, fmt.Sprintf("Reason: %s", pod.Status.Reason))
+	}
+	if message := strings.TrimSpace(pod.Status.Message); message != "" {
+		lines = append(lines, fmt.Sprintf("Message: %s", trimString(message, 300)))
+	}
+	lines = append(lines,
+		fmt.Sprintf("Node: %s", pod.Spec.NodeName),
+		fmt.Sprintf("Pod IP: %s", pod.Status.PodIP),
+		fmt.Sprintf("Host IP: %s", pod.Status.HostIP),
+	)
+	if pod.Status.StartTime != nil {
+		lines = append(lines, fmt.Sprintf("Started: %s", format.Timestamp(pod.Status.StartTime.Time)))
+	}
+	if pod.DeletionTimestamp != nil {
+		lines = append(lines, fmt.Sprintf("Terminating since: %s", format.Timestamp(pod.DeletionTimestamp.Time)))
+	}
+	if len(pod.OwnerReferences) > 0 {
+		owners := make([]string, 0, len(pod.OwnerReferences))
+		for _, ref := range pod.OwnerReferences {
+			owners = append(owners, fmt.Sprintf("%s/%s", ref.Kind, ref.Name))
+		}
+		lines = append(lines, "Owners: "+strings.Join(owners, ", "))
+	}
+	if pod.Spec.ServiceAccountName != "" {
+		lines = append(lines, fmt.Sprintf("Service account: %s", pod.Spec.ServiceAccountName))
+	}
+	if pod.Spec.PriorityClassName != "" {
+		lines = append(lines, fmt.Sprintf("Priority class: %s", pod.Spec.PriorityClassName))
+	}
+	if pod.Status.QOSClass != "" {
+		lines = append(lines, fmt.Sprintf("QoS class: %s", pod.Status.QOSClass))
+	}
+	if len(pod.Spec.NodeSelector) > 0 {
+		lines = append(lines, "Node selector: "+labelsText(pod.Spec.NodeSelector))
+	}
+	if len(pod.Spec.Tolerations) > 0 {
+		lines = append(lines, "Tolerations:")
+		for _, toleration := range pod.Spec.Tolerations {
+			lines = append(lines, "- "+tolerationText(toleration))
+		}
+	}
+	lines = append(lines, affinityLines(pod.Spec.Affinity)...)
+
+	statuses := make(map[string]corev1.ContainerStatus)
+	for _, cs := range pod.Status.InitContainerStatuses {
+		statuses["init/"+cs.Name] = cs
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		statuses[cs.Name] = cs
+	}
+	if len(pod.Spec.InitContainers) > 0 {
+		lines = append(lines, "Init containers:")
+		for _, container := range pod.Spec.InitContainers {
+			cs, ok := statuses["init/"+container.Name]
+			lines = append(lines, containerLines(container, cs, ok, nil)...)
+		}
+	}
+	usage := containerUsage(ctx, clientset, namespace, name)
+	lines = append(lines, "Containers:")
+	for _, container := range pod.Spec.Containers {
+		cs, ok := statuses[container.Name]
+		lines = append(lines, containerLines(container, cs, ok, usage[container.Name])...)
+	}
+
+	if len(pod.Status.Conditions) > 0 {
+		lines = append(lines, "Conditions:")
+		for _, condition := range pod.Status.Conditions {
+			line := fmt.Sprintf("- %s: %s", condition.Type, condition.Status)
+			if condition.Reason != "" {
+				line += " (" + condition.Reason + ")"
+			}
+			if message := strings.TrimSpace(condition.Message); message != "" && condition.Status != corev1.ConditionTrue {
+				line += ": " + trimString(message, 200)
+			}
+			lines = append(lines, line)
+		}
+	}
+	if len(pod.Spec.Volumes) > 0 {
+		lines = append(lines, "Volumes:")
+		for _, volume := range pod.Spec.Volumes {
+			lines = append(lines, fmt.Sprintf("- %s: %s", volume.Name, volumeSourceText(volume.VolumeSource)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// containerLines describes one container of a pod, with its status when the kubelet
+// reported one and its usage when metrics-server sampled it.
+func containerLines(container corev1.Container, cs corev1.ContainerStatus, hasStatus bool, usage corev1.ResourceList) []string {
+	lines := []string{fmt.Sprintf("- %s (%s)", container.Name, trimString(container.Image, 70))}
+	if hasStatus {
+		lines = append(lines,
+			"  State: "+containerStateText(cs.State),
+			fmt.Sprintf("  Ready: %t, restarts: %d", cs.Ready, cs.RestartCount),
+		)
+		if cs.LastTerminationState.Terminated != nil {
+			lines = append(lines, "  Last state: "+containerStateText(cs.LastTerminationState))
+		}
+	}
+	if len(container.Ports) > 0 {
+		ports := make([]string, 0, len(container.Ports))
+		for _, port := range container.Ports {
+			text := fmt.Sprintf("%d/%s", port.ContainerPort, port.Protocol)
+			if port.Name != "" {
+				text = port.Name + " " + text
+			}
+			ports = append(ports, text)
+		}
+		lines = append(lines, "  Ports: "+strings.Join(ports, ", "))
+	}
+	if requests := resourceListText(container.Resources.Requests); requests != "" {
+		lines = append(lines, "  Requests: "+requests)
+	}
+	if limits := resourceListText(container.Resources.Limits); limits != "" {
+		lines = append(lines, "  Limits: "+limits)
+	}
+	if text := usageText(usage, usageBase{"request", container.Resources.Requests}, usageBase{"limit", container.Resources.Limits}); text != "" {
+		lines = append(lines, "  Usage: "+text)
+	}
+	for _, probe := range []struct {
+		name  string
+		probe *corev1.Probe
+	}{
+		{"Liveness", container.LivenessProbe},
+		{"Readiness", container.ReadinessProbe},
+		{"Startup", container.StartupProbe},
+	} {
+		if probe.probe != nil {
+			lines = append(lines, fmt.Sprintf("  %s: %s", probe.name, probeText(probe.probe)))
+		}
+	}
+	for _, mount := range container.VolumeMounts {
+		line := fmt.Sprintf("  Mount: %s from %s", mount.MountPath, mount.Name)
+		if mount.SubPath != "" {
+			line += " (subPath " + mount.SubPath + ")"
+		}
+		if mount.ReadOnly {
+			line += " (ro)"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// containerStateText renders a container state as kubectl describe does, e.g.
+// "Terminated (OOMKilled), exit code 137, finished ...".
+func containerStateText(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running since " + format.Timestamp(state.Running.StartedAt.Time)
+	case state.Waiting != nil:
+		text := "Waiting"
+		if state.Waiting.Reason != "" {
+			text += " (" + state.Waiting.Reason + ")"
+		}
+		if message := strings.TrimSpace(state.Waiting.Message); message != "" {
+			text += ": " + trimString(message, 200)
+		}
+		return text
+	case state.Terminated != nil:
+		term := state.Terminated
+		text := "Terminated"
+		if term.Reason != "" {
+			text += " (" + term.Reason + ")"
+		}
+		text += fmt.Sprintf(", exit code %d", term.ExitCode)
+		if term.Signal != 0 {
+			text += fmt.Sprintf(", signal %d", term.Signal)
+		}
+		if !term.FinishedAt.IsZero() {
+			text += ", finished " + format.Timestamp(term.FinishedAt.Time)
+		}
+		if message := strings.TrimSpace(term.Message); message != "" {
+			text += ": " + trimString(message, 200)
+		}
+		return text
+	default:
+		return "unknown"
+	}
+}
+
+// resourceListText renders requests or limits in name order, e.g. "cpu=100m, memory=128Mi".
+func resourceListText(resources corev1.ResourceList) string {
+	if len(resources) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		quantity := resources[corev1.ResourceName(name)]
+		parts = append(parts, name+"="+quantity.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// probeText renders a probe like kubectl describe, e.g.
+// "http-get :8080/healthz delay=5s timeout=1s period=10s #success=1 #failure=3".
+func probeText(probe *corev1.Probe) string {
+	var action string
+	switch handler := probe.ProbeHandler; {
+	case handler.HTTPGet != nil:
+		scheme := strings.ToLower(string(handler.HTTPGet.Scheme))
+		if scheme == "" {
+			scheme = "http"
+		}
+		action = fmt.Sprintf("http-get %s://%s:%s%s", scheme, handler.HTTPGet.Host, handler.HTTPGet.Port.String(), handler.HTTPGet.Path)
+	case handler.TCPSocket != nil:
+		action = fmt.Sprintf("tcp-socket %s:%s", handler.TCPSocket.Host, handler.TCPSocket.Port.String())
+	case handler.GRPC != nil:
+		action = fmt.Sprintf("grpc <pod>:%d", handler.GRPC.Port)
+		if handler.GRPC.Service != nil && *handler.GRPC.Service != "" {
+			action += " " + *handler.GRPC.Service
+		}
+	case handler.Exec != nil:
+		action = "exec [" + trimString(strings.Join(handler.Exec.Command, " "), 80) + "]"
+	default:
+		action = "unknown"
+	}
+	// Unset fields read as the API server's defaults.
+	timeout, period, success, failure := probe.TimeoutSeconds, probe.PeriodSeconds, probe.SuccessThreshold, probe.FailureThreshold
+	if timeout == 0 {
+		timeout = 1
+	}
+	if period == 0 {
+		period = 10
+	}
+	if success == 0 {
+		success = 1
+	}
+	if failure == 0 {
+		failure = 3
+	}
+	return fmt.Sprintf("%s delay=%ds timeout=%ds period=%ds #success=%d #failure=%d",
+		action, probe.InitialDelaySeconds, timeout, period, success, failure)
+}
+
+// tolerationText renders a toleration as kubectl describe does, e.g.
+// "node.kubernetes.io/not-ready:NoExecute op=Exists for 300s".
+func tolerationText(toleration corev1.Toleration) string {
+	text := toleration.Key
+	if toleration.Value != "" {
+		text += "=" + toleration.Value
+	}
+	if toleration.Effect != "" {
+		text += ":" + string(toleration.Effect)
+	}
+	if toleration.Operator == corev1.TolerationOpExists {
+		if text == "" {
+			text = "<all taints>"
+		}
+		text += " op=Exists"
+	}
+	if toleration.TolerationSeconds != nil {
+		text += fmt.Sprintf(" for %ds", *toleration.TolerationSeconds)
+	}
+	return text
+}
+
+// affinityLines renders a pod's node affinity and pod (anti-)affinity terms.
+func affinityLines(affinity *corev1.Affinity) []string {
+	if affinity == nil {
+		return nil
+	}
+	var lines []string
+	if node := affinity.NodeAffinity; node != nil {
+		if required := node.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			for _, term := range required.NodeSelectorTerms {
+				lines = append(lines, "- node, required: "+nodeSelectorTermText(term))
+			}
+		}
+		for _, preferred := range node.PreferredDuringSchedulingIgnoredDuringExecution {
+			lines = append(lines, fmt.Sprintf("- node, preferred (weight %d): %s", preferred.Weight, nodeSelectorTermText(preferred.Preference)))
+		}
+	}
+	for _, kind := range []struct {
+		name string
+		pods *corev1.PodAffinity
+		anti *corev1.PodAntiAffinity
+	}{
+		{name: "pod affinity", pods: affinity.PodAffinity},
+		{name: "pod anti-affinity", anti: affinity.PodAntiAffinity},
+	} {
+		var required []corev1.PodAffinityTerm
+		var preferred []corev1.WeightedPodAffinityTerm
+		switch {
+		case kind.pods != nil:
+			required, preferred = kind.pods.RequiredDuringSchedulingIgnoredDuringExecution, kind.pods.PreferredDuringSchedulingIgnoredDuringExecution
+		case kind.anti != nil:
+			required, preferred = kind.anti.RequiredDuringSchedulingIgnoredDuringExecution, kind.anti.PreferredDuringSchedulingIgnoredDuringExecution
+		}
+		for _, term := range required {
+			lines = append(lines, fmt.Sprintf("- %s, required: %s", kind.name, podAffinityTermText(term)))
+		}
+		for _, weighted := range preferred {
+			lines = append(lines, fmt.Sprintf("- %s, preferred (weight %d): %s", kind.name, weighted.Weight, podAffinityTermText(weighted.PodAffinityTerm)))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return append([]string{"Affinity:"}, lines...)
+}
+
+func nodeSelectorTermText(term corev1.NodeSelectorTerm) string {
+	var parts []string
+	for _, expr := range term.MatchExpressions {
+		parts = append(parts, selectorRequirementText(expr.Key, string(expr.Operator), expr.Values))
+	}
+	for _, field := range term.MatchFields {
+		parts = append(parts, selectorRequirementText(field.Key, string(field.Operator), field.Values))
+	}
+	if len(parts) == 0 {
+		return "<any node>"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func podAffinityTermText(term corev1.PodAffinityTerm) string {
+	selector := "<all pods>"
+	if term.LabelSelector != nil {
+		selector = metav1.FormatLabelSelector(term.LabelSelector)
+	}
+	text := fmt.Sprintf("pods %s per %s", selector, term.TopologyKey)
+	if len(term.Namespaces) > 0 {
+		text += " in " + strings.Join(term.Namespaces, ",")
+	}
+	return text
+}
+
+func selectorRequirementText(key, operator string, values []string) string {
+	switch corev1.NodeSelectorOperator(operator) {
+	case corev1.NodeSelectorOpExists:
+		return key
+	case corev1.NodeSelectorOpDoesNotExist:
+		return "!" + key
+	default:
+		return fmt.Sprintf("%s %s [%s]", key, strings.ToLower(operator), strings.Join(values, ","))
+	}
+}
+
+// labelsText renders a label map in key order, e.g. "disktype=ssd, zone=a".
+func labelsText(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+labels[key])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// volumeSourceText summarizes where a volume comes from, e.g. "Secret web-tls" or
+// "PersistentVolumeClaim data-0".
+func volumeSourceText(source corev1.VolumeSource) string {
+	switch {
+	case source.ConfigMap != nil:
+		return "ConfigMap " + source.ConfigMap.Name + optionalText(source.ConfigMap.Optional)
+	case source.Secret != nil:
+		return "Secret " + source.Secret.SecretName + optionalText(source.Secret.Optional)
+	case source.PersistentVolumeClaim != nil:
+		text := "PersistentVolumeClaim " + source.PersistentVolumeClaim.ClaimName
+		if source.PersistentVolumeClaim.ReadOnly {
+			text += " (ro)"
+		}
+		return text
+	case source.EmptyDir != nil:
+		text := "EmptyDir"
+		if source.EmptyDir.Medium != "" {
+			text += " (" + string(source.EmptyDir.Medium) + ")"
+		}
+		if source.EmptyDir.SizeLimit != nil {
+			text += ", size limit " + source.EmptyDir.SizeLimit.String()
+		}
+		return text
+	case source.HostPath != nil:
+		return "HostPath " + source.HostPath.Path
+	case source.Projected != nil:
+		var parts []string
+		for _, projection := range source.Projected.Sources {
+			switch {
+			case projection.ConfigMap != nil:
+				parts = append(parts, "ConfigMap "+projection.ConfigMap.Name)
+			case projection.Secret != nil:
+				parts = append(parts, "Secret "+projection.Secret.Name)
+			case projection.ServiceAccountToken != nil:
+				parts = append(parts, "service account token")
+			case projection.DownwardAPI != nil:
+				parts = append(parts, "downward API")
+			case projection.ClusterTrustBundle != nil:
+				parts = append(parts, "cluster trust bundle")
+			}
+		}
+		return "Projected (" + strings.Join(parts, ", ") + ")"
+	case source.DownwardAPI != nil:
+		return "DownwardAPI"
+	case source.CSI != nil:
+		return "CSI " + source.CSI.Driver
+	case source.Ephemeral != nil:
+		return "Ephemeral volume claim"
+	case source.NFS != nil:
+		return fmt.Sprintf("NFS %s:%s", source.NFS.Server, source.NFS.Path)
+	case source.Image != nil:
+		return "Image " + source.Image.Reference
+	default:
+		return "other"
+	}
+}
+
+func optionalText(optional *bool) string {
+	if boolOrDefault(optional) {
+		return " (optional)"
+	}
+	return ""
+}
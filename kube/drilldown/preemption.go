@@ -0,0 +1,172 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	// preemptedMessage matches the scheduler's "Preempted by pod <uid> on node <node>" and
+	// the older "Preempted by <namespace>/<name> on node <node>".
+	preemptedMessage = regexp.MustCompile(`Preempted by (?:a pod|pod (\S+)|(\S+/\S+)) on node (\S+)`)
+	evictedMessage   = regexp.MustCompile(`(?i)(the node was low on resource|evict)`)
+)
+
+// preemptorPageSize is how many pods are listed per request while looking for a
+// preempting pod by UID.
+const preemptorPageSize = 500
+
+// pressureConditions are the node conditions behind kubelet evictions.
+var pressureConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+}
+
+// diagnosePreemption explains a Preempted or Evicted pod: its priority, the pod that
+// preempted it and the resource pressure of its node, to tell capacity problems apart
+// from priority misconfiguration.
+func diagnosePreemption(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name, message string) string {
+	if strings.ToLower(kind) != "pod" {
+		return ""
+	}
+	preempted := preemptedMessage.FindStringSubmatch(message)
+	if preempted == nil && !evictedMessage.MatchString(message) {
+		return ""
+	}
+
+	var lines []string
+	nodeName := ""
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		lines = append(lines, fmt.Sprintf("Pod %s/%s: %s", namespace, name, failure("load pod", err)))
+	} else {
+		lines = append(lines, "Pod "+priorityLine(ctx, clientset, pod))
+		nodeName = pod.Spec.NodeName
+	}
+
+	if preempted != nil {
+		nodeName = preempted[3]
+		switch {
+		case preempted[1] != "":
+			lines = append(lines, preemptorLine(ctx, clientset, types.UID(preempted[1]), "", ""))
+		case preempted[2] != "":
+			ns, podName, _ := strings.Cut(preempted[2], "/")
+			lines = append(lines, preemptorLine(ctx, clientset, "", ns, podName))
+		default:
+			lines = append(lines, "Preemptor: not named in the message")
+		}
+	}
+
+	if nodeName != "" {
+		lines = append(lines, nodePressureLines(ctx, clientset, nodeName)...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func priorityLine(ctx context.Context, clientset *kubernetes.Clientset, pod *corev1.Pod) string {
+	line := fmt.Sprintf("%s/%s: priority %d", pod.Namespace, pod.Name, valueOrDefault(pod.Spec.Priority))
+	if pod.Spec.PriorityClassName == "" {
+		return line + " (no priorityClassName)"
+	}
+	line += " from " + pod.Spec.PriorityClassName
+	if class, err := clientset.SchedulingV1().PriorityClasses().Get(ctx, pod.Spec.PriorityClassName, metav1.GetOptions{}); err == nil && class.PreemptionPolicy != nil {
+		line += ", preemptionPolicy " + string(*class.PreemptionPolicy)
+	}
+	return line
+}
+
+// preemptorLine finds the preempting pod by UID or by namespace and name.
+func preemptorLine(ctx context.Context, clientset *kubernetes.Clientset, uid types.UID, namespace, name string) string {
+	if uid == "" {
+		pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Sprintf("Preemptor %s/%s: %s", namespace, name, failure("load pod", err))
+		}
+		return "Preemptor " + priorityLine(ctx, clientset, pod)
+	}
+	// Served from the watch cache, in pages where the server supports that, until the pod
+	// turns up. A page continues from the first one's snapshot, so the rest carry no
+	// resourceVersion.
+	opts := metav1.ListOptions{ResourceVersion: "0", Limit: preemptorPageSize}
+	for {
+		pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return fmt.Sprintf("Preemptor %s: %s", uid, failure("list pods", err))
+		}
+		for i := range pods.Items {
+			if pods.Items[i].UID == uid {
+				return "Preemptor " + priorityLine(ctx, clientset, &pods.Items[i])
+			}
+		}
+		if pods.Continue == "" {
+			return fmt.Sprintf("Preemptor %s: no longer exists", uid)
+		}
+		opts.ResourceVersion, opts.Continue = "", pods.Continue
+	}
+}
+
+// nodePressureLines reports the node's pressure conditions and how much of its
+// allocatable CPU and memory is requested.
+func nodePressureLines(ctx context.Context, clientset *kubernetes.Clientset, nodeName string) []string {
+	node, err := clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return []string{fmt.Sprintf("Node %s: %s", nodeName, failure("load node", err))}
+	}
+	lines := []string{"Node " + node.Name}
+	var pressure []string
+	for _, cond := range node.Status.Conditions {
+		for _, want := range pressureConditions {
+			if cond.Type == want && cond.Status == corev1.ConditionTrue {
+				pressure = append(pressure, fmt.Sprintf("%s (%s)", cond.Type, cond.Message))
+			}
+		}
+	}
+	if len(pressure) == 0 {
+		lines = append(lines, "  No memory, disk or PID pressure reported now.")
+	} else {
+		lines = append(lines, "  Pressure: "+strings.Join(pressure, "; "))
+	}
+
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node.Name).String(),
+	})
+	if err != nil {
+		return append(lines, "  "+failure("list node pods", err))
+	}
+	requested := corev1.ResourceList{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			for name, value := range container.Resources.Requests {
+				total := requested[name]
+				total.Add(value)
+				requested[name] = total
+			}
+		}
+	}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		allocatable := node.Status.Allocatable[name]
+		total := requested[name]
+		lines = append(lines, fmt.Sprintf("  %s requested: %s of %s allocatable (%s)", name, total.String(), allocatable.String(), percentOf(total, allocatable)))
+	}
+	return lines
+}
+
+func percentOf(part, whole resource.Quantity) string {
+	if whole.MilliValue() == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", part.MilliValue()*100/whole.MilliValue())
+}
@@ -0,0 +1,287 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func describePVC(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load persistentvolumeclaim", err)
+	}
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	modes := make([]string, 0, len(pvc.Spec.AccessModes))
+	for _, mode := range pvc.Spec.AccessModes {
+		modes = append(modes, string(mode))
+	}
+	lines := []string{
+		"Kind: PersistentVolumeClaim",
+		fmt.Sprintf("Name: %s", pvc.Name),
+		fmt.Sprintf("Namespace: %s", pvc.Namespace),
+		fmt.Sprintf("Phase: %s", pvc.Status.Phase),
+		fmt.Sprintf("Storage class: %s", valueOrEmpty(pvc.Spec.StorageClassName)),
+		fmt.Sprintf("Requested: %s", requested.String()),
+		fmt.Sprintf("Capacity: %s", capacity.String()),
+		fmt.Sprintf("Access modes: %s", strings.Join(modes, ", ")),
+	}
+	if pvc.Spec.VolumeName != "" {
+		lines = append(lines, fmt.Sprintf("Volume: %s", pvc.Spec.VolumeName))
+	}
+	for _, cond := range pvc.Status.Conditions {
+		line := fmt.Sprintf("Condition %s=%s", cond.Type, cond.Status)
+		if cond.Message != "" {
+			line += ": " + cond.Message
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// relatedForPVC shows the volume bound to the claim and lists the pods mounting it.
+func relatedForPVC(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, string) {
+	var lines []string
+	if pvc, err := clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil && pvc.Spec.VolumeName != "" {
+		if pv, err := clientset.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{}); err != nil {
+			lines = append(lines, fmt.Sprintf("Volume %s: %s", pvc.Spec.VolumeName, failure("load persistentvolume", err)))
+		} else {
+			lines = append(lines, fmt.Sprintf("Volume: %s (%s, reclaim policy %s, %s)", pv.Name, pv.Status.Phase, pv.Spec.PersistentVolumeReclaimPolicy, volumeSource(pv)))
+		}
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return strings.Join(append(lines, failure("list pods", err)), "\n"), ""
+	}
+	users := podsMountingClaim(pods.Items, name)
+	if len(users) == 0 {
+		return strings.Join(append(lines, "No pods mount this claim."), "\n"), ""
+	}
+	return strings.Join(append(lines, summarizePods(users)...), "\n"), pickPodForLogs(users)
+}
+
+func podsMountingClaim(pods []corev1.Pod, claim string) []corev1.Pod {
+	var users []corev1.Pod
+	for _, pod := range pods {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claim {
+				users = append(users, pod)
+				break
+			}
+		}
+	}
+	return users
+}
+
+func describePV(ctx context.Context, clientset *kubernetes.Clientset, name string) string {
+	pv, err := clientset.CoreV1().PersistentVolumes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load persistentvolume", err)
+	}
+	capacity := pv.Spec.Capacity[corev1.ResourceStorage]
+	modes := make([]string, 0, len(pv.Spec.AccessModes))
+	for _, mode := range pv.Spec.AccessModes {
+		modes = append(modes, string(mode))
+	}
+	lines := []string{
+		"Kind: PersistentVolume",
+		fmt.Sprintf("Name: %s", pv.Name),
+		fmt.Sprintf("Phase: %s", pv.Status.Phase),
+		fmt.Sprintf("Storage class: %s", pv.Spec.StorageClassName),
+		fmt.Sprintf("Capacity: %s", capacity.String()),
+		fmt.Sprintf("Access modes: %s", strings.Join(modes, ", ")),
+		fmt.Sprintf("Reclaim policy: %s", pv.Spec.PersistentVolumeReclaimPolicy),
+		fmt.Sprintf("Source: %s", volumeSource(pv)),
+	}
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		lines = append(lines, fmt.Sprintf("Claim: %s/%s", ref.Namespace, ref.Name))
+	}
+	if pv.Status.Reason != "" || pv.Status.Message != "" {
+		lines = append(lines, strings.TrimSpace(fmt.Sprintf("Status: %s %s", pv.Status.Reason, pv.Status.Message)))
+	}
+	if pv.Status.Phase == corev1.VolumeReleased && pv.Spec.PersistentVolumeReclaimPolicy == corev1.PersistentVolumeReclaimRetain {
+		lines = append(lines, "The claim was deleted and the volume is retained: it will not bind to a new claim until an admin clears its claimRef or deletes it.")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// relatedForPV shows the claim bound to the volume and the pods mounting it. The pods
+// live in the claim's namespace, so no log pod is returned for the cluster-scoped volume.
+func relatedForPV(ctx context.Context, clientset *kubernetes.Clientset, name string) string {
+	pv, err := clientset.CoreV1().PersistentVolumes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return failure("load persistentvolume relationship", err)
+	}
+	ref := pv.Spec.ClaimRef
+	if ref == nil {
+		return "Not bound to a claim."
+	}
+	lines := []string{fmt.Sprintf("Claim: %s/%s", ref.Namespace, ref.Name)}
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return strings.Join(append(lines, failure("load persistentvolumeclaim", err)), "\n")
+	}
+	lines[0] += fmt.Sprintf(" (%s)", pvc.Status.Phase)
+	pods, err := clientset.CoreV1().Pods(ref.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return strings.Join(append(lines, failure("list pods", err)), "\n")
+	}
+	users := podsMountingClaim(pods.Items, ref.Name)
+	if len(users) == 0 {
+		return strings.Join(append(lines, "No pods mount this claim."), "\n")
+	}
+	return strings.Join(append(lines, summarizePods(users)...), "\n")
+}
+
+// volumeSource names the backend of a volume, e.g. "CSI ebs.csi.aws.com vol-0abc".
+func volumeSource(pv *corev1.PersistentVolume) string {
+	source := pv.Spec.PersistentVolumeSource
+	switch {
+	case source.CSI != nil:
+		return fmt.Sprintf("CSI %s %s", source.CSI.Driver, source.CSI.VolumeHandle)
+	case source.NFS != nil:
+		return fmt.Sprintf("NFS %s:%s", source.NFS.Server, source.NFS.Path)
+	case source.HostPath != nil:
+		return "HostPath " + source.HostPath.Path
+	case source.Local != nil:
+		return "Local " + source.Local.Path
+	case source.ISCSI != nil:
+		return fmt.Sprintf("iSCSI %s %s", source.ISCSI.TargetPortal, source.ISCSI.IQN)
+	case source.FC != nil:
+		return "FC"
+	default:
+		return "other"
+	}
+}
+
+// diagnosePVC looks for the usual reasons provisioning or resizing fails: a storage
+// ResourceQuota that the request does not fit in, a missing StorageClass, or a resize
+// on a class without allowVolumeExpansion.
+func diagnosePVC(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) string {
+	pvc, err := clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity, bound := pvc.Status.Capacity[corev1.ResourceStorage]
+	resizing := bound && requested.Cmp(capacity) > 0
+	if pvc.Status.Phase == corev1.ClaimBound && !resizing {
+		return ""
+	}
+
+	var lines, causes []string
+	if resizing {
+		lines = append(lines, fmt.Sprintf("Resize pending: %s requested, %s provisioned", requested.String(), capacity.String()))
+	}
+
+	className := valueOrEmpty(pvc.Spec.StorageClassName)
+	if className == "" {
+		lines = append(lines, "Storage class: none set")
+		if !resizing {
+			causes = append(causes, "No storage class and no default class: the claim waits for a pre-provisioned PersistentVolume.")
+		}
+	} else if class, err := clientset.StorageV1().StorageClasses().Get(ctx, className, metav1.GetOptions{}); err != nil {
+		lines = append(lines, fmt.Sprintf("Storage class %s: %s", className, failure("load storageclass", err)))
+	} else {
+		lines = append(lines, storageClassLines(class)...)
+		if resizing && !boolOrDefault(class.AllowVolumeExpansion) {
+			causes = append(causes, fmt.Sprintf("StorageClass %s has allowVolumeExpansion=false, so the claim cannot grow.", class.Name))
+		}
+		if !resizing && class.VolumeBindingMode != nil && *class.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+			causes = append(causes, "The class binds on first consumer: the volume is provisioned only once a pod using the claim is scheduled.")
+		}
+	}
+
+	quotas, err := clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		lines = append(lines, failure("list resourcequotas", err))
+	} else {
+		// A pending claim adds its whole request to the quota, a resize only the difference.
+		extra := requested.DeepCopy()
+		if resizing {
+			extra.Sub(capacity)
+		}
+		for _, quota := range quotas.Items {
+			for _, check := range storageQuotaKeys(className) {
+				hard, ok := quota.Status.Hard[check.name]
+				if !ok || (check.count && resizing) {
+					continue
+				}
+				used := quota.Status.Used[check.name]
+				add := extra
+				if check.count {
+					add = *resource.NewQuantity(1, resource.DecimalSI)
+				}
+				lines = append(lines, fmt.Sprintf("Quota %s %s: used %s of %s", quota.Name, check.name, used.String(), hard.String()))
+				total := used.DeepCopy()
+				total.Add(add)
+				if total.Cmp(hard) > 0 {
+					causes = append(causes, fmt.Sprintf("ResourceQuota %s would be exceeded: %s needs %s more, %s of %s used.", quota.Name, check.name, add.String(), used.String(), hard.String()))
+				}
+			}
+		}
+	}
+
+	if len(causes) > 0 {
+		lines = append(lines, "Likely causes:")
+		for _, cause := range causes {
+			lines = append(lines, "  - "+cause)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+type storageQuotaKey struct {
+	name  corev1.ResourceName
+	count bool
+}
+
+// storageQuotaKeys returns the quota resources a claim of the given class is charged to.
+func storageQuotaKeys(className string) []storageQuotaKey {
+	keys := []storageQuotaKey{
+		{name: corev1.ResourceRequestsStorage},
+		{name: corev1.ResourcePersistentVolumeClaims, count: true},
+	}
+	if className != "" {
+		prefix := className + ".storageclass.storage.k8s.io/"
+		keys = append(keys,
+			storageQuotaKey{name: corev1.ResourceName(prefix + string(corev1.ResourceRequestsStorage))},
+			storageQuotaKey{name: corev1.ResourceName(prefix + string(corev1.ResourcePersistentVolumeClaims)), count: true},
+		)
+	}
+	return keys
+}
+
+func storageClassLines(class *storagev1.StorageClass) []string {
+	lines := []string{
+		fmt.Sprintf("Storage class: %s (provisioner %s)", class.Name, class.Provisioner),
+		fmt.Sprintf("  allowVolumeExpansion: %t", boolOrDefault(class.AllowVolumeExpansion)),
+	}
+	if class.VolumeBindingMode != nil {
+		lines = append(lines, fmt.Sprintf("  volumeBindingMode: %s", *class.VolumeBindingMode))
+	}
+	keys := make([]string, 0, len(class.Parameters))
+	for key := range class.Parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("  %s: %s", key, class.Parameters[key]))
+	}
+	return lines
+}
+
+func valueOrEmpty(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,118 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	// quotaMessage matches admission denials such as
+	// "exceeded quota: compute, requested: limits.cpu=2, used: limits.cpu=8, limited: limits.cpu=8".
+	quotaMessage      = regexp.MustCompile(`exceeded quota: ([a-z0-9][a-z0-9.-]*)(?:, requested: ([^,]+(?:,[^,:]+=[^,]+)*))?`)
+	limitRangeMessage = regexp.MustCompile(`(?i)((maximum|minimum) \S+ usage per (Container|Pod|PersistentVolumeClaim)|must specify (limits|requests)|limit to request ratio)`)
+)
+
+// diagnoseQuota renders the namespace's ResourceQuota usage against its limits and its
+// LimitRanges when a create was rejected by either, marking the quota and resources that blocked it.
+func diagnoseQuota(ctx context.Context, clientset *kubernetes.Clientset, namespace, message string) string {
+	quotaMatch := quotaMessage.FindStringSubmatch(message)
+	if quotaMatch == nil && !limitRangeMessage.MatchString(message) {
+		return ""
+	}
+	blockingQuota := ""
+	requested := map[string]bool{}
+	if quotaMatch != nil {
+		blockingQuota = quotaMatch[1]
+		for _, item := range strings.Split(quotaMatch[2], ",") {
+			if name, _, ok := strings.Cut(strings.TrimSpace(item), "="); ok {
+				requested[name] = true
+			}
+		}
+	}
+	return strings.Join(quotaLines(ctx, clientset, namespace, blockingQuota, requested), "\n")
+}
+
+// quotaLines renders the ResourceQuotas of a namespace with their usage and its
+// LimitRanges. The requested resources of blockingQuota are marked with "!".
+func quotaLines(ctx context.Context, clientset *kubernetes.Clientset, namespace, blockingQuota string, requested map[string]bool) []string {
+	lines := []string{"Resource quotas"}
+	quotas, err := clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	switch {
+	case err != nil:
+		lines = append(lines, failure("list resourcequotas", err))
+	case len(quotas.Items) == 0:
+		lines = append(lines, "  none in "+namespace)
+	default:
+		for _, quota := range quotas.Items {
+			title := "  " + quota.Name
+			if quota.Name == blockingQuota {
+				title += " (blocking)"
+			}
+			lines = append(lines, title, fmt.Sprintf("    %-32s %12s %12s %6s", "RESOURCE", "USED", "HARD", "USE%"))
+			for _, name := range sortedResourceNames(quota.Status.Hard) {
+				hard := quota.Status.Hard[name]
+				used := quota.Status.Used[name]
+				percent := percentOf(used, hard)
+				marker := " "
+				if quota.Name == blockingQuota && requested[string(name)] {
+					marker = "!"
+				}
+				lines = append(lines, fmt.Sprintf("  %s %-32s %12s %12s %6s", marker, name, used.String(), hard.String(), percent))
+			}
+		}
+	}
+
+	ranges, err := clientset.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return append(lines, failure("list limitranges", err))
+	}
+	if len(ranges.Items) == 0 {
+		return lines
+	}
+	lines = append(lines, "Limit ranges")
+	for _, lr := range ranges.Items {
+		lines = append(lines, "  "+lr.Name, fmt.Sprintf("    %-22s %-10s %10s %10s %10s %10s", "TYPE", "RESOURCE", "MIN", "MAX", "DEFAULT", "DEFAULTREQ"))
+		for _, item := range lr.Spec.Limits {
+			for _, name := range limitRangeResources(item) {
+				lines = append(lines, fmt.Sprintf("    %-22s %-10s %10s %10s %10s %10s",
+					item.Type, name,
+					quantityOrDash(item.Min, name), quantityOrDash(item.Max, name),
+					quantityOrDash(item.Default, name), quantityOrDash(item.DefaultRequest, name)))
+			}
+		}
+	}
+	return lines
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func limitRangeResources(item corev1.LimitRangeItem) []corev1.ResourceName {
+	all := corev1.ResourceList{}
+	for _, list := range []corev1.ResourceList{item.Min, item.Max, item.Default, item.DefaultRequest} {
+		for name, value := range list {
+			all[name] = value
+		}
+	}
+	return sortedResourceNames(all)
+}
+
+func quantityOrDash(list corev1.ResourceList, name corev1.ResourceName) string {
+	if value, ok := list[name]; ok {
+		return value.String()
+	}
+	return "-"
+}
@@ -0,0 +1,58 @@
+package drilldown
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// restrictedActions remembers drill-down calls the API server refused, so contexts such
+// as vclusters or aggregated endpoints with limited verbs produce one short notice
+// instead of the same raw error on every drill-down.
+var restrictedActions sync.Map
+
+// IsRestricted reports whether err means the API server does not allow or serve the
+// request at all, as opposed to a missing object or a transient failure.
+func IsRestricted(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apierrors.IsForbidden(err) || apierrors.IsMethodNotSupported(err) {
+		return true
+	}
+	// A 404 without an object name means the resource type itself is not served.
+	var statusErr apierrors.APIStatus
+	if apierrors.IsNotFound(err) && errors.As(err, &statusErr) {
+		details := statusErr.Status().Details
+		return details == nil || details.Name == ""
+	}
+	return false
+}
+
+// RestrictedActions lists the drill-down actions refused so far in this session.
+func RestrictedActions() []string {
+	var actions []string
+	restrictedActions.Range(func(key, _ any) bool {
+		actions = append(actions, key.(string))
+		return true
+	})
+	sort.Strings(actions)
+	return actions
+}
+
+func isRestrictedAction(action string) bool {
+	_, ok := restrictedActions.Load(action)
+	return ok
+}
+
+// failure describes a failed drill-down action, keeping restrictions short and quiet.
+func failure(action string, err error) string {
+	if IsRestricted(err) {
+		restrictedActions.Store(action, true)
+		return fmt.Sprintf("Not available in this context (%s is not permitted).", action)
+	}
+	return fmt.Sprintf("Failed to %s: %v", action, err)
+}
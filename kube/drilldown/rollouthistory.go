@@ -0,0 +1,77 @@
+package drilldown
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/internal/format"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+const (
+	revisionAnnotation    = "deployment.kubernetes.io/revision"
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+	// rolloutHistoryLimit matches the default revisionHistoryLimit of Deployments.
+	rolloutHistoryLimit = 10
+)
+
+// rolloutHistory lists the revisions of a Deployment from its ReplicaSets, newest first:
+// the revision, ReplicaSet, ready replicas, images and change-cause, e.g.
+// "rev 42 (current): api-7d9f, 3/3 ready, app=api:1.4, 2h ago, kubectl set image ...".
+// replicaSets may include ReplicaSets of other Deployments, and of an earlier Deployment
+// of the same name, which are left out.
+func rolloutHistory(dep *appsv1.Deployment, replicaSets []appsv1.ReplicaSet, now time.Time) []string {
+	type revision struct {
+		number int64
+		rs     appsv1.ReplicaSet
+	}
+	var revisions []revision
+	for _, rs := range replicaSets {
+		owned := false
+		for _, ref := range rs.OwnerReferences {
+			owned = owned || ref.Kind == "Deployment" && ref.UID == dep.UID
+		}
+		if !owned {
+			continue
+		}
+		number, err := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		if err != nil {
+			continue
+		}
+		revisions = append(revisions, revision{number: number, rs: rs})
+	}
+	if len(revisions) == 0 {
+		return nil
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].number > revisions[j].number })
+	current := dep.Annotations[revisionAnnotation]
+
+	lines := []string{"Rollout history:"}
+	for _, rev := range revisions[:min(len(revisions), rolloutHistoryLimit)] {
+		label := fmt.Sprintf("rev %d", rev.number)
+		if strconv.FormatInt(rev.number, 10) == current {
+			label += " (current)"
+		}
+		images := make([]string, 0, len(rev.rs.Spec.Template.Spec.Containers))
+		for _, container := range rev.rs.Spec.Template.Spec.Containers {
+			images = append(images, container.Name+"="+container.Image)
+		}
+		parts := []string{
+			rev.rs.Name,
+			fmt.Sprintf("%d/%d ready", rev.rs.Status.ReadyReplicas, valueOrDefault(rev.rs.Spec.Replicas)),
+			strings.Join(images, " "),
+			format.Ago(rev.rs.CreationTimestamp.Time, now),
+		}
+		if cause := rev.rs.Annotations[changeCauseAnnotation]; cause != "" {
+			parts = append(parts, cause)
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s", label, strings.Join(parts, ", ")))
+	}
+	if len(revisions) > rolloutHistoryLimit {
+		lines = append(lines, fmt.Sprintf("... +%d older", len(revisions)-rolloutHistoryLimit))
+	}
+	return lines
+}
@@ -0,0 +1,207 @@
+package drilldown
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/a0xAi/kubeve/internal/format"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// ObjectSnapshot returns the manifest of an event's involved object as gzip-compressed
+// JSON, without managedFields, for archiving next to the event. Kinds the drill-down
+// does not know return an error.
+func ObjectSnapshot(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) ([]byte, error) {
+	obj, err := getObject(ctx, clientset, namespace, kind, name)
+	if err != nil {
+		return nil, err
+	}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+	manifest, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(manifest); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SnapshotYAML decompresses a snapshot taken by ObjectSnapshot into YAML.
+func SnapshotYAML(snapshot []byte) (string, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(snapshot))
+	if err != nil {
+		return "", err
+	}
+	defer zr.Close()
+	manifest, err := io.ReadAll(zr)
+	if err != nil {
+		return "", err
+	}
+	out, err := yaml.JSONToYAML(manifest)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// ObjectGone reports whether the API server says the object no longer exists. Other
+// errors, such as missing permissions, do not count as gone.
+func ObjectGone(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) bool {
+	_, err := getObject(ctx, clientset, namespace, kind, name)
+	return apierrors.IsNotFound(err)
+}
+
+// errNoSnapshotAdapter is returned by getObject for kinds it cannot read.
+var errNoSnapshotAdapter = errors.New("no snapshot adapter")
+
+// ObjectYAML returns the live manifest of an object as YAML, without managedFields,
+// for the drill-down's raw view. Kinds without a snapshot adapter, custom resources
+// among them, are read through the dynamic client. Secret values are redacted.
+func ObjectYAML(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) (string, error) {
+	if namespace == "" && isNamespacedKind(strings.ToLower(strings.TrimSpace(kind))) {
+		namespace = metav1.NamespaceDefault
+	}
+	obj, err := getObject(ctx, clientset, namespace, kind, name)
+	if errors.Is(err, errNoSnapshotAdapter) {
+		var client *genericClient
+		if client, err = genericClientFor(clientset); err == nil {
+			var generic *unstructured.Unstructured
+			if generic, _, err = client.get(ctx, namespace, kind, name); err == nil {
+				redactSecret(generic)
+				obj = generic
+			}
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+	manifest, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	out, err := yaml.JSONToYAML(manifest)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// redactSecret replaces the values of a core Secret with their redacted size, so the
+// raw view never shows them.
+func redactSecret(obj *unstructured.Unstructured) {
+	if gvk := obj.GroupVersionKind(); gvk.Group != "" || gvk.Kind != "Secret" {
+		return
+	}
+	for _, field := range []string{"data", "stringData"} {
+		values, ok := obj.Object[field].(map[string]any)
+		if !ok {
+			continue
+		}
+		for key, value := range values {
+			size := len(fmt.Sprint(value))
+			if field == "data" {
+				size = base64.StdEncoding.DecodedLen(size)
+			}
+			values[key] = fmt.Sprintf("<redacted, about %s>", format.Bytes(int64(size)))
+		}
+	}
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		// kubectl apply keeps the whole manifest, values included, in this annotation.
+		delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+		obj.SetAnnotations(annotations)
+	}
+}
+
+// getObject reads an object of one of the kinds the drill-down describes. Typed objects
+// read through the clientset carry no TypeMeta, so it is filled in from the kind.
+func getObject(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) (runtime.Object, error) {
+	if clientset == nil {
+		return nil, fmt.Errorf("kubernetes client is not available")
+	}
+	opts := metav1.GetOptions{}
+	var (
+		obj        runtime.Object
+		err        error
+		apiVersion string
+		typedKind  string
+	)
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "pod":
+		obj, err = clientset.CoreV1().Pods(namespace).Get(ctx, name, opts)
+		apiVersion, typedKind = "v1", "Pod"
+	case "service":
+		obj, err = clientset.CoreV1().Services(namespace).Get(ctx, name, opts)
+		apiVersion, typedKind = "v1", "Service"
+	case "ingress", "ingresses", "ing":
+		obj, err = clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, opts)
+		apiVersion, typedKind = "networking.k8s.io/v1", "Ingress"
+	case "configmap", "cm":
+		obj, err = clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, opts)
+		apiVersion, typedKind = "v1", "ConfigMap"
+	case "persistentvolumeclaim", "pvc":
+		obj, err = clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, opts)
+		apiVersion, typedKind = "v1", "PersistentVolumeClaim"
+	case "persistentvolume", "pv":
+		obj, err = clientset.CoreV1().PersistentVolumes().Get(ctx, name, opts)
+		apiVersion, typedKind = "v1", "PersistentVolume"
+	case "namespace", "ns":
+		obj, err = clientset.CoreV1().Namespaces().Get(ctx, name, opts)
+		apiVersion, typedKind = "v1", "Namespace"
+	case "node":
+		obj, err = clientset.CoreV1().Nodes().Get(ctx, name, opts)
+		apiVersion, typedKind = "v1", "Node"
+	case "deployment":
+		obj, err = clientset.AppsV1().Deployments(namespace).Get(ctx, name, opts)
+		apiVersion, typedKind = "apps/v1", "Deployment"
+	case "replicaset":
+		obj, err = clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, opts)
+		apiVersion, typedKind = "apps/v1", "ReplicaSet"
+	case "statefulset":
+		obj, err = clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, opts)
+		apiVersion, typedKind = "apps/v1", "StatefulSet"
+	case "daemonset":
+		obj, err = clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, opts)
+		apiVersion, typedKind = "apps/v1", "DaemonSet"
+	case "job":
+		obj, err = clientset.BatchV1().Jobs(namespace).Get(ctx, name, opts)
+		apiVersion, typedKind = "batch/v1", "Job"
+	case "cronjob":
+		obj, err = clientset.BatchV1().CronJobs(namespace).Get(ctx, name, opts)
+		apiVersion, typedKind = "batch/v1", "CronJob"
+	case "horizontalpodautoscaler", "hpa":
+		obj, err = clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, name, opts)
+		apiVersion, typedKind = "autoscaling/v2", "HorizontalPodAutoscaler"
+	default:
+		return nil, fmt.Errorf("%w for kind %q", errNoSnapshotAdapter, kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(schema.FromAPIVersionAndKind(apiVersion, typedKind))
+	return obj, nil
+}
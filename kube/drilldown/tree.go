@@ -0,0 +1,169 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ObjectRef identifies an object by its canonical kind and name.
+type ObjectRef struct {
+	Kind string
+	Name string
+}
+
+func (r ObjectRef) String() string {
+	return r.Kind + "/" + r.Name
+}
+
+var kindAliases = map[string]string{
+	"pod": "Pod", "pods": "Pod", "po": "Pod",
+	"deployment": "Deployment", "deployments": "Deployment", "deploy": "Deployment",
+	"replicaset": "ReplicaSet", "replicasets": "ReplicaSet", "rs": "ReplicaSet",
+	"statefulset": "StatefulSet", "statefulsets": "StatefulSet", "sts": "StatefulSet",
+	"daemonset": "DaemonSet", "daemonsets": "DaemonSet", "ds": "DaemonSet",
+	"job": "Job", "jobs": "Job",
+	"cronjob": "CronJob", "cronjobs": "CronJob", "cj": "CronJob",
+	"service": "Service", "services": "Service", "svc": "Service",
+	"persistentvolumeclaim": "PersistentVolumeClaim", "persistentvolumeclaims": "PersistentVolumeClaim", "pvc": "PersistentVolumeClaim",
+	"horizontalpodautoscaler": "HorizontalPodAutoscaler", "horizontalpodautoscalers": "HorizontalPodAutoscaler", "hpa": "HorizontalPodAutoscaler",
+}
+
+// ParseObjectRef parses kubectl-style references such as deployment/foo or deploy/foo.
+func ParseObjectRef(raw string) (ObjectRef, error) {
+	parts := strings.SplitN(strings.TrimSpace(raw), "/", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return ObjectRef{}, fmt.Errorf("expected <kind>/<name>, got %q", raw)
+	}
+	kind := strings.TrimSpace(parts[0])
+	if canonical, ok := kindAliases[strings.ToLower(kind)]; ok {
+		kind = canonical
+	}
+	return ObjectRef{Kind: kind, Name: strings.TrimSpace(parts[1])}, nil
+}
+
+// ResolveDescendants returns root and every object it owns transitively in namespace
+// (ReplicaSets, Jobs and Pods), mirroring kubectl events --for but including the tree.
+// Services contribute the pods they select. Kinds without known descendants resolve to themselves.
+func ResolveDescendants(ctx context.Context, clientset *kubernetes.Clientset, namespace string, root ObjectRef) (map[ObjectRef]bool, error) {
+	tree := map[ObjectRef]bool{root: true}
+
+	if root.Kind == "Service" {
+		svc, err := clientset.CoreV1().Services(namespace).Get(ctx, root.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get %s: %w", root, err)
+		}
+		if len(svc.Spec.Selector) > 0 {
+			pods, err := listPodsBySelector(ctx, clientset, namespace, labels.SelectorFromSet(svc.Spec.Selector).String())
+			if err != nil {
+				return nil, fmt.Errorf("list pods for %s: %w", root, err)
+			}
+			for _, pod := range pods {
+				tree[ObjectRef{Kind: "Pod", Name: pod.Name}] = true
+			}
+		}
+		return tree, nil
+	}
+
+	rootUID, ok, err := ownerUID(ctx, clientset, namespace, root)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", root, err)
+	}
+	if !ok {
+		return tree, nil
+	}
+
+	// Index every potential descendant by owner UID, then walk down from the root.
+	type child struct {
+		ref ObjectRef
+		uid types.UID
+	}
+	children := make(map[types.UID][]child)
+	addChild := func(kind string, meta metav1.ObjectMeta) {
+		for _, owner := range meta.OwnerReferences {
+			children[owner.UID] = append(children[owner.UID], child{ref: ObjectRef{Kind: kind, Name: meta.Name}, uid: meta.UID})
+		}
+	}
+	if rsList, listErr := clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{}); listErr == nil {
+		for _, rs := range rsList.Items {
+			addChild("ReplicaSet", rs.ObjectMeta)
+		}
+	}
+	if jobList, listErr := clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{}); listErr == nil {
+		for _, job := range jobList.Items {
+			addChild("Job", job.ObjectMeta)
+		}
+	}
+	podList, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+	for _, pod := range podList.Items {
+		addChild("Pod", pod.ObjectMeta)
+	}
+
+	queue := []types.UID{rootUID}
+	seen := map[types.UID]bool{rootUID: true}
+	for len(queue) > 0 {
+		uid := queue[0]
+		queue = queue[1:]
+		for _, c := range children[uid] {
+			if seen[c.uid] {
+				continue
+			}
+			seen[c.uid] = true
+			tree[c.ref] = true
+			queue = append(queue, c.uid)
+		}
+	}
+	return tree, nil
+}
+
+// ownerUID looks up the UID of kinds that can own other objects; ok is false for other kinds.
+func ownerUID(ctx context.Context, clientset *kubernetes.Clientset, namespace string, ref ObjectRef) (types.UID, bool, error) {
+	switch ref.Kind {
+	case "Deployment":
+		obj, err := clientset.AppsV1().Deployments(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", true, err
+		}
+		return obj.UID, true, nil
+	case "ReplicaSet":
+		obj, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", true, err
+		}
+		return obj.UID, true, nil
+	case "StatefulSet":
+		obj, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", true, err
+		}
+		return obj.UID, true, nil
+	case "DaemonSet":
+		obj, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", true, err
+		}
+		return obj.UID, true, nil
+	case "Job":
+		obj, err := clientset.BatchV1().Jobs(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", true, err
+		}
+		return obj.UID, true, nil
+	case "CronJob":
+		obj, err := clientset.BatchV1().CronJobs(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", true, err
+		}
+		return obj.UID, true, nil
+	default:
+		return "", false, nil
+	}
+}
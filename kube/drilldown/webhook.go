@@ -0,0 +1,143 @@
+package drilldown
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// webhookMessage matches both denials ("admission webhook "x" denied the request")
+// and outages ("failed calling webhook "x": ...").
+var webhookMessage = regexp.MustCompile(`(?:admission webhook|failed calling webhook) "([^"]+)"`)
+
+// webhookName returns the admission webhook named in an event message.
+func webhookName(message string) (string, bool) {
+	match := webhookMessage.FindStringSubmatch(message)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// diagnoseWebhook locates the admission webhook named in message and reports how it
+// fails (failurePolicy, timeout), where it is served and whether its backend pods are healthy.
+func diagnoseWebhook(ctx context.Context, clientset *kubernetes.Clientset, message string) string {
+	name, ok := webhookName(message)
+	if !ok {
+		return ""
+	}
+	lines := []string{fmt.Sprintf("Admission webhook %s", name)}
+
+	type located struct {
+		kind, config  string
+		failurePolicy *admissionregistrationv1.FailurePolicyType
+		timeout       *int32
+		client        admissionregistrationv1.WebhookClientConfig
+	}
+	var found []located
+	validating, err := clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		lines = append(lines, failure("list validatingwebhookconfigurations", err))
+	} else {
+		for _, cfg := range validating.Items {
+			for _, hook := range cfg.Webhooks {
+				if hook.Name == name {
+					found = append(found, located{"ValidatingWebhookConfiguration", cfg.Name, hook.FailurePolicy, hook.TimeoutSeconds, hook.ClientConfig})
+				}
+			}
+		}
+	}
+	mutating, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		lines = append(lines, failure("list mutatingwebhookconfigurations", err))
+	} else {
+		for _, cfg := range mutating.Items {
+			for _, hook := range cfg.Webhooks {
+				if hook.Name == name {
+					found = append(found, located{"MutatingWebhookConfiguration", cfg.Name, hook.FailurePolicy, hook.TimeoutSeconds, hook.ClientConfig})
+				}
+			}
+		}
+	}
+	if len(found) == 0 && len(lines) == 1 {
+		lines = append(lines, "No webhook configuration registers this webhook anymore.")
+	}
+
+	for _, hook := range found {
+		policy := string(admissionregistrationv1.Fail)
+		if hook.failurePolicy != nil {
+			policy = string(*hook.failurePolicy)
+		}
+		timeout := int32(10)
+		if hook.timeout != nil {
+			timeout = *hook.timeout
+		}
+		lines = append(lines,
+			fmt.Sprintf("%s/%s", hook.kind, hook.config),
+			fmt.Sprintf("  failurePolicy: %s", policy),
+			fmt.Sprintf("  timeoutSeconds: %d", timeout),
+		)
+		if policy == string(admissionregistrationv1.Fail) {
+			lines = append(lines, "  Requests are rejected whenever the webhook is unreachable.")
+		}
+		svc := hook.client.Service
+		if svc == nil {
+			if hook.client.URL != nil {
+				lines = append(lines, "  Backend: "+*hook.client.URL)
+			}
+			continue
+		}
+		port := int32(443)
+		if svc.Port != nil {
+			port = *svc.Port
+		}
+		backend := fmt.Sprintf("  Backend: service %s/%s:%d", svc.Namespace, svc.Name, port)
+		if svc.Path != nil {
+			backend += *svc.Path
+		}
+		lines = append(lines, backend)
+		lines = append(lines, webhookBackendHealth(ctx, clientset, svc.Namespace, svc.Name)...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func webhookBackendHealth(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) []string {
+	svc, err := clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return []string{"  " + failure("load webhook service", err)}
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return []string{"  Service has no selector; check its endpoints manually."}
+	}
+	pods, err := listPodsBySelector(ctx, clientset, namespace, labels.SelectorFromSet(svc.Spec.Selector).String())
+	if err != nil {
+		return []string{"  " + failure("list webhook pods", err)}
+	}
+	if len(pods) == 0 {
+		return []string{"  No pods back the webhook service: every call fails."}
+	}
+	lines := []string{"  Backend pods:"}
+	for _, pod := range pods {
+		lines = append(lines, "  "+podHealth(&pod))
+	}
+	return lines
+}
+
+// podHealth summarizes a pod's readiness and restarts on one line.
+func podHealth(pod *corev1.Pod) string {
+	ready, restarts := 0, int32(0)
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			ready++
+		}
+		restarts += cs.RestartCount
+	}
+	return fmt.Sprintf("- %s (%s, ready %d/%d, restarts %d)", pod.Name, pod.Status.Phase, ready, len(pod.Spec.Containers), restarts)
+}
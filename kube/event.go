@@ -0,0 +1,131 @@
+package kube
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+)
+
+// Event is the normalized form of a Kubernetes event handed to consumers of the watch.
+type Event struct {
+	UID         string    `json:"uid"`
+	Fingerprint string    `json:"fingerprint"`
+	Time        time.Time `json:"time"`
+	Namespace   string    `json:"namespace"`
+	Kind        string    `json:"kind"`
+	Name        string    `json:"name"`
+	ObjectUID   string    `json:"objectUID,omitempty"`
+	Type        string    `json:"type"`
+	Reason      string    `json:"reason"`
+	Message     string    `json:"message"`
+	// Count is how often the event occurred, from its series when it has one.
+	Count int32 `json:"count,omitempty"`
+	// Source is the controller that reported the event, e.g. kubelet.
+	Source string `json:"source,omitempty"`
+	// Cluster is the kubeconfig context the event was watched through when several are.
+	Cluster string `json:"cluster,omitempty"`
+	// Extra holds the fields of the API object this build of kubeve does not know, e.g.
+	// from a newer Kubernetes release, nested as in the object, so archives and exports
+	// keep them. Only events read with NewEventUnstructured carry it.
+	Extra map[string]any `json:"extra,omitempty"`
+}
+
+// NewEvent normalizes a core/v1 event.
+func NewEvent(event *corev1.Event) Event {
+	ev := Event{
+		UID:       string(event.UID),
+		Time:      EventTime(*event),
+		Namespace: event.Namespace,
+		Kind:      event.InvolvedObject.Kind,
+		Name:      event.InvolvedObject.Name,
+		ObjectUID: string(event.InvolvedObject.UID),
+		Type:      event.Type,
+		Reason:    event.Reason,
+		Message:   event.Message,
+		Count:     event.Count,
+		Source:    event.ReportingController,
+	}
+	if event.Series != nil {
+		ev.Count = event.Series.Count
+	}
+	if ev.Source == "" {
+		ev.Source = event.Source.Component
+	}
+	ev.Fingerprint = Fingerprint(ev)
+	return ev
+}
+
+// NewEventV1 normalizes an events.k8s.io/v1 event.
+func NewEventV1(event *eventsv1.Event) Event {
+	ev := Event{
+		UID:       string(event.UID),
+		Time:      eventV1Timestamp(event),
+		Namespace: event.Namespace,
+		Kind:      event.Regarding.Kind,
+		Name:      event.Regarding.Name,
+		ObjectUID: string(event.Regarding.UID),
+		Type:      event.Type,
+		Reason:    event.Reason,
+		Message:   event.Note,
+		Count:     event.DeprecatedCount,
+		Source:    event.ReportingController,
+	}
+	if event.Series != nil {
+		ev.Count = event.Series.Count
+	}
+	if ev.Source == "" {
+		ev.Source = event.DeprecatedSource.Component
+	}
+	ev.Fingerprint = Fingerprint(ev)
+	return ev
+}
+
+// EventTime returns when a core/v1 event last occurred, falling back through the
+// timestamps older clusters and clients fill in.
+func EventTime(event corev1.Event) time.Time {
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		return event.Series.LastObservedTime.Time
+	}
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	if !event.FirstTimestamp.IsZero() {
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+func eventV1Timestamp(event *eventsv1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.DeprecatedLastTimestamp.IsZero():
+		return event.DeprecatedLastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.DeprecatedFirstTimestamp.IsZero():
+		return event.DeprecatedFirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// Fingerprint returns a stable identifier built from the involved object's UID, the reason
+// and a hash of the message. It does not depend on the event's own name or resourceVersion,
+// so the same occurrence seen across reconnects or by several kubeve instances matches.
+func Fingerprint(event Event) string {
+	object := event.ObjectUID
+	if object == "" {
+		object = event.Namespace + "/" + event.Kind + "/" + event.Name
+	}
+	message := sha256.Sum256([]byte(strings.TrimSpace(event.Message)))
+	sum := sha256.Sum256([]byte(object + "|" + event.Reason + "|" + hex.EncodeToString(message[:])))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,88 @@
+package kube_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/a0xAi/kubeve/archive"
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/a0xAi/kubeve/kube/client"
+	"github.com/a0xAi/kubeve/kube/drilldown"
+	"github.com/a0xAi/kubeve/kube/watch"
+)
+
+// replaySource is an EventSource that replays a fixed list of events, e.g. read from a
+// file, and then ends the watch.
+type replaySource []kube.Event
+
+func (r replaySource) Watch(ctx context.Context, scope string, handlers kube.EventHandlers, onStatus func(kube.WatchStatus)) error {
+	for _, event := range r {
+		if kube.InNamespaces(scope, event.Namespace) {
+			handlers.OnAdd(event)
+		}
+	}
+	return nil
+}
+
+// A Hub fans any EventSource out to subscribers; watch.Source{} is the one of the
+// current kubeconfig context.
+func ExampleEventSource() {
+	source := replaySource{
+		{Namespace: "shop", Kind: "Pod", Name: "api-7d9c", Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container"},
+		{Namespace: "billing", Kind: "Pod", Name: "worker-0", Type: "Normal", Reason: "Pulled", Message: "Image pulled"},
+		{Namespace: "shop", Kind: "Deployment", Name: "api", Type: "Normal", Reason: "ScalingReplicaSet", Message: "Scaled up to 3"},
+	}
+	hub := watch.NewHub(source)
+
+	done := make(chan struct{})
+	hub.Subscribe("shop", nil, func(event kube.Event) {
+		fmt.Printf("%s %s/%s: %s\n", event.Reason, event.Kind, event.Name, event.Message)
+	}, func(err error) {
+		close(done)
+	})
+	<-done
+	// Output:
+	// BackOff Pod/api-7d9c: Back-off restarting failed container
+	// ScalingReplicaSet Deployment/api: Scaled up to 3
+}
+
+// Inspector explains the objects of warnings as they arrive.
+func ExampleObjectInspector() {
+	clientset, err := client.ContextClient("")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var inspector kube.ObjectInspector = drilldown.Inspector{Clientset: clientset}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_ = watch.Source{}.Watch(ctx, "", kube.EventHandlers{
+		OnAdd: func(event kube.Event) {
+			if event.Type != "Warning" {
+				return
+			}
+			details := inspector.DrillDown(ctx, event.Namespace, event.Kind, event.Name)
+			fmt.Println(details.Diagnosis)
+		},
+	}, nil)
+}
+
+// The archive of a cluster is a Store that keeps events past the cluster's event TTL.
+func ExampleStore() {
+	a, err := archive.Open("my-cluster")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer a.Close()
+	var store kube.Store = a
+	_ = store.Append(kube.Event{Time: time.Now(), Namespace: "shop", Kind: "Pod", Name: "api-7d9c", Reason: "BackOff"})
+	events, err := store.Since(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(len(events), "events in the last day")
+}
@@ -0,0 +1,70 @@
+// Package kube holds what kubeve's event pipeline passes around: the normalized Event,
+// the handlers and status of a watch, namespace scopes, and the interfaces between the
+// stages, so programs can embed the pipeline or replace one of its stages.
+//
+// The stages live in sub-packages: client sets up clients from the kubeconfig, watch
+// streams events (its Source is the EventSource of a cluster and its Hub shares one
+// source between subscribers), and drilldown inspects the objects events are about (its
+// Inspector is an ObjectInspector). The archive package is a Store.
+package kube
+
+import (
+	"context"
+	"time"
+)
+
+// EventSource streams the events of a namespace scope: "" for all namespaces, or a
+// comma separated list such as "team-a,team-b".
+type EventSource interface {
+	// Watch delivers changes to handlers, from one goroutine, and connection changes to
+	// onStatus (may be nil) until ctx is done or the watch cannot go on, in which case
+	// it returns why.
+	Watch(ctx context.Context, scope string, handlers EventHandlers, onStatus func(WatchStatus)) error
+}
+
+// ObjectInspector explains the object an event is about, named by the event's
+// namespace, kind and name.
+type ObjectInspector interface {
+	// DrillDown describes the object, lists related objects and, for kinds that have
+	// them, recent logs. Failures are reported in the text.
+	DrillDown(ctx context.Context, namespace, kind, name string) ResourceDrillDown
+	// YAML returns the object's manifest, with Secret values redacted.
+	YAML(ctx context.Context, namespace, kind, name string) (string, error)
+	// Logs returns the recent logs of a pod, or of the pods of a workload, or with
+	// previous set those of the last run of a restarted container.
+	Logs(ctx context.Context, namespace, kind, name string, previous bool) string
+}
+
+// Store keeps events beyond the cluster's event TTL.
+type Store interface {
+	// Append stores event. Storing an occurrence that is already stored does nothing.
+	Append(event Event) error
+	// Since returns the stored events that happened at or after since, in the order
+	// they were stored.
+	Since(since time.Time) ([]Event, error)
+}
+
+// ResourceDrillDown is what an ObjectInspector found out about an object, as text.
+type ResourceDrillDown struct {
+	Describe string
+	Related  string
+	// RelatedPages splits a Related list too long for one page, such as the pods on a
+	// busy node, into pages; Related is the first of them.
+	RelatedPages []string
+	// RelatedObjects are the objects in the namespace of the inspected one that a
+	// drill-down can move on to: its owners, what it owns, or the pods a Service selects.
+	RelatedObjects []RelatedObject
+	Logs           string
+	// Termination explains why containers of the inspected pod last exited, if they did.
+	Termination string
+	// Diagnosis answers "why did this fail" for kinds with a dedicated analysis.
+	Diagnosis string
+}
+
+// RelatedObject is an object related to an inspected one, in the same namespace.
+type RelatedObject struct {
+	Kind string
+	Name string
+	// Relation is how it relates to the inspected object: "owner", "owned" or "selected".
+	Relation string
+}
@@ -0,0 +1,42 @@
+package kube
+
+import (
+	"sort"
+	"strings"
+)
+
+// SplitNamespaces parses a namespace scope such as "team-a,team-b" into its sorted,
+// de-duplicated namespaces. It returns nil for all namespaces ("").
+func SplitNamespaces(scope string) []string {
+	seen := make(map[string]bool)
+	var namespaces []string
+	for _, ns := range strings.Split(scope, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// JoinNamespaces is the inverse of SplitNamespaces.
+func JoinNamespaces(namespaces []string) string {
+	return strings.Join(SplitNamespaces(strings.Join(namespaces, ",")), ",")
+}
+
+// InNamespaces reports whether namespace belongs to scope, a namespace list as accepted
+// by SplitNamespaces.
+func InNamespaces(scope, namespace string) bool {
+	if scope == "" || scope == namespace {
+		return true
+	}
+	for _, ns := range SplitNamespaces(scope) {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package kube
+
+import (
+	"errors"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// EventHandlers receive the changes of watched events. Nil funcs are skipped.
+type EventHandlers struct {
+	OnAdd    func(event Event)
+	OnUpdate func(old, event Event)
+	OnDelete func(event Event)
+}
+
+// WatchStatus describes the connection of an event watch.
+type WatchStatus struct {
+	Connected bool
+	// Attempt counts reconnect attempts since the connection was lost.
+	Attempt int
+	// Err is why the last attempt failed, a *watch.Error.
+	Err error
+	// Listing is set while the events that already exist are being listed page by
+	// page, with the number received so far in Listed.
+	Listing bool
+	Listed  int
+	// Stopped is set when the watch gave up, on rejected credentials or missing
+	// permissions, and only a restart resumes it.
+	Stopped bool
+	// Contexts holds the status of each kubeconfig context when several are watched.
+	Contexts map[string]WatchStatus
+}
+
+// WatchState summarizes a WatchStatus: whether the stream is live, and if not, why.
+type WatchState int
+
+const (
+	StateConnected WatchState = iota
+	StateListing
+	StateReconnecting
+	StateUnauthorized
+	StateForbidden
+)
+
+func (s WatchState) String() string {
+	switch s {
+	case StateConnected:
+		return "Connected"
+	case StateListing:
+		return "Listing"
+	case StateReconnecting:
+		return "Reconnecting"
+	case StateUnauthorized:
+		return "Unauthorized"
+	default:
+		return "Forbidden"
+	}
+}
+
+// State returns the state of the watch s describes.
+func (s WatchStatus) State() WatchState {
+	switch {
+	case s.Connected && s.Listing:
+		return StateListing
+	case s.Connected:
+		return StateConnected
+	case apierrors.IsForbidden(s.Err):
+		return StateForbidden
+	case IsAuthError(s.Err):
+		return StateUnauthorized
+	default:
+		return StateReconnecting
+	}
+}
+
+// IsAuthError reports whether err was caused by rejected or unobtainable credentials,
+// including failures of exec credential plugins such as aws or gke-gcloud-auth-plugin.
+func IsAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apierrors.IsUnauthorized(err) {
+		return true
+	}
+	var statusErr apierrors.APIStatus
+	if errors.As(err, &statusErr) {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "getting credentials") ||
+		strings.Contains(msg, "exec plugin") ||
+		strings.Contains(msg, "Unauthorized")
+}
@@ -0,0 +1,63 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/a0xAi/kubeve/kube"
+)
+
+// SplitContexts splits a comma separated list of kubeconfig contexts, dropping blanks
+// and duplicates.
+func SplitContexts(list string) []string {
+	var contexts []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		contexts = append(contexts, name)
+	}
+	return contexts
+}
+
+// Contexts is Namespaces across several kubeconfig contexts at once, merging
+// their events into one stream. Each event has Cluster set to the context it came from.
+// onStatus sees the combined status, with the status of every context in Contexts. The
+// first context whose watch fails stops the others and its error is returned.
+func Contexts(ctx context.Context, contexts []string, scope string, handlers kube.EventHandlers, onStatus func(kube.WatchStatus)) error {
+	return watchContexts(ctx, eventOptions{}, contexts, scope, handlers, onStatus)
+}
+
+// watchContexts is Contexts with opts.
+func watchContexts(ctx context.Context, opts eventOptions, contexts []string, scope string, handlers kube.EventHandlers, onStatus func(kube.WatchStatus)) error {
+	return fanIn(ctx, contexts, func(ctx context.Context, kubeContext string, handlers kube.EventHandlers, onStatus func(kube.WatchStatus)) error {
+		stamp := func(event kube.Event) kube.Event {
+			event.Cluster = kubeContext
+			return event
+		}
+		err := watchNamespaces(ctx, opts, kubeContext, scope, kube.EventHandlers{
+			OnAdd: func(event kube.Event) {
+				handlers.OnAdd(stamp(event))
+			},
+			OnUpdate: func(old, event kube.Event) {
+				handlers.OnUpdate(stamp(old), stamp(event))
+			},
+			OnDelete: func(event kube.Event) {
+				handlers.OnDelete(stamp(event))
+			},
+		}, onStatus)
+		if err != nil {
+			return fmt.Errorf("context %s: %w", kubeContext, err)
+		}
+		return nil
+	}, handlers, func(combined kube.WatchStatus, byContext map[string]kube.WatchStatus) {
+		if onStatus != nil {
+			combined.Contexts = byContext
+			onStatus(combined)
+		}
+	})
+}
@@ -0,0 +1,302 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/a0xAi/kubeve/kube/client"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Digest summarizes what happened in a time window, for daily ops reports.
+type Digest struct {
+	Since time.Time
+	Until time.Time
+	// Archived reports that the local archive was read along with the API server's events.
+	Archived   bool
+	Namespaces []NamespaceWarnings
+	CrashLoops []CrashLoop
+	NodeFlaps  []NodeFlap
+}
+
+// NamespaceWarnings counts Warning occurrences in one namespace.
+type NamespaceWarnings struct {
+	Namespace  string
+	Warnings   int
+	TopReasons []ReasonCount
+}
+
+// ReasonCount is the number of occurrences of one event reason.
+type ReasonCount struct {
+	Reason string
+	Count  int
+}
+
+// CrashLoop is a workload whose pods started crash looping inside the window.
+type CrashLoop struct {
+	Namespace string
+	Workload  string
+	Pods      int
+	BackOffs  int
+	FirstSeen time.Time
+}
+
+// NodeFlap is a node that reported unhealthy conditions inside the window.
+type NodeFlap struct {
+	Node    string
+	Reasons []ReasonCount
+}
+
+const digestTopReasons = 3
+
+// nodeProblemReasons are kubelet/node-controller reasons for a node turning unhealthy.
+var nodeProblemReasons = map[string]bool{
+	"NodeNotReady":              true,
+	"NodeHasDiskPressure":       true,
+	"NodeHasInsufficientMemory": true,
+	"NodeHasInsufficientPID":    true,
+	"NodeNotSchedulable":        true,
+	"Rebooted":                  true,
+	"SystemOOM":                 true,
+}
+
+// nodeRecoveryReasons are the matching "back to normal" reasons, counted to show flapping.
+var nodeRecoveryReasons = map[string]bool{
+	"NodeReady":               true,
+	"NodeHasNoDiskPressure":   true,
+	"NodeHasSufficientMemory": true,
+	"NodeHasSufficientPID":    true,
+	"NodeSchedulable":         true,
+}
+
+// BuildDigest lists the events currently retained by the API server in namespace (all
+// namespaces when empty) and summarizes those seen during the last since. When store is
+// not nil, e.g. the local archive, the events it kept since then are summarized too, so
+// the digest reaches past the cluster's event TTL.
+func BuildDigest(ctx context.Context, namespace string, since time.Duration, store kube.Store) (Digest, error) {
+	_, _, clientset, _, err := client.Kinit(namespace)
+	if err != nil {
+		return Digest{}, fmt.Errorf("initialize kubernetes client: %w", err)
+	}
+	now := time.Now()
+	var archived []kube.Event
+	if store != nil {
+		stored, err := store.Since(now.Add(-since))
+		if err != nil {
+			return Digest{}, fmt.Errorf("read archived events: %w", err)
+		}
+		for _, event := range stored {
+			if namespace == "" || event.Namespace == namespace {
+				archived = append(archived, event)
+			}
+		}
+	}
+	list, err := eventsAPI{clientset: clientset, namespace: namespace}.listPaged(ctx, metav1.ListOptions{}, func(int) {})
+	if err != nil {
+		return Digest{}, fmt.Errorf("list events: %w", err)
+	}
+	// Pods are only used to map crash looping pods to their workload; without them the
+	// pod name is reported instead.
+	var pods []corev1.Pod
+	if podList, listErr := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{}); listErr == nil {
+		pods = podList.Items
+	}
+	digest := summarizeDigest(digestEvents(archived, list.(*corev1.EventList).Items), pods, now.Add(-since), now)
+	digest.Archived = store != nil
+	return digest, nil
+}
+
+// digestEvent is an event with how often it occurred and when it was first seen.
+type digestEvent struct {
+	kube.Event
+	firstSeen time.Time
+	count     int
+}
+
+// digestEvents merges archived occurrences with the events the API server still retains,
+// by event UID. An archived event counts its occurrences from the first one archived in
+// the window; its live copy adds those that happened after the last one archived.
+func digestEvents(archived []kube.Event, live []corev1.Event) []digestEvent {
+	var events []digestEvent
+	index := make(map[string]int)
+	for _, event := range archived {
+		key := event.UID
+		if key == "" {
+			key = event.Fingerprint + "@" + event.Time.Format(time.RFC3339Nano)
+		}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(events)
+			events = append(events, digestEvent{Event: event, firstSeen: event.Time, count: 1})
+			continue
+		}
+		events[i].count += max(int(event.Count-events[i].Count), 1)
+		if event.Time.After(events[i].Time) {
+			events[i].Event = event
+		}
+	}
+	for i := range live {
+		event := kube.NewEvent(&live[i])
+		if j, ok := index[event.UID]; ok && event.UID != "" {
+			events[j].count += max(int(event.Count-events[j].Count), 0)
+			if first := live[i].FirstTimestamp.Time; !first.IsZero() && first.Before(events[j].firstSeen) {
+				events[j].firstSeen = first
+			}
+			if event.Time.After(events[j].Time) {
+				events[j].Event = event
+			}
+			continue
+		}
+		first := live[i].FirstTimestamp.Time
+		if first.IsZero() {
+			first = event.Time
+		}
+		events = append(events, digestEvent{Event: event, firstSeen: first, count: max(int(event.Count), 1)})
+	}
+	return events
+}
+
+func summarizeDigest(events []digestEvent, pods []corev1.Pod, since, until time.Time) Digest {
+	digest := Digest{Since: since, Until: until}
+	workloads := podWorkloads(pods)
+
+	warnings := make(map[string]map[string]int)
+	type loopKey struct{ namespace, workload string }
+	loops := make(map[loopKey]*CrashLoop)
+	loopPods := make(map[loopKey]map[string]bool)
+	nodes := make(map[string]map[string]int)
+
+	for _, event := range events {
+		if event.Time.Before(since) || event.Time.After(until) {
+			continue
+		}
+		count := event.count
+
+		if event.Type == corev1.EventTypeWarning {
+			if warnings[event.Namespace] == nil {
+				warnings[event.Namespace] = make(map[string]int)
+			}
+			warnings[event.Namespace][event.Reason] += count
+		}
+
+		if event.Kind == "Pod" && event.Reason == "BackOff" && strings.Contains(event.Message, "restarting failed container") {
+			first := event.firstSeen
+			workload := workloads[event.Namespace+"/"+event.Name]
+			if workload == "" {
+				workload = "Pod/" + event.Name
+			}
+			key := loopKey{event.Namespace, workload}
+			loop := loops[key]
+			if loop == nil {
+				loop = &CrashLoop{Namespace: event.Namespace, Workload: workload, FirstSeen: first}
+				loops[key] = loop
+				loopPods[key] = make(map[string]bool)
+			}
+			loopPods[key][event.Name] = true
+			loop.BackOffs += count
+			if first.Before(loop.FirstSeen) {
+				loop.FirstSeen = first
+			}
+		}
+
+		if event.Kind == "Node" && (nodeProblemReasons[event.Reason] || nodeRecoveryReasons[event.Reason]) {
+			node := event.Name
+			if nodes[node] == nil {
+				nodes[node] = make(map[string]int)
+			}
+			nodes[node][event.Reason] += count
+		}
+	}
+
+	for namespace, reasons := range warnings {
+		entry := NamespaceWarnings{Namespace: namespace}
+		for _, rc := range sortedReasons(reasons) {
+			entry.Warnings += rc.Count
+			if len(entry.TopReasons) < digestTopReasons {
+				entry.TopReasons = append(entry.TopReasons, rc)
+			}
+		}
+		digest.Namespaces = append(digest.Namespaces, entry)
+	}
+	sort.Slice(digest.Namespaces, func(i, j int) bool {
+		if digest.Namespaces[i].Warnings != digest.Namespaces[j].Warnings {
+			return digest.Namespaces[i].Warnings > digest.Namespaces[j].Warnings
+		}
+		return digest.Namespaces[i].Namespace < digest.Namespaces[j].Namespace
+	})
+
+	// Only loops that began inside the window are new; older ones were in yesterday's digest.
+	for key, loop := range loops {
+		if loop.FirstSeen.Before(since) {
+			continue
+		}
+		loop.Pods = len(loopPods[key])
+		digest.CrashLoops = append(digest.CrashLoops, *loop)
+	}
+	sort.Slice(digest.CrashLoops, func(i, j int) bool {
+		return digest.CrashLoops[i].FirstSeen.Before(digest.CrashLoops[j].FirstSeen)
+	})
+
+	for node, reasons := range nodes {
+		problem := false
+		for reason := range reasons {
+			if nodeProblemReasons[reason] {
+				problem = true
+				break
+			}
+		}
+		if problem {
+			digest.NodeFlaps = append(digest.NodeFlaps, NodeFlap{Node: node, Reasons: sortedReasons(reasons)})
+		}
+	}
+	sort.Slice(digest.NodeFlaps, func(i, j int) bool {
+		return digest.NodeFlaps[i].Node < digest.NodeFlaps[j].Node
+	})
+
+	return digest
+}
+
+// podWorkloads maps namespace/pod to the owning workload, e.g. Deployment/api.
+func podWorkloads(pods []corev1.Pod) map[string]string {
+	workloads := make(map[string]string, len(pods))
+	for i := range pods {
+		if workload := podWorkload(&pods[i]); workload != "" {
+			workloads[pods[i].Namespace+"/"+pods[i].Name] = workload
+		}
+	}
+	return workloads
+}
+
+// podWorkload returns the workload controlling a pod, e.g. Deployment/api, or "" for a
+// pod without a controller.
+func podWorkload(pod *corev1.Pod) string {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller == nil || !*owner.Controller {
+			continue
+		}
+		if hash := pod.Labels["pod-template-hash"]; owner.Kind == "ReplicaSet" && hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+			return "Deployment/" + strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+		return owner.Kind + "/" + owner.Name
+	}
+	return ""
+}
+
+func sortedReasons(reasons map[string]int) []ReasonCount {
+	sorted := make([]ReasonCount, 0, len(reasons))
+	for reason, count := range reasons {
+		sorted = append(sorted, ReasonCount{Reason: reason, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Reason < sorted[j].Reason
+	})
+	return sorted
+}
@@ -0,0 +1,187 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/a0xAi/kubeve/kube/client"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// knownEventsVersions are the events.k8s.io versions whose fields k8s.io/api knows.
+var knownEventsVersions = map[string]bool{"v1": true, "v1beta1": true}
+
+// NewEventUnstructured normalizes an event read without a typed decoder. Fields the
+// typed events of this build do not know are kept in Extra. Versions of events.k8s.io
+// other than v1 are read with the v1 field layout, which later versions are expected to
+// extend rather than break.
+func NewEventUnstructured(obj *unstructured.Unstructured) (kube.Event, error) {
+	var (
+		ev    kube.Event
+		typed any
+	)
+	switch gvk := obj.GroupVersionKind(); gvk.Group {
+	case eventsv1.GroupName:
+		event := &eventsv1.Event{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, event); err != nil {
+			return kube.Event{}, err
+		}
+		ev, typed = kube.NewEventV1(event), event
+	case corev1.GroupName:
+		event := &corev1.Event{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, event); err != nil {
+			return kube.Event{}, err
+		}
+		ev, typed = kube.NewEvent(event), event
+	default:
+		return kube.Event{}, fmt.Errorf("unsupported event type %s", gvk)
+	}
+	if known, err := runtime.DefaultUnstructuredConverter.ToUnstructured(typed); err == nil {
+		ev.Extra = unknownFields(obj.Object, known)
+	}
+	return ev, nil
+}
+
+// unknownFields returns the fields of raw that known lacks, descending into objects
+// but not lists. Empty values are skipped: known drops them too when they are optional.
+func unknownFields(raw, known map[string]any) map[string]any {
+	var extra map[string]any
+	for key, value := range raw {
+		if isEmptyValue(value) {
+			continue
+		}
+		var found any
+		if known != nil {
+			found = known[key]
+		}
+		if found == nil {
+			if extra == nil {
+				extra = make(map[string]any)
+			}
+			extra[key] = value
+			continue
+		}
+		rawObject, ok := value.(map[string]any)
+		knownObject, ok2 := found.(map[string]any)
+		if !ok || !ok2 {
+			continue
+		}
+		if nested := unknownFields(rawObject, knownObject); nested != nil {
+			if extra == nil {
+				extra = make(map[string]any)
+			}
+			extra[key] = nested
+		}
+	}
+	return extra
+}
+
+func isEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case int64:
+		return v == 0
+	case float64:
+		return v == 0
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// rawEventsResource returns a client that reads events as JSON into unstructured
+// objects when the cluster may send fields this build does not know: newer reports that
+// it runs a newer minor version than the k8s.io/api kubeve was built with (see
+// serverNewerThanBuild), or, for events.k8s.io, it prefers an unknown version. Otherwise
+// it returns nil, and the typed client, which can use protobuf, loses nothing.
+func rawEventsResource(clientset *kubernetes.Clientset, eventsAPIGroup, newer bool) (dynamic.NamespaceableResourceInterface, error) {
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "events"}
+	if eventsAPIGroup {
+		gvr.Group = eventsv1.GroupName
+		if preferred := preferredVersion(clientset, eventsv1.GroupName); preferred != "" && !knownEventsVersions[preferred] {
+			gvr.Version, newer = preferred, true
+		}
+	}
+	if !newer {
+		return nil, nil
+	}
+	dyn, err := client.Dynamic(clientset)
+	if err != nil {
+		return nil, err
+	}
+	return dyn.Resource(gvr), nil
+}
+
+// preferredVersion returns the version of an API group the server prefers, or "" when
+// discovery fails or the group is not served.
+func preferredVersion(clientset *kubernetes.Clientset, group string) string {
+	groups, err := clientset.Discovery().ServerGroups()
+	if err != nil {
+		return ""
+	}
+	for _, g := range groups.Groups {
+		if g.Name == group {
+			return g.PreferredVersion.Version
+		}
+	}
+	return ""
+}
+
+// serverNewerThanBuild reports whether the API server's minor version is newer than the
+// one of the k8s.io/api module kubeve was built with. A server that does not answer
+// within the startup timeout counts as not newer.
+func serverNewerThanBuild(ctx context.Context, clientset *kubernetes.Clientset) bool {
+	built := builtAPIMinor()
+	if built == 0 {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(ctx, client.StartupTimeout())
+	defer cancel()
+	info, err := client.ServerVersion(ctx, clientset)
+	if err != nil {
+		return false
+	}
+	// Some distributions report minors such as "33+".
+	minor, err := strconv.Atoi(strings.TrimRight(info.Minor, "+"))
+	return err == nil && minor > built
+}
+
+// builtAPIMinor returns the Kubernetes minor version of the k8s.io/api module in the
+// build, e.g. 33 for v0.33.0, or 0 when the build info does not name it.
+var builtAPIMinor = sync.OnceValue(func() int {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return 0
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != "k8s.io/api" {
+			continue
+		}
+		parts := strings.Split(strings.TrimPrefix(dep.Version, "v"), ".")
+		if len(parts) < 2 {
+			return 0
+		}
+		minor, _ := strconv.Atoi(parts[1])
+		return minor
+	}
+	return 0
+})
@@ -0,0 +1,541 @@
+// Package watch streams events, namespaces and contexts from clusters, reconnecting and
+// classifying failures, and derives the higher level changes kubeve reports, such as
+// rollouts, scaling and restarts. A Hub shares one stream between subscribers.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/a0xAi/kubeve/kube/client"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	apiwatch "k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Source is the kube.EventSource of a cluster: the kubeconfig contexts in Contexts
+// merged as by the Contexts func, or the current context when it lists at most one. The
+// other fields shape the lists and watches of events, and apply to watches started
+// after they are set.
+type Source struct {
+	Contexts []string
+	// WarningsOnly restricts lists and watches to type=Warning with a server-side field
+	// selector, which saves bandwidth on clusters dominated by Normal events.
+	WarningsOnly bool
+	// FieldSelector, such as "involvedObject.kind=Pod,reason!=Pulled", is passed to every
+	// list and watch of events. Fields may be given with core/v1 names
+	// (involvedObject.kind, source) or events.k8s.io/v1 names (regarding.kind,
+	// reportingController); they are translated for whichever API is watched. Watch fails
+	// when it does not parse, see ParseFieldSelector.
+	FieldSelector string
+	// ListLimit caps the number of existing events listed when a watch starts or has to
+	// relist; 0 lists all. Clusters with tens of thousands of events otherwise take long
+	// to list and keep every one of them in memory.
+	ListLimit int
+	// Backfill makes watches deliver the existing events that happened within it before
+	// the watch started, oldest first, ahead of new ones; 0 delivers none. Only what the
+	// API server still retains can be backfilled.
+	Backfill time.Duration
+}
+
+// Watch watches the events of scope, see Namespaces.
+func (s Source) Watch(ctx context.Context, scope string, handlers kube.EventHandlers, onStatus func(kube.WatchStatus)) error {
+	opts, err := s.eventOptions()
+	if err != nil {
+		return err
+	}
+	if len(s.Contexts) > 1 {
+		return watchContexts(ctx, opts, s.Contexts, scope, handlers, onStatus)
+	}
+	return watchNamespaces(ctx, opts, "", scope, handlers, onStatus)
+}
+
+// eventOptions shape the lists and watches of events, see Source.
+type eventOptions struct {
+	warningsOnly bool
+	// fieldSelector is in core/v1 field names, nil when there is none.
+	fieldSelector fields.Selector
+	listLimit     int
+	backfill      time.Duration
+}
+
+func (s Source) eventOptions() (eventOptions, error) {
+	selector, err := parseFieldSelector(s.FieldSelector)
+	if err != nil {
+		return eventOptions{}, err
+	}
+	return eventOptions{
+		warningsOnly:  s.WarningsOnly,
+		fieldSelector: selector,
+		listLimit:     max(s.ListLimit, 0),
+		backfill:      max(s.Backfill, 0),
+	}, nil
+}
+
+// ParseFieldSelector checks a selector for Source.FieldSelector and returns it in
+// core/v1 field names, or "" when it is empty.
+func ParseFieldSelector(selector string) (string, error) {
+	parsed, err := parseFieldSelector(selector)
+	if err != nil || parsed == nil {
+		return "", err
+	}
+	return parsed.String(), nil
+}
+
+func parseFieldSelector(selector string) (fields.Selector, error) {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return nil, nil
+	}
+	parsed, err := fields.ParseSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("parse field selector: %w", err)
+	}
+	parsed, err = parsed.Transform(func(field, value string) (string, string, error) {
+		return coreEventField(field), value, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parse field selector: %w", err)
+	}
+	return parsed, nil
+}
+
+// eventFieldSelector combines warningsOnly and the configured selector for the core/v1
+// or the events.k8s.io/v1 API.
+func (o eventOptions) eventFieldSelector(v1 bool) string {
+	var selectors []fields.Selector
+	if o.warningsOnly {
+		selectors = append(selectors, fields.OneTermEqualSelector("type", corev1.EventTypeWarning))
+	}
+	if o.fieldSelector != nil {
+		selectors = append(selectors, o.fieldSelector)
+	}
+	if len(selectors) == 0 {
+		return ""
+	}
+	selector := fields.AndSelectors(selectors...)
+	if v1 {
+		// Only renames fields, so it cannot fail.
+		selector, _ = selector.Transform(func(field, value string) (string, string, error) {
+			return eventsV1Field(field), value, nil
+		})
+	}
+	return selector.String()
+}
+
+// coreEventField maps events.k8s.io/v1 field names to their core/v1 equivalents.
+func coreEventField(field string) string {
+	switch {
+	case strings.HasPrefix(field, "regarding."):
+		return "involvedObject." + strings.TrimPrefix(field, "regarding.")
+	case field == "reportingController":
+		return "reportingComponent"
+	}
+	return field
+}
+
+// eventsV1Field maps core/v1 field names to their events.k8s.io/v1 equivalents.
+func eventsV1Field(field string) string {
+	switch {
+	case strings.HasPrefix(field, "involvedObject."):
+		return "regarding." + strings.TrimPrefix(field, "involvedObject.")
+	case field == "reportingComponent", field == "source":
+		return "reportingController"
+	}
+	return field
+}
+
+// eventResync re-delivers the informer's store periodically; unchanged events are
+// filtered out, so it only repairs missed updates.
+const eventResync = 10 * time.Minute
+
+const eventListPageSize = 500
+
+// Events runs an informer over events through the events.k8s.io/v1 API, which
+// carries series counts and the reporting controller. Clusters that do not serve it
+// (before 1.19) or roles that only allow core/v1 events are watched through core/v1 instead.
+//
+// Events that exist when the watch starts are not delivered; a Source with Backfill
+// delivers those within the window by time before any change. Events itself lists and
+// watches without a Source's filters and limits. The informer reconnects with backoff,
+// relists when its resourceVersion expires (410 Gone) and delivers only real changes
+// from the relist; connection changes go to onStatus (may be nil). Errors are wrapped
+// in an Error with their class, and how long the watch waits before retrying depends on
+// the class. Events returns on ctx cancellation and on authentication or permission
+// errors.
+func Events(ctx context.Context, namespace string, handlers kube.EventHandlers, onStatus func(kube.WatchStatus)) error {
+	return watchEvents(ctx, eventOptions{}, "", namespace, handlers, onStatus)
+}
+
+// watchEvents is Events with opts through a kubeconfig context, the current one when
+// empty.
+func watchEvents(ctx context.Context, opts eventOptions, kubeContext, namespace string, handlers kube.EventHandlers, onStatus func(kube.WatchStatus)) error {
+	clientset, err := client.ContextClient(kubeContext)
+	if err != nil {
+		return fmt.Errorf("initialize kubernetes client: %w", err)
+	}
+	notify := func(status kube.WatchStatus) {
+		if onStatus != nil {
+			onStatus(status)
+		}
+	}
+
+	api := eventsAPI{clientset: clientset, namespace: namespace, v1: true, opts: opts}
+	// Asked once: the core/v1 fallback below reads events of the same server.
+	newer := serverNewerThanBuild(ctx, clientset)
+	api.raw, err = rawEventsResource(clientset, true, newer)
+	if err == nil {
+		err = api.probe(ctx)
+	}
+	// RBAC written for core/v1 events only, or an old cluster, falls back to core/v1.
+	if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+		api.v1 = false
+		api.raw, err = rawEventsResource(clientset, false, newer)
+		if err == nil {
+			err = api.probe(ctx)
+		}
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		watchErr := newError(err)
+		errorCounts[watchErr.Class].Add(1)
+		notify(kube.WatchStatus{Err: watchErr, Stopped: true})
+		return fmt.Errorf("list events: %w", watchErr)
+	}
+
+	informerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	fatal := make(chan error, 1)
+
+	// status and delay are only touched from the reflector's goroutine, which runs the
+	// list and watch funcs and the watch error handler.
+	status := kube.WatchStatus{Connected: true}
+	var delay time.Duration
+	// pause waits out the delay the last error asked for before the next attempt.
+	pause := func() error {
+		if delay <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		delay = 0
+		select {
+		case <-timer.C:
+			return nil
+		case <-informerCtx.Done():
+			return informerCtx.Err()
+		}
+	}
+	lw := &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			if err := pause(); err != nil {
+				return nil, err
+			}
+			list, err := api.listPaged(informerCtx, opts, func(listed int) {
+				status.Listing, status.Listed = true, listed
+				notify(status)
+			})
+			if status.Listing {
+				status.Listing = false
+				notify(status)
+			}
+			return list, err
+		},
+		WatchFunc: func(opts metav1.ListOptions) (apiwatch.Interface, error) {
+			if err := pause(); err != nil {
+				return nil, err
+			}
+			watcher, err := api.watch(informerCtx, opts)
+			if err == nil && !status.Connected {
+				status = kube.WatchStatus{Connected: true}
+				notify(status)
+			}
+			return watcher, err
+		},
+	}
+	informer := cache.NewSharedIndexInformer(lw, api.object(), eventResync, cache.Indexers{})
+	_ = informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		if informerCtx.Err() != nil {
+			return
+		}
+		watchErr := newError(err)
+		errorCounts[watchErr.Class].Add(1)
+		if watchErr.Class == ErrorClassAuth {
+			notify(kube.WatchStatus{Err: watchErr, Stopped: true})
+			select {
+			case fatal <- fmt.Errorf("watch events: %w", watchErr):
+			default:
+			}
+			return
+		}
+		status = kube.WatchStatus{Attempt: status.Attempt + 1, Err: watchErr}
+		delay = retryDelay(watchErr, status.Attempt)
+		notify(status)
+	})
+	// Backfilled events arrive in list order and are held until the initial list is
+	// complete, then delivered sorted by time: by the first change or, when none comes,
+	// once the handler has synced. mu keeps the two from delivering concurrently.
+	var (
+		mu        sync.Mutex
+		backfills []kube.Event
+		flushed   bool
+	)
+	backfillSince := time.Now().Add(-opts.backfill)
+	flush := func() {
+		if flushed {
+			return
+		}
+		flushed = true
+		sort.SliceStable(backfills, func(i, j int) bool { return backfills[i].Time.Before(backfills[j].Time) })
+		for _, event := range backfills {
+			handlers.OnAdd(event)
+		}
+		backfills = nil
+	}
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if handlers.OnAdd == nil {
+				return
+			}
+			event, ok := toEvent(obj)
+			mu.Lock()
+			defer mu.Unlock()
+			if isInInitialList {
+				if ok && opts.backfill > 0 && !event.Time.Before(backfillSince) {
+					backfills = append(backfills, event)
+				}
+				return
+			}
+			flush()
+			if ok {
+				handlers.OnAdd(event)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if handlers.OnUpdate == nil || sameResourceVersion(oldObj, newObj) {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if handlers.OnAdd != nil {
+				flush()
+			}
+			old, okOld := toEvent(oldObj)
+			event, ok := toEvent(newObj)
+			if okOld && ok {
+				handlers.OnUpdate(old, event)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if handlers.OnDelete == nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if handlers.OnAdd != nil {
+				flush()
+			}
+			if event, ok := toEvent(obj); ok {
+				handlers.OnDelete(event)
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("watch events: %w", err)
+	}
+
+	notify(status)
+	go informer.Run(informerCtx.Done())
+	if opts.backfill > 0 && handlers.OnAdd != nil {
+		go func() {
+			if cache.WaitForCacheSync(informerCtx.Done(), registration.HasSynced) {
+				mu.Lock()
+				flush()
+				mu.Unlock()
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-fatal:
+		return err
+	}
+}
+
+func toEvent(obj interface{}) (kube.Event, bool) {
+	switch event := obj.(type) {
+	case *eventsv1.Event:
+		return kube.NewEventV1(event), true
+	case *corev1.Event:
+		return kube.NewEvent(event), true
+	case *unstructured.Unstructured:
+		ev, err := NewEventUnstructured(event)
+		return ev, err == nil
+	default:
+		return kube.Event{}, false
+	}
+}
+
+// sameResourceVersion reports resyncs, which re-deliver an unchanged object as an update.
+func sameResourceVersion(oldObj, newObj interface{}) bool {
+	oldMeta, err := meta.Accessor(oldObj)
+	if err != nil {
+		return false
+	}
+	newMeta, err := meta.Accessor(newObj)
+	if err != nil {
+		return false
+	}
+	return oldMeta.GetResourceVersion() == newMeta.GetResourceVersion()
+}
+
+// eventsAPI lists and watches events through events.k8s.io/v1 or core/v1, always with
+// the configured field selectors.
+type eventsAPI struct {
+	clientset *kubernetes.Clientset
+	namespace string
+	v1        bool
+	opts      eventOptions
+	// raw, when set, reads events as unstructured JSON instead, for clusters that may
+	// send fields the typed events do not know.
+	raw dynamic.NamespaceableResourceInterface
+}
+
+// probe checks that the API is served and readable with a minimal list.
+func (a eventsAPI) probe(ctx context.Context) error {
+	_, err := a.list(ctx, metav1.ListOptions{Limit: 1})
+	return err
+}
+
+func (a eventsAPI) object() runtime.Object {
+	if a.raw != nil {
+		return &unstructured.Unstructured{}
+	}
+	if a.v1 {
+		return &eventsv1.Event{}
+	}
+	return &corev1.Event{}
+}
+
+func (a eventsAPI) list(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+	opts.FieldSelector = a.opts.eventFieldSelector(a.v1)
+	if a.raw != nil {
+		return a.raw.Namespace(a.namespace).List(ctx, opts)
+	}
+	if a.v1 {
+		return a.clientset.EventsV1().Events(a.namespace).List(ctx, opts)
+	}
+	return a.clientset.CoreV1().Events(a.namespace).List(ctx, opts)
+}
+
+// listPaged lists events in pages of eventListPageSize up to the list limit and returns them
+// as one list, reporting the running total to progress after each page.
+func (a eventsAPI) listPaged(ctx context.Context, opts metav1.ListOptions, progress func(listed int)) (runtime.Object, error) {
+	// "0" is served from the watch cache, which ignores limits on older servers.
+	if opts.ResourceVersion == "0" {
+		opts.ResourceVersion = ""
+	}
+	opts.ResourceVersionMatch = ""
+	opts.Limit = eventListPageSize
+	opts.Continue = ""
+
+	var result runtime.Object
+	listed := 0
+	for {
+		page, err := a.list(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		switch list := page.(type) {
+		case *eventsv1.EventList:
+			if result == nil {
+				result = list
+			} else {
+				merged := result.(*eventsv1.EventList)
+				merged.Items = append(merged.Items, list.Items...)
+			}
+			listed += len(list.Items)
+		case *corev1.EventList:
+			if result == nil {
+				result = list
+			} else {
+				merged := result.(*corev1.EventList)
+				merged.Items = append(merged.Items, list.Items...)
+			}
+			listed += len(list.Items)
+		case *unstructured.UnstructuredList:
+			if result == nil {
+				result = list
+			} else {
+				merged := result.(*unstructured.UnstructuredList)
+				merged.Items = append(merged.Items, list.Items...)
+			}
+			listed += len(list.Items)
+		}
+		listMeta, err := meta.ListAccessor(page)
+		if err != nil {
+			return nil, err
+		}
+		if listMeta.GetContinue() == "" {
+			break
+		}
+		progress(listed)
+		if a.opts.listLimit > 0 && listed >= a.opts.listLimit {
+			break
+		}
+		opts.Continue = listMeta.GetContinue()
+	}
+
+	// Every page comes from the snapshot of the first one, whose resourceVersion the
+	// watch resumes from.
+	switch list := result.(type) {
+	case *eventsv1.EventList:
+		if limit := a.opts.listLimit; limit > 0 && len(list.Items) > limit {
+			list.Items = list.Items[:limit]
+		}
+		list.Continue = ""
+	case *corev1.EventList:
+		if limit := a.opts.listLimit; limit > 0 && len(list.Items) > limit {
+			list.Items = list.Items[:limit]
+		}
+		list.Continue = ""
+	case *unstructured.UnstructuredList:
+		if limit := a.opts.listLimit; limit > 0 && len(list.Items) > limit {
+			list.Items = list.Items[:limit]
+		}
+		list.SetContinue("")
+	}
+	return result, nil
+}
+
+func (a eventsAPI) watch(ctx context.Context, opts metav1.ListOptions) (apiwatch.Interface, error) {
+	opts.FieldSelector = a.opts.eventFieldSelector(a.v1)
+	if a.raw != nil {
+		return a.raw.Namespace(a.namespace).Watch(ctx, opts)
+	}
+	if a.v1 {
+		return a.clientset.EventsV1().Events(a.namespace).Watch(ctx, opts)
+	}
+	return a.clientset.CoreV1().Events(a.namespace).Watch(ctx, opts)
+}
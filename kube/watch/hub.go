@@ -0,0 +1,235 @@
+package watch
+
+import (
+	"context"
+	"sync"
+
+	"github.com/a0xAi/kubeve/kube"
+)
+
+const (
+	hubHistorySize      = 10000
+	hubSubscriberBuffer = 1024
+)
+
+// Hub fans one upstream event watch per namespace scope out to any number of
+// subscribers, so views and sinks do not each open their own watch.
+type Hub struct {
+	source   kube.EventSource
+	mu       sync.Mutex
+	streams  map[string]*hubStream
+	onStatus func(namespace string, status kube.WatchStatus)
+}
+
+type hubStream struct {
+	namespace string
+	cancel    context.CancelFunc
+	subs      map[*hubSubscriber]bool
+	history   []eventChange
+}
+
+type changeKind int
+
+const (
+	changeAdd changeKind = iota
+	changeUpdate
+	changeDelete
+)
+
+// eventChange is one informer notification; old is only set for updates.
+type eventChange struct {
+	kind  changeKind
+	event kube.Event
+	old   kube.Event
+}
+
+type hubSubscriber struct {
+	filter   func(kube.Event) bool
+	handlers kube.EventHandlers
+	onClose  func(error)
+	backlog  []eventChange
+	events   chan eventChange
+	done     chan struct{}
+	once     sync.Once
+	err      error
+}
+
+// NewHub returns an empty hub over source. Upstream watches start with the first
+// subscriber of a namespace and stop when its last subscriber leaves.
+func NewHub(source kube.EventSource) *Hub {
+	return &Hub{source: source, streams: make(map[string]*hubStream)}
+}
+
+// SetSource makes upstream watches started from now on read from source, e.g. with
+// other filters. Running watches keep theirs until their last subscriber leaves.
+func (h *Hub) SetSource(source kube.EventSource) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.source = source
+}
+
+// OnStatus registers fn to be told when an upstream watch loses or regains its
+// connection. Register it before the first Subscribe.
+func (h *Hub) OnStatus(fn func(namespace string, status kube.WatchStatus)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onStatus = fn
+}
+
+// Subscribe delivers events of namespace (all namespaces when empty, several when comma
+// separated, e.g. "team-a,team-b") that pass filter
+// (nil accepts everything) to handler, starting with the events the upstream watch has
+// already seen. New and updated events both go to handler; deletions are dropped. See
+// SubscribeChanges for the rest.
+func (h *Hub) Subscribe(namespace string, filter func(kube.Event) bool, handler func(kube.Event), onClose func(error)) (unsubscribe func()) {
+	return h.SubscribeChanges(namespace, filter, kube.EventHandlers{
+		OnAdd:    handler,
+		OnUpdate: func(_, event kube.Event) { handler(event) },
+	}, onClose)
+}
+
+// SubscribeChanges is Subscribe with separate callbacks for added, updated and deleted
+// events. Each subscriber gets its changes in order on its own goroutine. onClose, if
+// set, is called once the upstream watch ends, with its error or nil; it is not called
+// after unsubscribe. A subscriber that falls far behind slows down the shared watch.
+func (h *Hub) SubscribeChanges(namespace string, filter func(kube.Event) bool, handlers kube.EventHandlers, onClose func(error)) (unsubscribe func()) {
+	sub := &hubSubscriber{
+		filter:   filter,
+		handlers: handlers,
+		onClose:  onClose,
+		events:   make(chan eventChange, hubSubscriberBuffer),
+		done:     make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	stream, ok := h.streams[namespace]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream = &hubStream{namespace: namespace, cancel: cancel, subs: make(map[*hubSubscriber]bool)}
+		h.streams[namespace] = stream
+		go h.run(ctx, h.source, stream)
+	}
+	sub.backlog = append([]eventChange(nil), stream.history...)
+	stream.subs[sub] = true
+	h.mu.Unlock()
+
+	go sub.run()
+
+	return func() {
+		sub.once.Do(func() { close(sub.done) })
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(stream.subs, sub)
+		if len(stream.subs) == 0 && h.streams[namespace] == stream {
+			delete(h.streams, namespace)
+			stream.cancel()
+		}
+	}
+}
+
+func (h *Hub) run(ctx context.Context, source kube.EventSource, stream *hubStream) {
+	publish := func(change eventChange) {
+		h.mu.Lock()
+		stream.history = append(stream.history, change)
+		if len(stream.history) > hubHistorySize {
+			stream.history = append([]eventChange(nil), stream.history[len(stream.history)-hubHistorySize/2:]...)
+		}
+		subs := make([]*hubSubscriber, 0, len(stream.subs))
+		for sub := range stream.subs {
+			subs = append(subs, sub)
+		}
+		h.mu.Unlock()
+
+		for _, sub := range subs {
+			select {
+			case sub.events <- change:
+			case <-sub.done:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+	err := source.Watch(ctx, stream.namespace, kube.EventHandlers{
+		OnAdd: func(event kube.Event) {
+			publish(eventChange{kind: changeAdd, event: event})
+		},
+		OnUpdate: func(old, event kube.Event) {
+			publish(eventChange{kind: changeUpdate, event: event, old: old})
+		},
+		OnDelete: func(event kube.Event) {
+			publish(eventChange{kind: changeDelete, event: event})
+		},
+	}, func(status kube.WatchStatus) {
+		h.mu.Lock()
+		onStatus := h.onStatus
+		h.mu.Unlock()
+		if onStatus != nil {
+			onStatus(stream.namespace, status)
+		}
+	})
+
+	h.mu.Lock()
+	if h.streams[stream.namespace] == stream {
+		delete(h.streams, stream.namespace)
+	}
+	subs := stream.subs
+	stream.subs = make(map[*hubSubscriber]bool)
+	h.mu.Unlock()
+	// A cancelled context means every subscriber left; nobody is waiting for onClose.
+	left := ctx.Err() != nil
+	stream.cancel()
+	if left {
+		return
+	}
+	for sub := range subs {
+		sub.err = err
+		close(sub.events)
+	}
+}
+
+func (s *hubSubscriber) run() {
+	for _, change := range s.backlog {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+		s.deliver(change)
+	}
+	s.backlog = nil
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case change, ok := <-s.events:
+			if !ok {
+				if s.onClose != nil {
+					s.onClose(s.err)
+				}
+				return
+			}
+			s.deliver(change)
+		}
+	}
+}
+
+func (s *hubSubscriber) deliver(change eventChange) {
+	if s.filter != nil && !s.filter(change.event) {
+		return
+	}
+	switch change.kind {
+	case changeAdd:
+		if s.handlers.OnAdd != nil {
+			s.handlers.OnAdd(change.event)
+		}
+	case changeUpdate:
+		if s.handlers.OnUpdate != nil {
+			s.handlers.OnUpdate(change.old, change.event)
+		}
+	case changeDelete:
+		if s.handlers.OnDelete != nil {
+			s.handlers.OnDelete(change.event)
+		}
+	}
+}
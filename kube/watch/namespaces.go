@@ -0,0 +1,184 @@
+package watch
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/a0xAi/kubeve/kube"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NamespaceChange is a namespace that was created, started terminating or was deleted.
+type NamespaceChange struct {
+	Name        string
+	Terminating bool
+	Deleted     bool
+}
+
+// NamespaceChanges calls onChange, from one goroutine, for namespaces created,
+// terminating or deleted until ctx is done. Namespaces that exist when the watch starts
+// are only reported if they are already terminating. Without permission to watch
+// namespaces it keeps retrying in the background and reports nothing.
+func NamespaceChanges(ctx context.Context, clientset *kubernetes.Clientset, onChange func(NamespaceChange)) error {
+	factory := informers.NewSharedInformerFactory(clientset, 0)
+	informer := factory.Core().V1().Namespaces().Informer()
+	terminating := func(obj interface{}) bool {
+		ns, ok := obj.(*corev1.Namespace)
+		return ok && (ns.Status.Phase == corev1.NamespaceTerminating || ns.DeletionTimestamp != nil)
+	}
+	name := func(obj interface{}) string {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		if ns, ok := obj.(*corev1.Namespace); ok {
+			return ns.Name
+		}
+		return ""
+	}
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if !isInInitialList || terminating(obj) {
+				onChange(NamespaceChange{Name: name(obj), Terminating: terminating(obj)})
+			}
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if !terminating(old) && terminating(obj) {
+				onChange(NamespaceChange{Name: name(obj), Terminating: true})
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if n := name(obj); n != "" {
+				onChange(NamespaceChange{Name: n, Deleted: true})
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+	factory.Start(ctx.Done())
+	return nil
+}
+
+// Namespaces is Events for a namespace scope that may list several namespaces.
+// It runs one watch per namespace and fans their changes into a single stream: handlers
+// are called from one goroutine, one change at a time, in the order changes arrive.
+// onStatus sees the combined connection, which is only up while every watch is, and
+// the combined listing progress. The
+// first watch to fail stops the others and its error is returned.
+func Namespaces(ctx context.Context, scope string, handlers kube.EventHandlers, onStatus func(kube.WatchStatus)) error {
+	return watchNamespaces(ctx, eventOptions{}, "", scope, handlers, onStatus)
+}
+
+// watchNamespaces is Namespaces with opts through a kubeconfig context, the current one
+// when empty.
+func watchNamespaces(ctx context.Context, opts eventOptions, kubeContext, scope string, handlers kube.EventHandlers, onStatus func(kube.WatchStatus)) error {
+	namespaces := kube.SplitNamespaces(scope)
+	if len(namespaces) <= 1 {
+		return watchEvents(ctx, opts, kubeContext, strings.Join(namespaces, ""), handlers, onStatus)
+	}
+	return fanIn(ctx, namespaces, func(ctx context.Context, ns string, handlers kube.EventHandlers, onStatus func(kube.WatchStatus)) error {
+		return watchEvents(ctx, opts, kubeContext, ns, handlers, onStatus)
+	}, handlers, func(combined kube.WatchStatus, _ map[string]kube.WatchStatus) {
+		if onStatus != nil {
+			onStatus(combined)
+		}
+	})
+}
+
+// watchFunc runs one of the watches fanIn combines.
+type watchFunc func(ctx context.Context, key string, handlers kube.EventHandlers, onStatus func(kube.WatchStatus)) error
+
+// fanIn runs watch once per key and delivers their changes to handlers from one
+// goroutine, one change at a time, in the order they arrive. onStatus gets the combined
+// status, which is only connected while every watch is, along with the status of each
+// key. The first watch to fail stops the others and its error is returned.
+func fanIn(ctx context.Context, keys []string, watch watchFunc, handlers kube.EventHandlers, onStatus func(combined kube.WatchStatus, byKey map[string]kube.WatchStatus)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	changes := make(chan eventChange, hubSubscriberBuffer)
+	send := func(change eventChange) {
+		select {
+		case changes <- change:
+		case <-ctx.Done():
+		}
+	}
+
+	var statusMu sync.Mutex
+	statuses := make(map[string]kube.WatchStatus, len(keys))
+	for _, key := range keys {
+		statuses[key] = kube.WatchStatus{Connected: true}
+	}
+	reportStatus := func(key string, status kube.WatchStatus) {
+		statusMu.Lock()
+		statuses[key] = status
+		combined := kube.WatchStatus{Connected: true}
+		listing, listed := false, 0
+		byKey := make(map[string]kube.WatchStatus, len(statuses))
+		for k, s := range statuses {
+			// A stopped watch outranks reconnecting ones: it will not recover by itself.
+			if !s.Connected && (s.Stopped || !combined.Stopped && s.Attempt >= combined.Attempt) {
+				combined = s
+			}
+			listing = listing || s.Listing
+			listed += s.Listed
+			byKey[k] = s
+		}
+		combined.Listing, combined.Listed = listing, listed
+		statusMu.Unlock()
+		onStatus(combined, byKey)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(keys))
+	for _, key := range keys {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			err := watch(ctx, key, kube.EventHandlers{
+				OnAdd: func(event kube.Event) {
+					send(eventChange{kind: changeAdd, event: event})
+				},
+				OnUpdate: func(old, event kube.Event) {
+					send(eventChange{kind: changeUpdate, event: event, old: old})
+				},
+				OnDelete: func(event kube.Event) {
+					send(eventChange{kind: changeDelete, event: event})
+				},
+			}, func(status kube.WatchStatus) {
+				reportStatus(key, status)
+			})
+			if err != nil {
+				errs <- err
+			}
+			// One watch ending leaves the stream incomplete; stop the rest with it.
+			cancel()
+		}(key)
+	}
+	go func() {
+		wg.Wait()
+		close(changes)
+	}()
+
+	for change := range changes {
+		switch {
+		case change.kind == changeAdd && handlers.OnAdd != nil:
+			handlers.OnAdd(change.event)
+		case change.kind == changeUpdate && handlers.OnUpdate != nil:
+			handlers.OnUpdate(change.old, change.event)
+		case change.kind == changeDelete && handlers.OnDelete != nil:
+			handlers.OnDelete(change.event)
+		}
+	}
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return nil
+	}
+}
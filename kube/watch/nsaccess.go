@@ -0,0 +1,97 @@
+package watch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// namespaceProbeWorkers is how many namespaces ProbeNamespaceAccess checks at once.
+	namespaceProbeWorkers = 8
+	// namespaceProbeTimeout bounds the check of one namespace.
+	namespaceProbeTimeout = 5 * time.Second
+)
+
+// ProbeNamespaceAccess checks in the background which namespaces allow listing events
+// and returns those that do not. A SelfSubjectAccessReview asks for core and
+// events.k8s.io events, like the watch falls back from one to the other; where the
+// review itself fails, a list of one event answers instead. Namespaces whose access
+// stays unknown, e.g. after a timeout, are not returned.
+func ProbeNamespaceAccess(ctx context.Context, clientset *kubernetes.Clientset, namespaces []string) map[string]bool {
+	denied := make(map[string]bool)
+	var mu sync.Mutex
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range min(namespaceProbeWorkers, len(namespaces)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ns := range jobs {
+				if allowed, known := canListEvents(ctx, clientset, ns); known && !allowed {
+					mu.Lock()
+					denied[ns] = true
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, ns := range namespaces {
+		select {
+		case jobs <- ns:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return denied
+}
+
+// canListEvents reports whether events in namespace can be listed, and whether that
+// is known at all.
+func canListEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace string) (allowed, known bool) {
+	ctx, cancel := context.WithTimeout(ctx, namespaceProbeTimeout)
+	defer cancel()
+	reviewed := true
+	for _, group := range []string{eventsv1.GroupName, ""} {
+		review, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      "list",
+					Group:     group,
+					Resource:  "events",
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			reviewed = false
+			break
+		}
+		if review.Status.Allowed {
+			return true, true
+		}
+	}
+	if reviewed {
+		return false, true
+	}
+
+	_, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{Limit: 1})
+	if apierrors.IsForbidden(err) {
+		_, err = clientset.EventsV1().Events(namespace).List(ctx, metav1.ListOptions{Limit: 1})
+	}
+	switch {
+	case err == nil:
+		return true, true
+	case apierrors.IsForbidden(err):
+		return false, true
+	default:
+		return false, false
+	}
+}
@@ -0,0 +1,27 @@
+package watch
+
+import (
+	"context"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PodRestarts returns the workload of a pod, e.g. Deployment/api or Pod/debug for a
+// pod without a controller, and how often its containers restarted so far.
+func PodRestarts(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (string, int32, error) {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", 0, err
+	}
+	workload := podWorkload(pod)
+	if workload == "" {
+		workload = "Pod/" + pod.Name
+	}
+	var restarts int32
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		restarts += status.RestartCount
+	}
+	return workload, restarts, nil
+}
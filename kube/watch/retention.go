@@ -0,0 +1,41 @@
+package watch
+
+import (
+	"context"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// retentionMinSamples avoids guessing a TTL from a quiet namespace with a handful of events.
+const retentionMinSamples = 20
+
+// ObservedRetention estimates how long the API server keeps events (its --event-ttl) from
+// the age of the oldest event still present in namespace. ok is false when there are too
+// few events for a meaningful estimate.
+func ObservedRetention(ctx context.Context, clientset *kubernetes.Clientset, namespace string) (time.Duration, bool, error) {
+	evList, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, false, err
+	}
+	if len(evList.Items) < retentionMinSamples {
+		return 0, false, nil
+	}
+	now := time.Now()
+	var oldest time.Time
+	for _, event := range evList.Items {
+		ts := kube.EventTime(event)
+		if ts.IsZero() {
+			continue
+		}
+		if oldest.IsZero() || ts.Before(oldest) {
+			oldest = ts
+		}
+	}
+	if oldest.IsZero() {
+		return 0, false, nil
+	}
+	return now.Sub(oldest), true, nil
+}
@@ -0,0 +1,91 @@
+package watch
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	revisionAnnotation    = "deployment.kubernetes.io/revision"
+	revisionLookupTimeout = 5 * time.Second
+)
+
+// scaledReplicaSet extracts the ReplicaSet from ScalingReplicaSet messages such as
+// "Scaled up replica set api-7d9f to 3 from 2".
+var scaledReplicaSet = regexp.MustCompile(`(?i)scaled (?:up|down) replica set (\S+)`)
+
+// RevisionResolver maps rollout events to the Deployment revision they belong to, using
+// the revision annotation of the ReplicaSet involved. Lookups are cached, including misses.
+type RevisionResolver struct {
+	clientset *kubernetes.Clientset
+	mu        sync.Mutex
+	cache     map[string]string
+}
+
+func NewRevisionResolver(clientset *kubernetes.Clientset) *RevisionResolver {
+	return &RevisionResolver{clientset: clientset, cache: make(map[string]string)}
+}
+
+// Revision returns the rollout revision of ScalingReplicaSet events on Deployments and of
+// events on ReplicaSets, or "" for other events or when the ReplicaSet is gone.
+func (r *RevisionResolver) Revision(ctx context.Context, event kube.Event) string {
+	rsName := replicaSetOf(event)
+	if rsName == "" || r == nil || r.clientset == nil {
+		return ""
+	}
+	key := event.Namespace + "/" + rsName
+	if revision, ok := r.cached(key); ok {
+		return revision
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, revisionLookupTimeout)
+	defer cancel()
+	rs, err := r.clientset.AppsV1().ReplicaSets(event.Namespace).Get(lookupCtx, rsName, metav1.GetOptions{})
+	revision := ""
+	if err == nil {
+		revision = rs.Annotations[revisionAnnotation]
+	} else if ctx.Err() != nil {
+		return ""
+	}
+	r.mu.Lock()
+	r.cache[key] = revision
+	r.mu.Unlock()
+	return revision
+}
+
+// CachedRevision is Revision without querying the API server: ok is false when the
+// revision has not been looked up yet and Revision would have to.
+func (r *RevisionResolver) CachedRevision(event kube.Event) (revision string, ok bool) {
+	rsName := replicaSetOf(event)
+	if rsName == "" || r == nil || r.clientset == nil {
+		return "", true
+	}
+	return r.cached(event.Namespace + "/" + rsName)
+}
+
+func (r *RevisionResolver) cached(key string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	revision, ok := r.cache[key]
+	return revision, ok
+}
+
+// replicaSetOf returns the ReplicaSet whose revision an event belongs to, or "" for
+// events without one.
+func replicaSetOf(event kube.Event) string {
+	switch {
+	case event.Kind == "Deployment" && event.Reason == "ScalingReplicaSet":
+		if match := scaledReplicaSet.FindStringSubmatch(event.Message); match != nil {
+			return match[1]
+		}
+	case event.Kind == "ReplicaSet":
+		return event.Name
+	}
+	return ""
+}
@@ -0,0 +1,178 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Backoff before watching the Deployments of a namespace again after its cache did not
+// fill, e.g. without permission to watch them: it doubles with each failure up to the max.
+const (
+	scaleRetryBackoff    = 30 * time.Second
+	scaleRetryBackoffMax = 10 * time.Minute
+)
+
+// ScaleTracker keeps the Deployments of namespaces with rollout activity in an informer
+// cache, so ScalingReplicaSet events can be annotated with the Deployment's replica
+// counts as they were when the event arrived.
+type ScaleTracker struct {
+	clientset *kubernetes.Clientset
+	mu        sync.Mutex
+	caches    map[string]*deploymentCache
+	stopped   bool
+}
+
+type deploymentCache struct {
+	lister appslisters.DeploymentLister
+	synced cache.InformerSynced
+	stop   chan struct{}
+	// failed is set when the cache did not fill in time; it is started again after
+	// retryAt. failures counts the failures in a row.
+	failed   bool
+	failures int
+	retryAt  time.Time
+}
+
+func NewScaleTracker(clientset *kubernetes.Clientset) *ScaleTracker {
+	return &ScaleTracker{clientset: clientset, caches: make(map[string]*deploymentCache)}
+}
+
+// Replicas describes the desired, ready and up-to-date replicas of the Deployment of a
+// ScalingReplicaSet event, e.g. "desired 3, ready 1, up-to-date 3", or returns "" for
+// other events and when the Deployment cannot be read. The first event of a namespace
+// waits for its cache to fill.
+func (t *ScaleTracker) Replicas(ctx context.Context, event kube.Event) string {
+	if !isScalingEvent(event) || t == nil || t.clientset == nil {
+		return ""
+	}
+	deployments := t.cache(ctx, event.Namespace)
+	if deployments == nil {
+		return ""
+	}
+	return replicasOf(deployments, event)
+}
+
+// CachedReplicas is Replicas without waiting for the namespace's cache to fill, which
+// it starts on first use: ok is false while the cache fills and Replicas would wait.
+func (t *ScaleTracker) CachedReplicas(event kube.Event) (replicas string, ok bool) {
+	if !isScalingEvent(event) || t == nil || t.clientset == nil {
+		return "", true
+	}
+	deployments := t.start(event.Namespace)
+	if deployments == nil {
+		return "", true
+	}
+	if !deployments.synced() {
+		return "", false
+	}
+	return replicasOf(deployments, event), true
+}
+
+func isScalingEvent(event kube.Event) bool {
+	return event.Kind == "Deployment" && event.Reason == "ScalingReplicaSet"
+}
+
+func replicasOf(deployments *deploymentCache, event kube.Event) string {
+	deployment, err := deployments.lister.Deployments(event.Namespace).Get(event.Name)
+	if err != nil {
+		return ""
+	}
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	return fmt.Sprintf("desired %d, ready %d, up-to-date %d",
+		desired, deployment.Status.ReadyReplicas, deployment.Status.UpdatedReplicas)
+}
+
+// cache returns the synced Deployment cache of namespace, starting it on first use.
+func (t *ScaleTracker) cache(ctx context.Context, namespace string) *deploymentCache {
+	deployments := t.start(namespace)
+	if deployments == nil {
+		return nil
+	}
+	if deployments.synced() {
+		return deployments
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, revisionLookupTimeout)
+	defer cancel()
+	if cache.WaitForCacheSync(waitCtx.Done(), deployments.synced) {
+		return deployments
+	}
+	if ctx.Err() == nil {
+		t.fail(deployments)
+	}
+	return nil
+}
+
+// start returns the Deployment cache of namespace, starting its informer on first use
+// and again once the backoff after a failure ran out. It returns nil while a failed
+// cache waits to be retried and after Stop.
+func (t *ScaleTracker) start(namespace string) *deploymentCache {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return nil
+	}
+	previous, ok := t.caches[namespace]
+	if ok && !previous.failed {
+		return previous
+	}
+	if ok && time.Now().Before(previous.retryAt) {
+		return nil
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(t.clientset, 0, informers.WithNamespace(namespace))
+	informer := factory.Apps().V1().Deployments()
+	deployments := &deploymentCache{
+		lister: informer.Lister(),
+		synced: informer.Informer().HasSynced,
+		stop:   make(chan struct{}),
+	}
+	if ok {
+		deployments.failures = previous.failures
+	}
+	factory.Start(deployments.stop)
+	t.caches[namespace] = deployments
+	return deployments
+}
+
+// fail stops a cache that did not fill and schedules its retry.
+func (t *ScaleTracker) fail(deployments *deploymentCache) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if deployments.failed {
+		return
+	}
+	deployments.failed = true
+	deployments.failures++
+	backoff := scaleRetryBackoffMax
+	if deployments.failures < 6 {
+		backoff = min(scaleRetryBackoff<<(deployments.failures-1), scaleRetryBackoffMax)
+	}
+	deployments.retryAt = time.Now().Add(backoff)
+	close(deployments.stop)
+}
+
+// Stop ends all Deployment watches.
+func (t *ScaleTracker) Stop() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	for _, deployments := range t.caches {
+		if !deployments.failed {
+			deployments.failed = true
+			close(deployments.stop)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// StatusChangeSource is the Source of the synthetic events StatusChanges reports.
+const StatusChangeSource = "kubeve"
+
+// StatusChanges watches the Pods and Deployments of a namespace scope and calls
+// onEvent with a synthetic event for status transitions that often leave no Event
+// object behind: Pod phase changes, container restarts, image changes and Deployments
+// becoming available or unavailable. States present when the watch starts are not
+// reported. onEvent may be called from several goroutines. The watches run until ctx is
+// done; without permission to list Pods or Deployments they report nothing.
+func StatusChanges(ctx context.Context, clientset *kubernetes.Clientset, scope string, onEvent func(kube.Event)) error {
+	namespaces := kube.SplitNamespaces(scope)
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	for _, ns := range namespaces {
+		factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0, informers.WithNamespace(ns))
+		_, err := factory.Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, obj interface{}) {
+				oldPod, ok := old.(*corev1.Pod)
+				pod, ok2 := obj.(*corev1.Pod)
+				if ok && ok2 {
+					for _, event := range podStatusChanges(oldPod, pod, time.Now()) {
+						onEvent(event)
+					}
+				}
+			},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = factory.Apps().V1().Deployments().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, obj interface{}) {
+				oldDeployment, ok := old.(*appsv1.Deployment)
+				deployment, ok2 := obj.(*appsv1.Deployment)
+				if ok && ok2 {
+					for _, event := range deploymentStatusChanges(oldDeployment, deployment, time.Now()) {
+						onEvent(event)
+					}
+				}
+			},
+		})
+		if err != nil {
+			return err
+		}
+		factory.Start(ctx.Done())
+	}
+	return nil
+}
+
+// statusChange is a transition found by comparing two versions of an object.
+type statusChange struct {
+	eventType string
+	reason    string
+	message   string
+}
+
+// podStatusChanges compares two versions of a Pod.
+func podStatusChanges(old, pod *corev1.Pod, now time.Time) []kube.Event {
+	var changes []statusChange
+	if old.Status.Phase != "" && old.Status.Phase != pod.Status.Phase {
+		change := statusChange{
+			eventType: corev1.EventTypeNormal,
+			reason:    "PhaseChanged",
+			message:   fmt.Sprintf("Phase %s → %s", old.Status.Phase, pod.Status.Phase),
+		}
+		if pod.Status.Phase == corev1.PodFailed {
+			change.eventType = corev1.EventTypeWarning
+		}
+		if pod.Status.Reason != "" {
+			change.message += ": " + pod.Status.Reason
+		}
+		changes = append(changes, change)
+	}
+
+	previous := make(map[string]corev1.ContainerStatus)
+	for _, status := range slices.Concat(old.Status.InitContainerStatuses, old.Status.ContainerStatuses) {
+		previous[status.Name] = status
+	}
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		before, ok := previous[status.Name]
+		if !ok || status.RestartCount <= before.RestartCount {
+			continue
+		}
+		message := fmt.Sprintf("Container %s restarted (%d restarts)", status.Name, status.RestartCount)
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			message += fmt.Sprintf(", last exit: %s, exit code %d", terminated.Reason, terminated.ExitCode)
+		}
+		changes = append(changes, statusChange{eventType: corev1.EventTypeWarning, reason: "ContainerRestarted", message: message})
+	}
+
+	changes = append(changes, imageChanges(old.Spec.Containers, pod.Spec.Containers)...)
+	return statusEvents(pod.ObjectMeta, "Pod", changes, now)
+}
+
+// deploymentStatusChanges compares two versions of a Deployment.
+func deploymentStatusChanges(old, deployment *appsv1.Deployment, now time.Time) []kube.Event {
+	changes := imageChanges(old.Spec.Template.Spec.Containers, deployment.Spec.Template.Spec.Containers)
+
+	before := deploymentAvailable(old)
+	after := deploymentAvailable(deployment)
+	if before != nil && after != nil && before.Status != after.Status {
+		change := statusChange{
+			eventType: corev1.EventTypeNormal,
+			reason:    "AvailabilityChanged",
+			message:   fmt.Sprintf("Available %s → %s", before.Status, after.Status),
+		}
+		if after.Status != corev1.ConditionTrue {
+			change.eventType = corev1.EventTypeWarning
+		}
+		if after.Reason != "" {
+			change.message += ": " + after.Reason
+		}
+		changes = append(changes, change)
+	}
+	return statusEvents(deployment.ObjectMeta, "Deployment", changes, now)
+}
+
+func deploymentAvailable(deployment *appsv1.Deployment) *appsv1.DeploymentCondition {
+	for i := range deployment.Status.Conditions {
+		if deployment.Status.Conditions[i].Type == appsv1.DeploymentAvailable {
+			return &deployment.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// imageChanges reports containers whose image changed between two container lists.
+func imageChanges(old, containers []corev1.Container) []statusChange {
+	images := make(map[string]string, len(old))
+	for _, container := range old {
+		images[container.Name] = container.Image
+	}
+	var changes []statusChange
+	for _, container := range containers {
+		image, ok := images[container.Name]
+		if !ok || image == container.Image {
+			continue
+		}
+		changes = append(changes, statusChange{
+			eventType: corev1.EventTypeNormal,
+			reason:    "ImageChanged",
+			message:   fmt.Sprintf("Container %s image %s → %s", container.Name, image, container.Image),
+		})
+	}
+	return changes
+}
+
+// statusEvents turns the changes of one object update into events. Their UIDs derive
+// from the object's resourceVersion, so a replayed update keeps its rows.
+func statusEvents(meta metav1.ObjectMeta, kind string, changes []statusChange, now time.Time) []kube.Event {
+	events := make([]kube.Event, 0, len(changes))
+	for i, change := range changes {
+		event := kube.Event{
+			UID:       fmt.Sprintf("status/%s/%s/%d", meta.UID, meta.ResourceVersion, i),
+			Time:      now,
+			Namespace: meta.Namespace,
+			Kind:      kind,
+			Name:      meta.Name,
+			ObjectUID: string(meta.UID),
+			Type:      change.eventType,
+			Reason:    change.reason,
+			Message:   change.message,
+			Count:     1,
+			Source:    StatusChangeSource,
+		}
+		event.Fingerprint = kube.Fingerprint(event)
+		events = append(events, event)
+	}
+	return events
+}
@@ -0,0 +1,144 @@
+package watch
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+// ErrorClass groups watch and list errors by what caused them, which decides how
+// the watch retries.
+type ErrorClass int
+
+const (
+	// ErrorClassOther is any error not covered by another class. The watch waits a few
+	// seconds on top of the informer's backoff.
+	ErrorClassOther ErrorClass = iota
+	// ErrorClassAuth is rejected credentials or missing permissions. The watch stops.
+	ErrorClassAuth
+	// ErrorClassThrottled is the API server asking clients to slow down (429, or a
+	// Retry-After). The watch waits as long as the server asks, or longer each attempt
+	// when it does not say.
+	ErrorClassThrottled
+	// ErrorClassNetwork is a connection that could not be made or was cut. The watch
+	// reconnects with the informer's own backoff.
+	ErrorClassNetwork
+	// ErrorClassGone is a resourceVersion too old to resume from (410 Gone). The watch
+	// relists right away.
+	ErrorClassGone
+)
+
+// errorClasses lists every class, in the order metrics report them.
+var errorClasses = []ErrorClass{ErrorClassAuth, ErrorClassThrottled, ErrorClassNetwork, ErrorClassGone, ErrorClassOther}
+
+const (
+	throttledRetryDelay    = 10 * time.Second
+	throttledRetryDelayMax = time.Minute
+	otherRetryDelay        = 5 * time.Second
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassAuth:
+		return "auth"
+	case ErrorClassThrottled:
+		return "throttled"
+	case ErrorClassNetwork:
+		return "network"
+	case ErrorClassGone:
+		return "gone"
+	default:
+		return "other"
+	}
+}
+
+// Error is a watch or list error with its class. kube.WatchStatus.Err and the errors
+// Events returns carry one.
+type Error struct {
+	Class ErrorClass
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(err error) *Error {
+	return &Error{Class: ClassifyError(err), Err: err}
+}
+
+// ClassifyError returns the class of err, taken from an Error it wraps or
+// worked out from the error itself.
+func ClassifyError(err error) ErrorClass {
+	var watchErr *Error
+	if errors.As(err, &watchErr) {
+		return watchErr.Class
+	}
+	switch {
+	case err == nil:
+		return ErrorClassOther
+	case kube.IsAuthError(err) || apierrors.IsForbidden(err):
+		return ErrorClassAuth
+	case apierrors.IsResourceExpired(err) || apierrors.IsGone(err) ||
+		strings.Contains(err.Error(), "too old resource version"):
+		return ErrorClassGone
+	case apierrors.IsTooManyRequests(err):
+		return ErrorClassThrottled
+	}
+	if _, ok := apierrors.SuggestsClientDelay(err); ok {
+		return ErrorClassThrottled
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err) ||
+		apierrors.IsServiceUnavailable(err) || apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) {
+		return ErrorClassNetwork
+	}
+	return ErrorClassOther
+}
+
+// retryDelay is how long to wait before the next list or watch after err, on top of the
+// informer's backoff; attempt is the number of failed attempts so far.
+func retryDelay(err *Error, attempt int) time.Duration {
+	switch err.Class {
+	case ErrorClassThrottled:
+		if seconds, ok := apierrors.SuggestsClientDelay(err.Err); ok && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		return min(time.Duration(attempt)*throttledRetryDelay, throttledRetryDelayMax)
+	case ErrorClassOther:
+		return otherRetryDelay
+	default:
+		return 0
+	}
+}
+
+// errorCounts is indexed by class.
+var errorCounts [ErrorClassGone + 1]atomic.Int64
+
+// ErrorCounts returns how many watch and list errors of each class all watches of
+// this process have seen.
+func ErrorCounts() map[ErrorClass]int64 {
+	counts := make(map[ErrorClass]int64, len(errorClasses))
+	for _, class := range errorClasses {
+		counts[class] = errorCounts[class].Load()
+	}
+	return counts
+}
+
+// ErrorClasses returns every class, for reporting counts in a stable order.
+func ErrorClasses() []ErrorClass {
+	return append([]ErrorClass(nil), errorClasses...)
+}
@@ -0,0 +1,156 @@
+package watch
+
+import (
+	"context"
+	"sync"
+
+	"github.com/a0xAi/kubeve/kube"
+)
+
+// UpdateType tells what an Update carries.
+type UpdateType int
+
+const (
+	// EventAdded carries a new event.
+	EventAdded UpdateType = iota
+	// EventUpdated carries an event the cluster changed, with its previous state in Old.
+	EventUpdated
+	// EventDeleted carries an event the cluster removed, usually because it expired.
+	EventDeleted
+	// WatchClosed reports that the watch ended on its own, with the reason in Err.
+	WatchClosed
+)
+
+// Update is one item of a Manager's output.
+type Update struct {
+	Type      UpdateType
+	Namespace string
+	Event     kube.Event
+	Old       kube.Event
+	Err       error
+
+	generation int
+}
+
+// Manager owns the event watch of one view: at most one namespace scope is
+// watched at a time, and switching scopes stops the previous watch before the next
+// starts. Every change is delivered on a single channel returned by Updates.
+type Manager struct {
+	hub     *Hub
+	updates chan Update
+
+	mu         sync.Mutex
+	namespace  string
+	running    bool
+	generation int
+	cancel     context.CancelFunc
+	stop       func()
+}
+
+// NewManager returns a stopped manager that watches through hub.
+func NewManager(hub *Hub) *Manager {
+	return &Manager{
+		hub:     hub,
+		updates: make(chan Update, hubSubscriberBuffer),
+	}
+}
+
+// Updates returns the channel all changes of the current watch are delivered on. It is
+// never closed. Updates already queued when the watch is switched or stopped may still
+// arrive; drop those for which IsCurrent reports false.
+func (m *Manager) Updates() <-chan Update {
+	return m.updates
+}
+
+// Start watches namespace (all namespaces when empty, several when comma separated),
+// replacing any running watch, even of the same namespace.
+func (m *Manager) Start(namespace string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopLocked()
+
+	generation := m.generation
+	ctx, cancel := context.WithCancel(context.Background())
+	send := func(update Update) {
+		update.Namespace = namespace
+		update.generation = generation
+		select {
+		case m.updates <- update:
+		case <-ctx.Done():
+		}
+	}
+
+	m.namespace = namespace
+	m.running = true
+	m.cancel = cancel
+	m.stop = m.hub.SubscribeChanges(namespace, nil, kube.EventHandlers{
+		OnAdd: func(event kube.Event) {
+			send(Update{Type: EventAdded, Event: event})
+		},
+		OnUpdate: func(old, event kube.Event) {
+			send(Update{Type: EventUpdated, Event: event, Old: old})
+		},
+		OnDelete: func(event kube.Event) {
+			send(Update{Type: EventDeleted, Event: event})
+		},
+	}, func(err error) {
+		m.mu.Lock()
+		if m.generation == generation {
+			m.running = false
+		}
+		m.mu.Unlock()
+		send(Update{Type: WatchClosed, Err: err})
+	})
+}
+
+// SwitchNamespace watches namespace instead of the current scope. It does nothing when
+// that namespace is already being watched.
+func (m *Manager) SwitchNamespace(namespace string) {
+	m.mu.Lock()
+	same := m.running && m.namespace == namespace
+	m.mu.Unlock()
+	if !same {
+		m.Start(namespace)
+	}
+}
+
+// Stop ends the current watch. Nothing is delivered for it afterwards except updates
+// that were already queued.
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopLocked()
+}
+
+// stopLocked ends the watch and makes its queued updates stale.
+func (m *Manager) stopLocked() {
+	m.generation++
+	if m.stop != nil {
+		m.cancel()
+		m.stop()
+		m.stop = nil
+	}
+	m.running = false
+}
+
+// Namespace returns the scope of the current or last watch.
+func (m *Manager) Namespace() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.namespace
+}
+
+// Running reports whether a watch is active and has not ended on its own.
+func (m *Manager) Running() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.running
+}
+
+// IsCurrent reports whether update belongs to the running watch rather than to one that
+// was switched away from or stopped.
+func (m *Manager) IsCurrent(update Update) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return update.generation == m.generation
+}
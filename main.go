@@ -3,17 +3,70 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
 
+	"github.com/a0xAi/kubeve/audit"
+	"github.com/a0xAi/kubeve/config"
+	"github.com/a0xAi/kubeve/kube/client"
+	"github.com/a0xAi/kubeve/kube/watch"
 	"github.com/a0xAi/kubeve/ui"
 )
 
 func main() {
 	version := "0.5.0"
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		case "manifest":
+			runManifest(version, os.Args[2:])
+			return
+		case "rbac":
+			runRBAC(os.Args[2:])
+			return
+		case "doctor":
+			runDoctor(os.Args[2:])
+			return
+		case "digest":
+			runDigest(os.Args[2:])
+			return
+		case "keys":
+			runKeys(os.Args[2:])
+			return
+		case "replay":
+			runReplay(os.Args[2:])
+			return
+		}
+	}
+
+	defaults := config.Load().Flags
 	showVersion := flag.Bool("v", false, "print version")
 	help := flag.Bool("h", false, "show help")
-	namespace := flag.String("n", "", "Kubernetes namespace to use")
+	namespace := flag.String("n", "", "Kubernetes namespace to use, or a comma separated list")
+	contexts := flag.String("contexts", "", "comma separated kubeconfig contexts to watch as one stream, e.g. prod-eu,prod-us")
+	forObject := flag.String("for", "", "only show events for an object and its descendants, e.g. deployment/foo")
+	warningsOnly := flag.Bool("warnings-only", defaults.WarningsOnly, "only list and watch Warning events (filtered by the API server)")
+	statusChanges := flag.Bool("status-changes", defaults.StatusChanges, "also show Pod and Deployment status transitions (phase, restarts, images, availability) as rows")
+	since := flag.Duration("since", 0, "also show existing events of this long ago, e.g. 2h, from the cluster and the local archive")
+	listLimit := flag.Int("list-limit", 0, "list at most this many existing events when a watch starts, in pages of 500 (0 for all)")
+	fieldSelector := flag.String("field-selector", "", "only list and watch events matching this field selector, e.g. involvedObject.kind=Pod")
+	auditLog := flag.String("audit-log", "", "JSON audit log file to tail and show alongside events")
+	auditVerbs := flag.String("audit-verbs", "", "comma separated audit verbs to show (default: mutating verbs)")
+	record := flag.String("record", "", "record the session to this file in asciinema v2 format")
+	applyConnection := connectionFlags(flag.CommandLine)
 	flag.Parse()
+	applyConnection()
+	watchContexts := watch.SplitContexts(*contexts)
+	if len(watchContexts) > 0 {
+		client.SetContext(watchContexts[0])
+	}
+	selector, err := watch.ParseFieldSelector(*fieldSelector)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
 	if *help {
 		flag.Usage()
@@ -24,5 +77,21 @@ func main() {
 		return
 	}
 
-	ui.StartUI(version, *namespace)
+	ui.StartUI(version, ui.StartOptions{
+		Namespace: *namespace,
+		For:       *forObject,
+		Record:    *record,
+		Watch: watch.Source{
+			Contexts:      watchContexts,
+			WarningsOnly:  *warningsOnly,
+			FieldSelector: selector,
+			ListLimit:     *listLimit,
+			Backfill:      *since,
+		},
+
+		StatusChanges: *statusChanges,
+
+		AuditLog:    *auditLog,
+		AuditFilter: audit.NewFilter(*auditVerbs, true),
+	})
 }
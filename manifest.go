@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/a0xAi/kubeve/config"
+	"github.com/a0xAi/kubeve/manifest"
+)
+
+// runManifest prints ready-to-apply manifests for running kubeve serve in-cluster.
+func runManifest(version string, args []string) {
+	fs := flag.NewFlagSet("manifest", flag.ExitOnError)
+	name := fs.String("name", "kubeve", "name used for the Deployment, ServiceAccount and RBAC objects")
+	namespace := fs.String("namespace", "kubeve", "namespace to install kubeve into")
+	image := fs.String("image", "kubeve:"+version, "container image to run")
+	watchNamespace := fs.String("watch-namespace", "", "only watch events in this namespace (cluster-wide when empty)")
+	replicas := fs.Int("replicas", 1, "number of replicas; more than one enables leader election")
+	healthPort := fs.Int("health-port", 8080, "container port for /healthz and /readyz")
+	useLocalConfig := fs.Bool("use-local-config", false, "embed ~/.kubeve/config.yaml in the ConfigMap instead of the defaults")
+	fs.Parse(args)
+
+	cfg := config.Default
+	if *useLocalConfig {
+		cfg = config.Load()
+	}
+
+	out, err := manifest.Render(manifest.Options{
+		Name:           *name,
+		Namespace:      *namespace,
+		Image:          *image,
+		WatchNamespace: *watchNamespace,
+		Replicas:       int32(*replicas),
+		HealthPort:     int32(*healthPort),
+		Config:         cfg,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(out)
+}
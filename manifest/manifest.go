@@ -0,0 +1,218 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/a0xAi/kubeve/config"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+const configMountPath = "/home/kubeve/.kubeve"
+
+// Options parameterizes the rendered serve-mode manifests.
+type Options struct {
+	Name           string
+	Namespace      string
+	Image          string
+	WatchNamespace string
+	Replicas       int32
+	HealthPort     int32
+	Config         config.Config
+}
+
+// Render returns a multi-document YAML stream with everything needed to run kubeve serve in-cluster.
+func Render(opts Options) ([]byte, error) {
+	if opts.Name == "" || opts.Namespace == "" || opts.Image == "" {
+		return nil, fmt.Errorf("name, namespace and image are required")
+	}
+	if opts.Replicas < 1 {
+		opts.Replicas = 1
+	}
+
+	configData, err := config.Marshal(opts.Config)
+	if err != nil {
+		return nil, fmt.Errorf("marshal config: %w", err)
+	}
+
+	objects := []interface{}{
+		serviceAccount(opts),
+		configMap(opts, configData),
+	}
+	objects = append(objects, rbac(opts)...)
+	objects = append(objects, deployment(opts))
+
+	return marshalDocuments(objects)
+}
+
+// marshalDocuments renders objects as a multi-document YAML stream.
+func marshalDocuments(objects []interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+func objectMeta(opts Options, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: opts.Namespace,
+		Labels:    labels(opts),
+	}
+}
+
+func labels(opts Options) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":     "kubeve",
+		"app.kubernetes.io/instance": opts.Name,
+	}
+}
+
+func serviceAccount(opts Options) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+		ObjectMeta: objectMeta(opts, opts.Name),
+	}
+}
+
+func configMap(opts Options, configData []byte) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: objectMeta(opts, opts.Name+"-config"),
+		Data:       map[string]string{"config.yaml": string(configData)},
+	}
+}
+
+// rbac grants read access to events, cluster-wide unless a watch namespace is set,
+// plus lease access in the install namespace when running more than one replica.
+func rbac(opts Options) []interface{} {
+	eventRules, _ := Rules([]Feature{FeatureEvents})
+	subjects := []rbacv1.Subject{{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      opts.Name,
+		Namespace: opts.Namespace,
+	}}
+
+	var objects []interface{}
+	if opts.WatchNamespace == "" {
+		objects = append(objects,
+			&rbacv1.ClusterRole{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+				ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Labels: labels(opts)},
+				Rules:      eventRules,
+			},
+			&rbacv1.ClusterRoleBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+				ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Labels: labels(opts)},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: opts.Name},
+				Subjects:   subjects,
+			},
+		)
+	} else {
+		objects = append(objects, roleAndBinding(opts, opts.Name+"-events", opts.WatchNamespace, eventRules, subjects)...)
+	}
+
+	if opts.Replicas > 1 {
+		leaseRules, _ := Rules([]Feature{FeatureLeaderElection})
+		objects = append(objects, roleAndBinding(opts, opts.Name+"-leader-election", opts.Namespace, leaseRules, subjects)...)
+	}
+	return objects
+}
+
+func roleAndBinding(opts Options, name, namespace string, rules []rbacv1.PolicyRule, subjects []rbacv1.Subject) []interface{} {
+	meta := metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels(opts)}
+	return []interface{}{
+		&rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+			ObjectMeta: meta,
+			Rules:      rules,
+		},
+		&rbacv1.RoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"},
+			ObjectMeta: meta,
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name},
+			Subjects:   subjects,
+		},
+	}
+}
+
+func deployment(opts Options) *appsv1.Deployment {
+	args := []string{"serve", "-health-addr", ":" + strconv.Itoa(int(opts.HealthPort))}
+	if opts.WatchNamespace != "" {
+		args = append(args, "-n", opts.WatchNamespace)
+	}
+	if opts.Replicas > 1 {
+		args = append(args, "-leader-elect", "-lease-name", opts.Name)
+	}
+
+	probe := func(path string) *corev1.Probe {
+		return &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: path, Port: intstr.FromString("health")},
+			},
+			PeriodSeconds: 10,
+		}
+	}
+	nonRoot := true
+	user := int64(65532)
+	readOnly := true
+	noEscalation := false
+	replicas := opts.Replicas
+
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: objectMeta(opts, opts.Name),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels(opts)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels(opts)},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: opts.Name,
+					Containers: []corev1.Container{{
+						Name:           "kubeve",
+						Image:          opts.Image,
+						Args:           args,
+						Env:            []corev1.EnvVar{{Name: "HOME", Value: "/home/kubeve"}},
+						Ports:          []corev1.ContainerPort{{Name: "health", ContainerPort: opts.HealthPort}},
+						LivenessProbe:  probe("/healthz"),
+						ReadinessProbe: probe("/readyz"),
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "config",
+							MountPath: configMountPath,
+							ReadOnly:  true,
+						}},
+						SecurityContext: &corev1.SecurityContext{
+							RunAsNonRoot:             &nonRoot,
+							RunAsUser:                &user,
+							ReadOnlyRootFilesystem:   &readOnly,
+							AllowPrivilegeEscalation: &noEscalation,
+						},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "config",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: opts.Name + "-config"},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,241 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Feature names a kubeve capability that needs API permissions.
+type Feature string
+
+const (
+	FeatureEvents         Feature = "events"
+	FeatureNamespaces     Feature = "namespaces"
+	FeatureDrillDown      Feature = "drilldown"
+	FeatureLogs           Feature = "logs"
+	FeatureLeaderElection Feature = "leader-election"
+	FeatureStatusChanges  Feature = "status-changes"
+	FeatureSecrets        Feature = "secrets"
+)
+
+// DefaultFeatures are the capabilities used by the interactive UI. FeatureSecrets is
+// left out: reading Secrets has to be granted on purpose.
+var DefaultFeatures = []Feature{FeatureEvents, FeatureNamespaces, FeatureDrillDown, FeatureLogs}
+
+type featureRule struct {
+	group         string
+	resources     []string
+	verbs         []string
+	clusterScoped bool
+}
+
+var featureRules = map[Feature][]featureRule{
+	FeatureEvents: {
+		{group: "", resources: []string{"events"}, verbs: []string{"get", "list", "watch"}},
+		{group: "events.k8s.io", resources: []string{"events"}, verbs: []string{"get", "list", "watch"}},
+	},
+	FeatureNamespaces: {
+		// watch reports namespaces deleted from under the current scope.
+		{group: "", resources: []string{"namespaces"}, verbs: []string{"list", "watch"}, clusterScoped: true},
+	},
+	FeatureDrillDown: {
+		{group: "", resources: []string{"pods", "services", "persistentvolumeclaims"}, verbs: []string{"get", "list"}},
+		{group: "", resources: []string{"resourcequotas", "limitranges"}, verbs: []string{"list"}},
+		{group: "", resources: []string{"configmaps"}, verbs: []string{"get"}},
+		{group: "", resources: []string{"events"}, verbs: []string{"list"}},
+		{group: "apps", resources: []string{"deployments", "replicasets", "statefulsets", "daemonsets"}, verbs: []string{"get", "list"}},
+		// The UI puts replica counts on ScalingReplicaSet events from a Deployment informer.
+		{group: "apps", resources: []string{"deployments"}, verbs: []string{"watch"}},
+		{group: "batch", resources: []string{"jobs", "cronjobs"}, verbs: []string{"get", "list"}},
+		{group: "autoscaling", resources: []string{"horizontalpodautoscalers"}, verbs: []string{"get"}},
+		{group: "networking.k8s.io", resources: []string{"ingresses"}, verbs: []string{"get"}},
+		{group: "", resources: []string{"nodes", "persistentvolumes", "namespaces"}, verbs: []string{"get"}, clusterScoped: true},
+		{group: "storage.k8s.io", resources: []string{"storageclasses"}, verbs: []string{"get"}, clusterScoped: true},
+		{group: "scheduling.k8s.io", resources: []string{"priorityclasses"}, verbs: []string{"get"}, clusterScoped: true},
+		{group: "admissionregistration.k8s.io", resources: []string{"validatingwebhookconfigurations", "mutatingwebhookconfigurations"}, verbs: []string{"list"}, clusterScoped: true},
+		{group: "", resources: []string{"pods"}, verbs: []string{"list"}, clusterScoped: true},
+		// Container and node usage from metrics-server.
+		{group: "metrics.k8s.io", resources: []string{"pods"}, verbs: []string{"get"}},
+		{group: "metrics.k8s.io", resources: []string{"nodes"}, verbs: []string{"get"}, clusterScoped: true},
+	},
+	FeatureLogs: {
+		{group: "", resources: []string{"pods"}, verbs: []string{"get"}},
+		{group: "", resources: []string{"pods/log"}, verbs: []string{"get"}},
+	},
+	FeatureStatusChanges: {
+		{group: "", resources: []string{"pods"}, verbs: []string{"list", "watch"}},
+		{group: "apps", resources: []string{"deployments"}, verbs: []string{"list", "watch"}},
+	},
+	FeatureSecrets: {
+		// The Secret drill-down, its values and the certificates of TLS secrets.
+		{group: "", resources: []string{"secrets"}, verbs: []string{"get"}},
+	},
+	FeatureLeaderElection: {
+		{group: "coordination.k8s.io", resources: []string{"leases"}, verbs: []string{"get", "create", "update"}},
+	},
+}
+
+// Features returns all known feature names in a stable order.
+func Features() []Feature {
+	features := make([]Feature, 0, len(featureRules))
+	for feature := range featureRules {
+		features = append(features, feature)
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i] < features[j] })
+	return features
+}
+
+// ParseFeatures parses a comma separated feature list.
+func ParseFeatures(raw string) ([]Feature, error) {
+	var features []Feature
+	for _, part := range strings.Split(raw, ",") {
+		name := Feature(strings.ToLower(strings.TrimSpace(part)))
+		if name == "" {
+			continue
+		}
+		if _, ok := featureRules[name]; !ok {
+			return nil, fmt.Errorf("unknown feature %q (known: %s)", name, joinFeatures(Features()))
+		}
+		features = append(features, name)
+	}
+	if len(features) == 0 {
+		return nil, fmt.Errorf("no features selected")
+	}
+	return features, nil
+}
+
+func joinFeatures(features []Feature) string {
+	names := make([]string, 0, len(features))
+	for _, feature := range features {
+		names = append(names, string(feature))
+	}
+	return strings.Join(names, ", ")
+}
+
+// Rules returns the merged policy rules needed by features. Namespaced rules and
+// rules on cluster-scoped resources are returned separately since only the latter
+// require a ClusterRole when kubeve is restricted to one namespace.
+func Rules(features []Feature) (namespaced []rbacv1.PolicyRule, clusterScoped []rbacv1.PolicyRule) {
+	ns, cluster := collectRules(features)
+	return mergeRules(ns), mergeRules(cluster)
+}
+
+func collectRules(features []Feature) (namespaced []featureRule, clusterScoped []featureRule) {
+	for _, feature := range features {
+		for _, rule := range featureRules[feature] {
+			if rule.clusterScoped {
+				clusterScoped = append(clusterScoped, rule)
+			} else {
+				namespaced = append(namespaced, rule)
+			}
+		}
+	}
+	return namespaced, clusterScoped
+}
+
+// mergeRules unions verbs per group/resource and groups resources sharing the same verbs.
+func mergeRules(rules []featureRule) []rbacv1.PolicyRule {
+	type key struct{ group, resource string }
+	verbs := make(map[key]map[string]bool)
+	for _, rule := range rules {
+		for _, resource := range rule.resources {
+			k := key{rule.group, resource}
+			if verbs[k] == nil {
+				verbs[k] = make(map[string]bool)
+			}
+			for _, verb := range rule.verbs {
+				verbs[k][verb] = true
+			}
+		}
+	}
+
+	type grouped struct {
+		group     string
+		verbs     []string
+		resources []string
+	}
+	byVerbs := make(map[string]*grouped)
+	for k, set := range verbs {
+		list := make([]string, 0, len(set))
+		for verb := range set {
+			list = append(list, verb)
+		}
+		sort.Strings(list)
+		id := k.group + "|" + strings.Join(list, ",")
+		if byVerbs[id] == nil {
+			byVerbs[id] = &grouped{group: k.group, verbs: list}
+		}
+		byVerbs[id].resources = append(byVerbs[id].resources, k.resource)
+	}
+
+	ids := make([]string, 0, len(byVerbs))
+	for id := range byVerbs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	policy := make([]rbacv1.PolicyRule, 0, len(ids))
+	for _, id := range ids {
+		g := byVerbs[id]
+		sort.Strings(g.resources)
+		policy = append(policy, rbacv1.PolicyRule{
+			APIGroups: []string{g.group},
+			Resources: g.resources,
+			Verbs:     g.verbs,
+		})
+	}
+	return policy
+}
+
+// RBACOptions parameterizes RenderRBAC.
+type RBACOptions struct {
+	Name      string
+	Namespace string
+	Features  []Feature
+}
+
+// RenderRBAC returns the least-privilege roles for the selected features. Without a
+// namespace everything goes into one ClusterRole; with a namespace the namespaced
+// rules go into a Role and only cluster-scoped rules remain in a ClusterRole.
+func RenderRBAC(opts RBACOptions) ([]byte, error) {
+	if opts.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	meta := metav1.ObjectMeta{
+		Name:   opts.Name,
+		Labels: map[string]string{"app.kubernetes.io/name": "kubeve"},
+	}
+
+	var objects []interface{}
+	if opts.Namespace == "" {
+		namespaced, clusterScoped := collectRules(opts.Features)
+		objects = append(objects, &rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: meta,
+			Rules:      mergeRules(append(namespaced, clusterScoped...)),
+		})
+	} else {
+		namespaced, clusterScoped := Rules(opts.Features)
+		if len(namespaced) > 0 {
+			roleMeta := meta
+			roleMeta.Namespace = opts.Namespace
+			objects = append(objects, &rbacv1.Role{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+				ObjectMeta: roleMeta,
+				Rules:      namespaced,
+			})
+		}
+		if len(clusterScoped) > 0 {
+			objects = append(objects, &rbacv1.ClusterRole{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+				ObjectMeta: meta,
+				Rules:      clusterScoped,
+			})
+		}
+	}
+
+	return marshalDocuments(objects)
+}
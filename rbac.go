@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/a0xAi/kubeve/manifest"
+)
+
+// runRBAC prints the least-privilege roles for the selected kubeve features.
+func runRBAC(args []string) {
+	fs := flag.NewFlagSet("rbac", flag.ExitOnError)
+	name := fs.String("name", "kubeve", "name of the generated Role/ClusterRole")
+	namespace := fs.String("namespace", "", "generate a namespaced Role for this namespace (ClusterRole when empty)")
+	defaults := make([]string, 0, len(manifest.DefaultFeatures))
+	for _, feature := range manifest.DefaultFeatures {
+		defaults = append(defaults, string(feature))
+	}
+	rawFeatures := fs.String("features", strings.Join(defaults, ","), "comma separated features to grant")
+	list := fs.Bool("list", false, "list available features")
+	fs.Parse(args)
+
+	if *list {
+		for _, feature := range manifest.Features() {
+			fmt.Println(feature)
+		}
+		return
+	}
+
+	features, err := manifest.ParseFeatures(*rawFeatures)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	out, err := manifest.RenderRBAC(manifest.RBACOptions{
+		Name:      *name,
+		Namespace: *namespace,
+		Features:  features,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(out)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/a0xAi/kubeve/archive"
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/a0xAi/kubeve/kube/client"
+	"github.com/a0xAi/kubeve/ui"
+)
+
+// runReplay plays the local event archive of a context back in the event table, for
+// post-mortems and training.
+func runReplay(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	kubeContext := fs.String("context", "", "kubeconfig context whose archive to replay (default: the current context)")
+	namespace := fs.String("n", "", "namespace to replay, or a comma separated list (empty for all namespaces)")
+	since := fs.Duration("since", 24*time.Hour, "replay the archived events of this long ago")
+	from := fs.String("from", "", "replay from this time instead of -since, e.g. 2025-05-01T12:00:00Z")
+	to := fs.String("to", "", "end the replay at this time (default: the last archived event)")
+	speed := fs.Int("speed", 1, "playback speed to start with: 1, 10 or 60")
+	fs.Parse(args)
+
+	start := time.Now().Add(-*since)
+	if *from != "" {
+		start = parseReplayTime("from", *from)
+	}
+	var end time.Time
+	if *to != "" {
+		end = parseReplayTime("to", *to)
+	}
+	if *speed != 1 && *speed != 10 && *speed != 60 {
+		fmt.Fprintln(os.Stderr, "-speed must be 1, 10 or 60")
+		os.Exit(2)
+	}
+
+	name := *kubeContext
+	if name == "" {
+		current, err := client.CurrentContext()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading kubeconfig: %v\n", err)
+			os.Exit(1)
+		}
+		// Named like the live view names the archive it writes.
+		name = current
+		if name == "" {
+			name = "in-cluster"
+		}
+	}
+	archived, err := archive.Read(name, start)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading the archive of %s: %v\n", name, err)
+		os.Exit(1)
+	}
+	var events []kube.Event
+	for _, event := range archived {
+		if end.IsZero() || !event.Time.After(end) {
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		fmt.Fprintf(os.Stderr, "No archived events of %s in that time range. Run :archive in kubeve to start archiving.\n", name)
+		os.Exit(1)
+	}
+
+	if err := ui.StartReplay(events, ui.ReplayOptions{Name: name, Namespace: *namespace, Speed: *speed}); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running the replay: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseReplayTime parses the RFC 3339 time of flag name, exiting when it is invalid.
+func parseReplayTime(name, value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "-%s: %v\n", name, err)
+		os.Exit(2)
+	}
+	return parsed
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/a0xAi/kubeve/audit"
+	"github.com/a0xAi/kubeve/config"
+	"github.com/a0xAi/kubeve/serve"
+)
+
+// runServe starts headless mode: events are written to stdout as JSON lines.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	namespace := fs.String("n", "", "Kubernetes namespace to watch, or a comma separated list (empty for all namespaces)")
+	leaderElect := fs.Bool("leader-elect", false, "only forward events while holding the leader lease")
+	leaseName := fs.String("lease-name", "kubeve", "name of the coordination.k8s.io Lease used for leader election")
+	leaseNamespace := fs.String("lease-namespace", "", "namespace of the leader election Lease (defaults to the pod namespace)")
+	identity := fs.String("identity", "", "leader election identity (defaults to the hostname)")
+	healthAddr := fs.String("health-addr", "", "address for /healthz, /readyz and /metrics endpoints, e.g. :8080 (disabled when empty)")
+	auditLog := fs.String("audit-log", "", "JSON audit log file to tail and forward alongside events")
+	auditWebhookAddr := fs.String("audit-webhook-addr", "", "address to receive audit webhook batches on /audit, e.g. :9443 (disabled when empty)")
+	auditVerbs := fs.String("audit-verbs", "", "comma separated audit verbs to forward (default: mutating verbs)")
+	auditSkipSystem := fs.Bool("audit-skip-system", true, "ignore audit entries from system:* users")
+	warningsOnly := fs.Bool("warnings-only", false, "only list and watch Warning events (filtered by the API server)")
+	listLimit := fs.Int("list-limit", 0, "list at most this many existing events when a watch starts, in pages of 500 (0 for all)")
+	fieldSelector := fs.String("field-selector", "", "only list and watch events matching this field selector, e.g. involvedObject.kind=Pod")
+	configPath := fs.String("config", config.Path(), "config file whose serve section sets the namespace, warnings-only, field selector and list limit, e.g. a mounted ConfigMap")
+	reloadInterval := fs.Duration("reload-interval", 10*time.Second, "how often to check -config for changes and restart the watch with them (0 disables reloading)")
+	applyConnection := connectionFlags(fs)
+	fs.Parse(args)
+	applyConnection()
+
+	// Flags given on the command line win over the config file, also after a reload.
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	settings := func(cfg config.Config) serve.Settings {
+		s := serve.Settings{
+			Namespace:     cfg.Serve.Namespace,
+			WarningsOnly:  cfg.Serve.WarningsOnly,
+			FieldSelector: cfg.Serve.FieldSelector,
+			ListLimit:     cfg.Serve.ListLimit,
+		}
+		if explicit["n"] {
+			s.Namespace = *namespace
+		}
+		if explicit["warnings-only"] {
+			s.WarningsOnly = *warningsOnly
+		}
+		if explicit["field-selector"] {
+			s.FieldSelector = *fieldSelector
+		}
+		if explicit["list-limit"] {
+			s.ListLimit = *listLimit
+		}
+		return s
+	}
+	cfg := config.Default
+	if data, err := os.ReadFile(*configPath); err == nil {
+		if cfg, err = config.Parse(data); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: parse %s: %v\n", *configPath, err)
+			os.Exit(1)
+		}
+	}
+	initial := settings(cfg)
+	if _, err := initial.Source(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var reload <-chan serve.Settings
+	if *reloadInterval > 0 && *configPath != "" {
+		reload = serve.WatchConfig(ctx, *configPath, *reloadInterval, settings)
+	}
+
+	err := serve.Run(ctx, serve.Options{
+		Settings:       initial,
+		Reload:         reload,
+		LeaderElect:    *leaderElect,
+		LeaseName:      *leaseName,
+		LeaseNamespace: *leaseNamespace,
+		Identity:       *identity,
+		HealthAddr:     *healthAddr,
+
+		AuditLog:         *auditLog,
+		AuditWebhookAddr: *auditWebhookAddr,
+		AuditFilter:      audit.NewFilter(*auditVerbs, *auditSkipSystem),
+	}, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
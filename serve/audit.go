@@ -0,0 +1,60 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/a0xAi/kubeve/audit"
+	"github.com/a0xAi/kubeve/kube"
+)
+
+// startAuditWebhook receives audit webhook batches on /audit and forwards matching
+// entries with the events.
+func startAuditWebhook(addr string, filter audit.Filter, f *forwarder) (func(), error) {
+	mux := http.NewServeMux()
+	mux.Handle("/audit", auditHandler(filter, f))
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", addr, err)
+	}
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "audit webhook: %v\n", err)
+		}
+	}()
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}, nil
+}
+
+// auditHandler queues the matching entries of audit webhook batches for the sink. Only
+// the leader acknowledges batches: standby replicas, and a leader whose queue stays full
+// for sinkStallTimeout, answer 503 so the API server retries the batch rather than it
+// being dropped.
+func auditHandler(filter audit.Filter, f *forwarder) http.Handler {
+	webhook := audit.WebhookHandler(filter, func(event kube.Event) error {
+		select {
+		case f.queue <- event:
+			return nil
+		case <-time.After(sinkStallTimeout):
+			return fmt.Errorf("sink queue full for %s", sinkStallTimeout)
+		}
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !f.leading.Load() {
+			http.Error(w, "standby replica, audit entries are forwarded by the leader", http.StatusServiceUnavailable)
+			return
+		}
+		webhook.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,104 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube/watch"
+)
+
+// startHealthServer exposes /healthz and /readyz for Kubernetes probes and /metrics in
+// the Prometheus text format.
+//
+// /healthz fails when the sink is wedged or a write to it failed, so the kubelet restarts
+// the forwarder.
+// /readyz fails while the leader is not watching (or reconnecting) or the sink backlog is above half the queue.
+// Standby replicas are reported ready since they are healthy and waiting for the lease;
+// they answer audit webhook batches with 503 so the API server retries them.
+func startHealthServer(addr string, f *forwarder) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", addr, err)
+	}
+	srv := &http.Server{Handler: healthHandler(f), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "health server: %v\n", err)
+		}
+	}()
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}, nil
+}
+
+// healthHandler serves the probe and metrics endpoints of f.
+func healthHandler(f *forwarder) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if err := f.lastSinkError(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		if f.stalled() {
+			http.Error(w, fmt.Sprintf("sink stalled with %d queued events", f.backlog()), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !f.leading.Load() {
+			fmt.Fprintln(w, "ok (standby)")
+			return
+		}
+		if !f.watching.Load() {
+			http.Error(w, "event watch is not running", http.StatusServiceUnavailable)
+			return
+		}
+		if backlog := f.backlog(); backlog > sinkQueueSize/2 {
+			http.Error(w, fmt.Sprintf("sink backlog too large: %d queued events", backlog), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, "ok (backlog %d)\n", f.backlog())
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writeMetrics(w, f)
+	})
+	return mux
+}
+
+// writeMetrics writes the watch and sink metrics. Watch errors are counted per class, so
+// throttling can be told apart from network trouble or expired watches.
+func writeMetrics(w io.Writer, f *forwarder) {
+	fmt.Fprintln(w, "# HELP kubeve_watch_errors_total Event watch and list errors by class.")
+	fmt.Fprintln(w, "# TYPE kubeve_watch_errors_total counter")
+	counts := watch.ErrorCounts()
+	for _, class := range watch.ErrorClasses() {
+		fmt.Fprintf(w, "kubeve_watch_errors_total{class=%q} %d\n", class.String(), counts[class])
+	}
+	fmt.Fprintln(w, "# HELP kubeve_watch_connected Whether the event watch is connected.")
+	fmt.Fprintln(w, "# TYPE kubeve_watch_connected gauge")
+	fmt.Fprintf(w, "kubeve_watch_connected %d\n", boolMetric(f.watching.Load()))
+	fmt.Fprintln(w, "# HELP kubeve_leader Whether this replica holds the lease and forwards events.")
+	fmt.Fprintln(w, "# TYPE kubeve_leader gauge")
+	fmt.Fprintf(w, "kubeve_leader %d\n", boolMetric(f.leading.Load()))
+	fmt.Fprintln(w, "# HELP kubeve_sink_backlog Events queued for the sink.")
+	fmt.Fprintln(w, "# TYPE kubeve_sink_backlog gauge")
+	fmt.Fprintf(w, "kubeve_sink_backlog %d\n", f.backlog())
+}
+
+func boolMetric(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
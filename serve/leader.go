@@ -0,0 +1,101 @@
+package serve
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube/client"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// runWithLeaderElection blocks until ctx is cancelled, calling run while this instance holds the lease.
+// Losing the lease while ctx is still active is returned as an error so the pod gets restarted.
+func runWithLeaderElection(ctx context.Context, opts Options, run func(context.Context) error) error {
+	homeNamespace, _, clientset, _, err := client.Kinit("")
+	if err != nil {
+		return fmt.Errorf("initialize kubernetes client: %w", err)
+	}
+	leaseNamespace := opts.LeaseNamespace
+	if leaseNamespace == "" {
+		leaseNamespace = homeNamespace
+	}
+	identity := opts.Identity
+	if identity == "" {
+		identity, err = os.Hostname()
+		if err != nil {
+			return fmt.Errorf("resolve leader election identity: %w", err)
+		}
+	}
+
+	lock, err := resourcelock.New(
+		resourcelock.LeasesResourceLock,
+		leaseNamespace,
+		opts.LeaseName,
+		clientset.CoreV1(),
+		clientset.CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: identity},
+	)
+	if err != nil {
+		return fmt.Errorf("create lease lock: %w", err)
+	}
+
+	return lead(ctx, lock, leaseNamespace+"/"+opts.LeaseName, run)
+}
+
+// lead calls run while this instance holds lock, until ctx is cancelled or run returns.
+// When run returns, the lease is released so a standby takes over, and run's error is
+// returned. Losing the lease while ctx is still active is returned as an error so the
+// pod gets restarted.
+func lead(ctx context.Context, lock resourcelock.Interface, lease string, run func(context.Context) error) error {
+	identity := lock.Identity()
+	// Cancelled when run returns: a leader that stopped forwarding must not keep renewing.
+	electionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	runErr := make(chan error, 1)
+	var started atomic.Bool
+	leaderelection.RunOrDie(electionCtx, leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            lease,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				started.Store(true)
+				fmt.Fprintf(os.Stderr, "%s: acquired lease %s, forwarding events\n", identity, lease)
+				runErr <- run(leaderCtx)
+				cancel()
+			},
+			OnStoppedLeading: func() {
+				fmt.Fprintf(os.Stderr, "%s: stopped leading\n", identity)
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					fmt.Fprintf(os.Stderr, "%s: standing by, current leader is %s\n", identity, leader)
+				}
+			},
+		},
+	})
+
+	// The leader context is cancelled by now; let run wind down before returning.
+	if started.Load() {
+		if err := <-runErr; err != nil {
+			return err
+		}
+	}
+	if ctx.Err() == nil {
+		return fmt.Errorf("lost lease %s", lease)
+	}
+	return nil
+}
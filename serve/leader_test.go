@@ -0,0 +1,71 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+func TestLeaderThatStopsForwardingHandsOverTheLease(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	newLock := func(identity string) resourcelock.Interface {
+		lock, err := resourcelock.New(resourcelock.LeasesResourceLock, "kubeve", "kubeve-serve",
+			clientset.CoreV1(), clientset.CoordinationV1(), resourcelock.ResourceLockConfig{Identity: identity})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return lock
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sinkFailed := errors.New("sink failed")
+	leading, fail := make(chan struct{}), make(chan struct{})
+	firstErr := make(chan error, 1)
+	go func() {
+		firstErr <- lead(ctx, newLock("first"), "kubeve/kubeve-serve", func(context.Context) error {
+			close(leading)
+			<-fail
+			return sinkFailed
+		})
+	}()
+	<-leading
+
+	acquired := make(chan struct{})
+	standbyDone := make(chan struct{})
+	go func() {
+		defer close(standbyDone)
+		_ = lead(ctx, newLock("second"), "kubeve/kubeve-serve", func(leaderCtx context.Context) error {
+			close(acquired)
+			<-leaderCtx.Done()
+			return nil
+		})
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("the standby acquired a lease that is held")
+	case <-time.After(3 * retryPeriod):
+	}
+
+	close(fail)
+	select {
+	case err := <-firstErr:
+		if !errors.Is(err, sinkFailed) {
+			t.Fatalf("want the leader to return its run error, got %v", err)
+		}
+	case <-time.After(leaseDuration):
+		t.Fatal("the leader kept the lease after it stopped forwarding")
+	}
+	// Well before the lease would expire on its own.
+	select {
+	case <-acquired:
+	case <-time.After(leaseDuration / 2):
+		t.Fatal("the standby did not take over the released lease")
+	}
+	cancel()
+	<-standbyDone
+}
@@ -0,0 +1,96 @@
+package serve
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/config"
+	"github.com/a0xAi/kubeve/kube/watch"
+)
+
+// Settings are the forwarder settings that can change while serve runs.
+type Settings struct {
+	Namespace     string
+	WarningsOnly  bool
+	FieldSelector string
+	ListLimit     int
+}
+
+// Source returns the event source that watches with the settings. It fails when the
+// field selector is invalid.
+func (s Settings) Source() (watch.Source, error) {
+	if _, err := watch.ParseFieldSelector(s.FieldSelector); err != nil {
+		return watch.Source{}, err
+	}
+	return watch.Source{
+		WarningsOnly:  s.WarningsOnly,
+		FieldSelector: s.FieldSelector,
+		ListLimit:     s.ListLimit,
+	}, nil
+}
+
+// WatchConfig polls the config file at path every interval and sends the settings
+// derived from it whenever its content changes. ConfigMap volumes are updated by
+// swapping a symlink, which polling the content notices without inotify. A file that
+// cannot be read or parsed is reported to stderr and the previous settings stay. The
+// channel is closed when ctx is done.
+func WatchConfig(ctx context.Context, path string, interval time.Duration, settings func(config.Config) Settings) <-chan Settings {
+	out := make(chan Settings)
+	go func() {
+		defer close(out)
+		last, _ := os.ReadFile(path)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				if !os.IsNotExist(err) {
+					fmt.Fprintf(os.Stderr, "reload config: %v\n", err)
+				}
+				continue
+			}
+			if bytes.Equal(data, last) {
+				continue
+			}
+			last = data
+			cfg, err := config.Parse(data)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "reload config %s: %v, keeping the current settings\n", path, err)
+				continue
+			}
+			select {
+			case out <- settings(cfg):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// describeSettings renders settings for the log, e.g. "all namespaces, warnings only".
+func describeSettings(s Settings) string {
+	parts := []string{"all namespaces"}
+	if s.Namespace != "" {
+		parts[0] = "namespace " + s.Namespace
+	}
+	if s.WarningsOnly {
+		parts = append(parts, "warnings only")
+	}
+	if s.FieldSelector != "" {
+		parts = append(parts, "fields "+s.FieldSelector)
+	}
+	if s.ListLimit > 0 {
+		parts = append(parts, fmt.Sprintf("list limit %d", s.ListLimit))
+	}
+	return strings.Join(parts, ", ")
+}
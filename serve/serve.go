@@ -0,0 +1,215 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/a0xAi/kubeve/audit"
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/a0xAi/kubeve/kube/watch"
+)
+
+const (
+	sinkQueueSize    = 1024
+	sinkStallTimeout = 30 * time.Second
+)
+
+// Options configures headless serve mode.
+type Options struct {
+	Settings Settings
+	// Reload delivers changed settings, e.g. from WatchConfig; the watch restarts with
+	// them. Nil keeps the settings for the whole run.
+	Reload         <-chan Settings
+	LeaderElect    bool
+	LeaseName      string
+	LeaseNamespace string
+	Identity       string
+	HealthAddr     string
+	// AuditLog is a JSON audit log file to tail alongside events.
+	AuditLog string
+	// AuditWebhookAddr serves an audit webhook backend receiver, e.g. :9443.
+	AuditWebhookAddr string
+	AuditFilter      audit.Filter
+}
+
+// Run watches events and forwards them to out as JSON lines until ctx is cancelled.
+// With leader election enabled only the replica holding the lease forwards events.
+func Run(ctx context.Context, opts Options, out io.Writer) error {
+	f := newForwarder(out)
+	f.settings = opts.Settings
+	if opts.HealthAddr != "" {
+		stop, err := startHealthServer(opts.HealthAddr, f)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+	if opts.AuditWebhookAddr != "" {
+		stop, err := startAuditWebhook(opts.AuditWebhookAddr, opts.AuditFilter, f)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	if !opts.LeaderElect {
+		f.leading.Store(true)
+		return f.run(ctx, opts)
+	}
+	return runWithLeaderElection(ctx, opts, func(leaderCtx context.Context) error {
+		f.leading.Store(true)
+		defer f.leading.Store(false)
+		return f.run(leaderCtx, opts)
+	})
+}
+
+// forwarder decouples the watch from the sink through a bounded queue so a slow
+// sink shows up as backlog instead of silently stalling the watch.
+type forwarder struct {
+	encoder   *json.Encoder
+	queue     chan kube.Event
+	leading   atomic.Bool
+	watching  atomic.Bool
+	lastWrite atomic.Int64
+	sinkErr   atomic.Pointer[error]
+	// settings are the latest settings, only touched by run.
+	settings Settings
+}
+
+func newForwarder(out io.Writer) *forwarder {
+	f := &forwarder{
+		encoder: json.NewEncoder(out),
+		queue:   make(chan kube.Event, sinkQueueSize),
+	}
+	f.lastWrite.Store(time.Now().UnixNano())
+	return f
+}
+
+func (f *forwarder) run(ctx context.Context, opts Options) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	watchErr := make(chan error, 3)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := f.drain(runCtx); err != nil {
+			watchErr <- err
+		}
+	}()
+
+	if opts.AuditLog != "" {
+		go func() {
+			if err := audit.TailFile(runCtx, opts.AuditLog, opts.AuditFilter, f.enqueue(runCtx)); err != nil {
+				watchErr <- fmt.Errorf("tail audit log: %w", err)
+			}
+		}()
+	}
+
+	subscribe := func(settings Settings, source watch.Source) func() {
+		f.watching.Store(true)
+		hub := watch.NewHub(source)
+		// Readiness follows the watch connection, so a replica stuck reconnecting is not ready.
+		hub.OnStatus(func(_ string, status kube.WatchStatus) {
+			f.watching.Store(status.Connected)
+		})
+		return hub.Subscribe(settings.Namespace, nil, f.enqueue(runCtx), func(err error) {
+			watchErr <- err
+		})
+	}
+	// A reload outlives a leadership term; the next term starts with the latest settings.
+	current := f.settings
+	source, err := current.Source()
+	if err != nil {
+		return err
+	}
+	unsubscribe := subscribe(current, source)
+wait:
+	for {
+		select {
+		case err = <-watchErr:
+			break wait
+		case <-runCtx.Done():
+			break wait
+		case settings, ok := <-opts.Reload:
+			if !ok {
+				opts.Reload = nil
+				continue
+			}
+			if settings == current {
+				continue
+			}
+			source, sourceErr := settings.Source()
+			if sourceErr != nil {
+				fmt.Fprintf(os.Stderr, "reload config: %v, keeping the current settings\n", sourceErr)
+				continue
+			}
+			// Events that happen while the new watch starts up are not forwarded.
+			unsubscribe()
+			current = settings
+			f.settings = current
+			unsubscribe = subscribe(current, source)
+			fmt.Fprintf(os.Stderr, "config reloaded, watching %s\n", describeSettings(current))
+		}
+	}
+	unsubscribe()
+	f.watching.Store(false)
+	cancel()
+	<-done
+
+	return err
+}
+
+// enqueue returns a handler that queues events for the sink until ctx is done.
+func (f *forwarder) enqueue(ctx context.Context) func(kube.Event) {
+	return func(event kube.Event) {
+		select {
+		case f.queue <- event:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// drain writes queued events to the sink until ctx is done. A write error stops it and
+// is returned, and kept for /healthz: events written after a failed one could be cut off
+// or lost, so the forwarder exits rather than carry on.
+func (f *forwarder) drain(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-f.queue:
+			if err := f.encoder.Encode(event); err != nil {
+				err = fmt.Errorf("write event to sink: %w", err)
+				f.sinkErr.Store(&err)
+				return err
+			}
+			f.lastWrite.Store(time.Now().UnixNano())
+		}
+	}
+}
+
+// lastSinkError returns the error of the last failed sink write, or nil.
+func (f *forwarder) lastSinkError() error {
+	if err := f.sinkErr.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// backlog returns the number of events waiting to be written to the sink.
+func (f *forwarder) backlog() int {
+	return len(f.queue)
+}
+
+// stalled reports whether events are queued but nothing was written for sinkStallTimeout.
+func (f *forwarder) stalled() bool {
+	if f.backlog() == 0 {
+		return false
+	}
+	return time.Since(time.Unix(0, f.lastWrite.Load())) > sinkStallTimeout
+}
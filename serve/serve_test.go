@@ -0,0 +1,67 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a0xAi/kubeve/audit"
+	"github.com/a0xAi/kubeve/kube"
+)
+
+// failingWriter fails every write, like a closed pipe.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestSinkWriteErrorStopsForwardingAndFailsHealthz(t *testing.T) {
+	f := newForwarder(failingWriter{})
+	f.queue <- kube.Event{Name: "api-0", Reason: "BackOff"}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := f.drain(ctx)
+	if err == nil || !strings.Contains(err.Error(), "broken pipe") {
+		t.Fatalf("want the write error, got %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("drain kept running after the write failed")
+	}
+
+	rec := httptest.NewRecorder()
+	healthHandler(f).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "broken pipe") {
+		t.Fatalf("want /healthz to report the sink error, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestStandbyDoesNotAcknowledgeAuditBatches(t *testing.T) {
+	f := newForwarder(io.Discard)
+	batch := `{"items":[{"stage":"ResponseComplete","verb":"delete","user":{"username":"alice"},
+		"objectRef":{"resource":"secrets","namespace":"shop","name":"db"}}]}`
+	post := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		auditHandler(audit.NewFilter("", true), f).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodPost, "/audit", strings.NewReader(batch)))
+		return rec
+	}
+
+	if rec := post(); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("a standby acknowledged the batch with %d %q", rec.Code, rec.Body.String())
+	}
+	if f.backlog() != 0 {
+		t.Fatalf("a standby queued %d audit entries", f.backlog())
+	}
+
+	f.leading.Store(true)
+	if rec := post(); rec.Code != http.StatusOK || f.backlog() != 1 {
+		t.Fatalf("the leader answered %d with %d queued entries, want 200 and 1", rec.Code, f.backlog())
+	}
+}
@@ -0,0 +1,374 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube/drilldown"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	rowActionLogs     = "logs"
+	rowActionDescribe = "describe"
+	rowActionFilter   = "filter"
+)
+
+// rowAction is an entry of the row actions popup, run by its key or by enter.
+type rowAction struct {
+	name  string
+	key   rune
+	label string
+}
+
+// rowActionsFor returns the actions offered for the object of a table row: its logs
+// for pods and workloads, its drill-down and a filter to its events. Rows without an
+// object reference get none.
+func rowActionsFor(row tableRow) []rowAction {
+	kind, _, ok := splitResource(row.resource())
+	if !ok {
+		return nil
+	}
+	var actions []rowAction
+	if drilldown.HasLogs(kind) {
+		actions = append(actions, rowAction{name: rowActionLogs, key: 'l', label: "Logs"})
+	}
+	return append(actions,
+		rowAction{name: rowActionDescribe, key: 'd', label: "Describe"},
+		rowAction{name: rowActionFilter, key: 'f', label: "Filter to object"},
+	)
+}
+
+// rowActionMenu is a small popup under a table row with the actions for its object.
+// Like the preview it is drawn over the table and never takes focus; while it is shown
+// the table's input handler passes every key to handle.
+type rowActionMenu struct {
+	view *tview.TextView
+	// row is the table row the menu belongs to, 0 while hidden.
+	row      int
+	event    tableRow
+	actions  []rowAction
+	selected int
+}
+
+func newRowActionMenu() *rowActionMenu {
+	view := tview.NewTextView()
+	view.SetDynamicColors(true)
+	view.SetBorder(true)
+	view.SetTitle(" Actions ")
+	return &rowActionMenu{view: view}
+}
+
+// show opens the menu for table row row, which shows event row r, and reports whether
+// the row has any actions.
+func (m *rowActionMenu) show(row int, r tableRow) bool {
+	m.actions = rowActionsFor(r)
+	if len(m.actions) == 0 {
+		m.hide()
+		return false
+	}
+	m.row, m.event, m.selected = row, r, 0
+	m.render()
+	return true
+}
+
+func (m *rowActionMenu) hide() {
+	m.row, m.event, m.actions = 0, tableRow{}, nil
+}
+
+func (m *rowActionMenu) visible() bool {
+	return m.row > 0
+}
+
+func (m *rowActionMenu) render() {
+	lines := make([]string, 0, len(m.actions))
+	for i, action := range m.actions {
+		line := fmt.Sprintf("[blue]%c[white]  %s", action.key, action.label)
+		if i == m.selected {
+			line = fmt.Sprintf("[black:yellow]%c  %s[-:-]", action.key, action.label)
+		}
+		lines = append(lines, line)
+	}
+	m.view.SetText(strings.Join(lines, "\n"))
+}
+
+// handle applies a key to the open menu. It returns the action to run, if the key
+// chose one; any key that is not an action or a move closes the menu.
+func (m *rowActionMenu) handle(event *tcell.EventKey) (rowAction, bool) {
+	switch {
+	case event.Key() == tcell.KeyUp || event.Rune() == 'k':
+		m.selected = (m.selected + len(m.actions) - 1) % len(m.actions)
+		m.render()
+		return rowAction{}, false
+	case event.Key() == tcell.KeyDown || event.Rune() == 'j':
+		m.selected = (m.selected + 1) % len(m.actions)
+		m.render()
+		return rowAction{}, false
+	case event.Key() == tcell.KeyEnter:
+		action := m.actions[m.selected]
+		m.hide()
+		return action, true
+	}
+	for _, action := range m.actions {
+		if event.Key() == tcell.KeyRune && event.Rune() == action.key {
+			m.hide()
+			return action, true
+		}
+	}
+	m.hide()
+	return rowAction{}, false
+}
+
+// draw places the menu under its row like the preview.
+func (m *rowActionMenu) draw(screen tcell.Screen, table *tview.Table) {
+	if m.row <= 0 {
+		return
+	}
+	width := len(" Actions ") + 2
+	for _, action := range m.actions {
+		width = max(width, len(action.label)+7)
+	}
+	height := len(m.actions) + 2
+	x, y, ok := placeUnderRow(table, m.row, height)
+	if !ok {
+		return
+	}
+	m.view.SetRect(x, y, width, height)
+	m.view.Draw(screen)
+}
+
+// LogsMode selects what LogsModal shows.
+type LogsMode int
+
+const (
+	// LogsRecent shows the recent logs.
+	LogsRecent LogsMode = iota
+	// LogsPrevious shows the logs of the last run of a restarted container.
+	LogsPrevious
+	// LogsFollow streams the logs as they are written.
+	LogsFollow
+)
+
+// followedLogLines is how many lines the logs view keeps while following, so a chatty
+// container does not grow it without bound.
+const followedLogLines = 5000
+
+// LogsModal shows the logs of a pod or of a workload's pod, read with kubeClient, as
+// selected by mode. p switches between the recent and the previous run's logs, f
+// starts and stops following; onClose is called when the modal closes, which also
+// stops a followed stream.
+func LogsModal(
+	app *tview.Application,
+	kubeClient *kubernetes.Clientset,
+	namespace, kind, name string,
+	mode LogsMode,
+	onClose func(),
+) {
+	view := tview.NewTextView()
+	view.SetDynamicColors(true)
+	view.SetBorder(true)
+	view.SetBackgroundColor(0x000000)
+	view.SetScrollable(true)
+
+	// footer holds the state and keys while following, since the text keeps growing.
+	footer := tview.NewTextView()
+	footer.SetDynamicColors(true)
+	footer.SetBackgroundColor(0x000000)
+
+	column := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(view, 0, 1, true).
+		AddItem(footer, 0, 0, false)
+
+	modalFlex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 1, 0, false).
+		AddItem(
+			tview.NewFlex().
+				AddItem(tview.NewBox(), 2, 0, false).
+				AddItem(column, 0, 1, true).
+				AddItem(tview.NewBox(), 2, 0, false),
+			0, 1, true,
+		).
+		AddItem(tview.NewBox(), 1, 0, false)
+
+	// Only touched from the UI goroutine.
+	closed := false
+	loading := false
+	var cancel context.CancelFunc = func() {}
+	// generation tells lines of a stream that was since restarted or stopped apart.
+	generation := 0
+	paused := false
+	var held []string
+	ended := ""
+
+	updateFooter := func() {
+		keys := "[gray]space to pause, r to restart, f to stop following, p for the previous run's logs, Esc/q to close.[white]"
+		state := "[green]Following[white]"
+		switch {
+		case ended != "":
+			keys = "[gray]r to restart, f for the recent logs, p for the previous run's logs, Esc/q to close.[white]"
+			state = "[yellow]" + escapeTViewText(ended) + "[white]"
+		case paused:
+			keys = strings.Replace(keys, "space to pause", "space to resume", 1)
+			state = fmt.Sprintf("[yellow]Paused, %d new lines[white]", len(held))
+		}
+		footer.SetText(state + " " + keys)
+	}
+
+	writeLines := func(lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		escaped := make([]string, len(lines))
+		for i, line := range lines {
+			escaped[i] = escapeTViewText(line)
+		}
+		fmt.Fprint(view, strings.Join(escaped, "\n")+"\n")
+		view.ScrollToEnd()
+	}
+
+	follow := func() {
+		generation++
+		gen := generation
+		paused, held, ended = false, nil, ""
+		view.SetTitle(fmt.Sprintf(" Following logs: %s/%s ", kind, name))
+		view.SetMaxLines(followedLogLines)
+		view.Clear()
+		column.ResizeItem(footer, 1, 0)
+		updateFooter()
+		cancel()
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+
+		// Lines are handed to the UI goroutine in batches, so a burst of output costs
+		// one redraw rather than one per line.
+		var mu sync.Mutex
+		var pending []string
+		scheduled := false
+		flush := func() {
+			mu.Lock()
+			lines := pending
+			pending, scheduled = nil, false
+			mu.Unlock()
+			if closed || gen != generation {
+				return
+			}
+			if paused {
+				held = append(held, lines...)
+				if len(held) > followedLogLines {
+					held = append([]string(nil), held[len(held)-followedLogLines:]...)
+				}
+				updateFooter()
+				return
+			}
+			writeLines(lines)
+		}
+		go func() {
+			message := drilldown.FollowLogs(ctx, kubeClient, namespace, kind, name, func(line string) {
+				mu.Lock()
+				pending = append(pending, line)
+				schedule := !scheduled
+				scheduled = true
+				mu.Unlock()
+				if schedule {
+					app.QueueUpdateDraw(flush)
+				}
+			})
+			app.QueueUpdateDraw(func() {
+				flush()
+				if closed || gen != generation || message == "" {
+					return
+				}
+				ended = message
+				updateFooter()
+			})
+		}()
+	}
+
+	load := func() {
+		if mode == LogsFollow {
+			follow()
+			return
+		}
+		if loading {
+			return
+		}
+		loading = true
+		generation++
+		column.ResizeItem(footer, 0, 0)
+		view.SetMaxLines(0)
+		title, other := "Logs", "previous run's logs"
+		if mode == LogsPrevious {
+			title, other = "Previous logs", "current logs"
+		}
+		view.SetTitle(fmt.Sprintf(" %s: %s/%s ", title, kind, name))
+		helpText := "\n\n[gray]r to reload, f to follow, p for the " + other + ", Esc/q to close. Use arrow keys to scroll.[white]"
+		view.SetText("[gray]Loading logs...[white]")
+		cancel()
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(context.Background(), 8*time.Second)
+		showPrevious := mode == LogsPrevious
+		go func() {
+			logs := drilldown.ObjectLogs(ctx, kubeClient, namespace, kind, name, showPrevious)
+			app.QueueUpdateDraw(func() {
+				if closed {
+					return
+				}
+				loading = false
+				view.SetText(escapeTViewText(logs) + helpText)
+				view.ScrollToEnd()
+			})
+		}()
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+			closed = true
+			cancel()
+			if onClose != nil {
+				onClose()
+			}
+			return nil
+		case event.Rune() == 'r':
+			load()
+			return nil
+		case event.Rune() == ' ' && mode == LogsFollow && ended == "":
+			paused = !paused
+			if !paused {
+				writeLines(held)
+				held = nil
+			}
+			updateFooter()
+			return nil
+		case loading:
+			return event
+		case event.Rune() == 'p':
+			if mode == LogsPrevious {
+				mode = LogsRecent
+			} else {
+				mode = LogsPrevious
+			}
+			load()
+			return nil
+		case event.Rune() == 'f':
+			if mode == LogsFollow {
+				mode = LogsRecent
+			} else {
+				mode = LogsFollow
+			}
+			load()
+			return nil
+		}
+		return event
+	})
+
+	app.SetRoot(modalFlex, true).SetFocus(view)
+	load()
+}
@@ -0,0 +1,28 @@
+package ui
+
+// clusterBandColors are the colors of the gutter that tells apart the rows of each
+// cluster in a stream of several contexts. They stay clear of the yellow, green, blue
+// and red the status and action columns use.
+var clusterBandColors = []string{"dodgerblue", "orchid", "darkorange", "mediumseagreen", "khaki", "lightcoral"}
+
+// clusterBands assigns each of contexts a gutter color in order, reusing colors when
+// there are more contexts than colors. A single context gets none.
+func clusterBands(contexts []string) map[string]string {
+	if len(contexts) < 2 {
+		return nil
+	}
+	bands := make(map[string]string, len(contexts))
+	for i, name := range contexts {
+		bands[name] = clusterBandColors[i%len(clusterBandColors)]
+	}
+	return bands
+}
+
+// bandGutter renders the gutter that starts a row of the cluster with color band, or ""
+// when the row has no band.
+func bandGutter(band string) string {
+	if band == "" {
+		return ""
+	}
+	return "[" + band + "]▌[-] "
+}
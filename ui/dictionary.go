@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/a0xAi/kubeve/config"
+)
+
+// builtinAnnotations cover the reasons newcomers most often ask about. Entries from the
+// config file are matched first, so teams can override them with their own runbooks.
+var builtinAnnotations = []config.Annotation{
+	{Reason: "FailedScheduling", Message: "Insufficient (cpu|memory)", Explanation: "No node has enough unreserved CPU/memory for the pod's requests. Lower the requests or add capacity."},
+	{Reason: "FailedScheduling", Message: "didn't match Pod's node affinity|node selector", Explanation: "The pod's nodeSelector/affinity excludes every node. Check labels on the nodes you expect it to land on."},
+	{Reason: "FailedScheduling", Message: "untolerated taint", Explanation: "Every candidate node has a taint the pod does not tolerate."},
+	{Reason: "FailedScheduling", Message: "unbound immediate PersistentVolumeClaims", Explanation: "A PVC used by the pod is not bound yet. Check the claim and its StorageClass provisioner."},
+	{Reason: "BackOff", Message: "restarting failed container", Explanation: "The container keeps exiting and the kubelet is waiting before restarting it (CrashLoopBackOff). Look at the previous container logs and exit code."},
+	{Reason: "BackOff", Message: "pulling image", Explanation: "Image pulls keep failing; the kubelet backs off between attempts."},
+	{Reason: "Failed", Message: "ErrImagePull|ImagePullBackOff|pull access denied|not found", Explanation: "The image cannot be pulled: wrong name/tag, missing registry credentials (imagePullSecrets) or registry unreachable."},
+	{Reason: "Unhealthy", Message: "Liveness probe failed", Explanation: "The liveness probe failed; the kubelet will restart the container once failureThreshold is reached."},
+	{Reason: "Unhealthy", Message: "Readiness probe failed", Explanation: "The readiness probe failed; the pod is removed from Service endpoints until it passes again."},
+	{Reason: "OOMKilling", Explanation: "The kernel killed a process for exceeding its memory limit. Raise the limit or reduce memory usage."},
+	{Reason: "FailedMount", Explanation: "A volume could not be mounted. Common causes are missing ConfigMaps/Secrets or a volume still attached to another node."},
+	{Reason: "FailedCreate", Message: "exceeded quota", Explanation: "A ResourceQuota in the namespace blocks creating more objects or requesting more resources."},
+	{Reason: "Evicted", Explanation: "The kubelet evicted the pod because the node ran low on memory, disk or PIDs."},
+	{Reason: "NodeNotReady", Explanation: "The node stopped reporting as Ready; pods on it may be rescheduled after the eviction timeout."},
+}
+
+type dictionaryEntry struct {
+	annotation config.Annotation
+	message    *regexp.Regexp
+}
+
+// eventDictionary maps event reasons and messages to human explanations.
+type eventDictionary struct {
+	entries []dictionaryEntry
+}
+
+// newEventDictionary compiles the configured annotations followed by the built-in ones.
+// Entries with an invalid message pattern are skipped and reported.
+func newEventDictionary(custom []config.Annotation) (*eventDictionary, []error) {
+	dict := &eventDictionary{}
+	var errs []error
+	for _, annotation := range append(append([]config.Annotation{}, custom...), builtinAnnotations...) {
+		entry := dictionaryEntry{annotation: annotation}
+		if annotation.Message != "" {
+			re, err := regexp.Compile("(?i)" + annotation.Message)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("dictionary entry %q: %w", annotation.Reason, err))
+				continue
+			}
+			entry.message = re
+		}
+		dict.entries = append(dict.entries, entry)
+	}
+	return dict, errs
+}
+
+// lookup returns the first annotation matching reason and message.
+func (d *eventDictionary) lookup(reason, message string) (config.Annotation, bool) {
+	if d == nil {
+		return config.Annotation{}, false
+	}
+	for _, entry := range d.entries {
+		if entry.annotation.Reason != "" && !strings.EqualFold(entry.annotation.Reason, reason) {
+			continue
+		}
+		if entry.message != nil && !entry.message.MatchString(message) {
+			continue
+		}
+		return entry.annotation, true
+	}
+	return config.Annotation{}, false
+}
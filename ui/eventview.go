@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// EventViewOptions configures an EventView.
+type EventViewOptions struct {
+	// Namespace is the scope to watch: "" for all namespaces, or a comma separated list.
+	Namespace string
+	// Columns selects the columns shown besides the message. When none is set, the
+	// view shows kubeve's default columns.
+	Columns ColumnOptions
+	// Wrap wraps long messages over several lines instead of cutting them.
+	Wrap bool
+	// Filter is the initial filter text.
+	Filter string
+	// OnSelect, if set, is called with the event of a row when Enter is pressed on it.
+	// In aggregate mode it gets the last event of the group.
+	OnSelect func(event kube.Event)
+}
+
+// EventView is kubeve's event table and filter as a tview primitive, for embedding the
+// event stream in other applications' layouts. Events come from an EventSource once Run
+// is called. "/" opens the filter; Enter applies it and Esc closes it unchanged.
+//
+// Apart from Run, its methods must be called from the application's goroutine, e.g.
+// in QueueUpdateDraw, like those of any tview primitive.
+type EventView struct {
+	*tview.Flex
+
+	source  kube.EventSource
+	opts    EventViewOptions
+	table   *tview.Table
+	filter  *tview.InputField
+	status  kube.WatchStatus
+	running bool
+
+	events       []streamEvent
+	eventIndex   map[string]int
+	visible      []tableRow
+	rowToVisible []int
+	tableWidth   int
+}
+
+// NewEventView returns a view of the events source streams, configured by opts.
+func NewEventView(source kube.EventSource, opts EventViewOptions) *EventView {
+	if opts.Columns == (ColumnOptions{}) || opts.Columns == (ColumnOptions{Aggregate: true}) {
+		opts.Columns.Timestamp = true
+		opts.Columns.Namespace = true
+		opts.Columns.Status = true
+		opts.Columns.Action = true
+		opts.Columns.Resource = true
+	}
+	v := &EventView{
+		source:     source,
+		opts:       opts,
+		table:      NewTable("Events"),
+		filter:     NewFilter(),
+		status:     kube.WatchStatus{Connected: true},
+		eventIndex: make(map[string]int),
+	}
+	v.filter.SetLabel("Filter: ")
+	v.filter.SetText(opts.Filter)
+	v.table.SetSelectedFunc(func(row, _ int) {
+		if v.opts.OnSelect == nil || row <= 0 || row-1 >= len(v.rowToVisible) {
+			return
+		}
+		if event, ok := v.visibleEvent(v.rowToVisible[row-1]); ok {
+			v.opts.OnSelect(event)
+		}
+	})
+	v.Flex = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.filter, 0, 0, false)
+	v.refresh()
+	return v
+}
+
+// Run watches the events of the view's namespace scope and shows them, redrawing app,
+// until ctx is done or the source gives up, in which case it returns why. Run it in its
+// own goroutine, once.
+func (v *EventView) Run(ctx context.Context, app *tview.Application) error {
+	app.QueueUpdateDraw(func() {
+		v.running = true
+		v.updateTitle()
+	})
+	return v.source.Watch(ctx, v.opts.Namespace, kube.EventHandlers{
+		OnAdd: func(event kube.Event) {
+			app.QueueUpdateDraw(func() { v.Add(event) })
+		},
+		OnUpdate: func(_, event kube.Event) {
+			app.QueueUpdateDraw(func() { v.Add(event) })
+		},
+		OnDelete: func(event kube.Event) {
+			app.QueueUpdateDraw(func() { v.Delete(event) })
+		},
+	}, func(status kube.WatchStatus) {
+		app.QueueUpdateDraw(func() {
+			v.status = status
+			v.updateTitle()
+		})
+	})
+}
+
+// Add shows event, or updates the row of an earlier occurrence with the same UID. Run
+// adds the events of the source; Add is for views fed without one.
+func (v *EventView) Add(event kube.Event) {
+	v.add(event)
+}
+
+// add shows events like Add, redrawing the table once for all of them.
+func (v *EventView) add(events ...kube.Event) {
+	added, updated := false, false
+	for _, event := range events {
+		if idx, ok := v.eventIndex[event.UID]; ok && event.UID != "" {
+			if v.events[idx].repeatedBy(event) {
+				v.events[idx].update(event)
+				updated = true
+			}
+			continue
+		}
+		v.events = append(v.events, newStreamEvent(event, false))
+		if event.UID != "" {
+			v.eventIndex[event.UID] = len(v.events) - 1
+		}
+		added = true
+	}
+	if !added {
+		if updated {
+			v.refresh()
+		}
+		return
+	}
+	row, _ := v.table.GetSelection()
+	following := row >= v.table.GetRowCount()-1
+	v.refresh()
+	if following && v.table.GetRowCount() > 1 {
+		if v.opts.Columns.Aggregate {
+			v.table.ScrollToBeginning()
+			v.table.Select(1, 0)
+		} else {
+			v.table.ScrollToEnd()
+			v.table.Select(v.table.GetRowCount()-1, 0)
+		}
+	}
+}
+
+// clear drops all events from the view, e.g. before a replay shows an earlier point.
+func (v *EventView) clear() {
+	v.events = nil
+	clear(v.eventIndex)
+	v.refresh()
+}
+
+// Delete greys out the row of an event the cluster deleted, usually because it expired.
+func (v *EventView) Delete(event kube.Event) {
+	idx, ok := v.eventIndex[event.UID]
+	if !ok || event.UID == "" {
+		return
+	}
+	v.events[idx].markDeleted()
+	v.refresh()
+}
+
+// SetFilter shows only the events whose row contains text.
+func (v *EventView) SetFilter(text string) {
+	v.filter.SetText(text)
+	v.opts.Filter = text
+	v.refresh()
+}
+
+// Selected returns the event of the selected row, if a row is selected.
+func (v *EventView) Selected() (kube.Event, bool) {
+	row, _ := v.table.GetSelection()
+	if row <= 0 || row-1 >= len(v.rowToVisible) {
+		return kube.Event{}, false
+	}
+	return v.visibleEvent(v.rowToVisible[row-1])
+}
+
+// visibleEvent returns the event behind visible row idx. Aggregated rows give the
+// group's last event.
+func (v *EventView) visibleEvent(idx int) (kube.Event, bool) {
+	if idx < 0 || idx >= len(v.visible) {
+		return kube.Event{}, false
+	}
+	return v.visible[idx].event, true
+}
+
+// InputHandler opens and closes the filter and passes other keys to the table.
+func (v *EventView) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return v.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		switch {
+		case v.filter.HasFocus() && event.Key() == tcell.KeyEnter:
+			v.SetFilter(v.filter.GetText())
+			v.closeFilter(setFocus)
+			return
+		case v.filter.HasFocus() && event.Key() == tcell.KeyEsc:
+			v.filter.SetText(v.opts.Filter)
+			v.closeFilter(setFocus)
+			return
+		case v.table.HasFocus() && event.Rune() == '/':
+			v.ResizeItem(v.filter, 1, 0)
+			setFocus(v.filter)
+			return
+		}
+		for _, item := range []tview.Primitive{v.table, v.filter} {
+			if item.HasFocus() {
+				item.InputHandler()(event, setFocus)
+				return
+			}
+		}
+	})
+}
+
+func (v *EventView) closeFilter(setFocus func(p tview.Primitive)) {
+	v.ResizeItem(v.filter, 0, 0)
+	setFocus(v.table)
+}
+
+// Draw re-renders wrapped rows when the width they were wrapped to changed.
+func (v *EventView) Draw(screen tcell.Screen) {
+	v.Flex.Draw(screen)
+	if _, _, width, _ := v.table.GetInnerRect(); v.opts.Wrap && width != v.tableWidth {
+		v.refresh()
+		v.Flex.Draw(screen)
+	}
+}
+
+func (v *EventView) refresh() {
+	if v.opts.Columns.Aggregate {
+		rows, _ := streamRows(v.events, v.opts.Namespace, "")
+		v.visible = filterRows(aggregateEvents(rows), v.opts.Filter)
+	} else {
+		v.visible, _ = streamRows(v.events, v.opts.Namespace, v.opts.Filter)
+	}
+	_, _, v.tableWidth, _ = v.table.GetInnerRect()
+	v.rowToVisible = renderTable(v.table, v.visible, "", v.opts.Columns, v.opts.Wrap, v.tableWidth)
+	// A narrower filter may leave the selection past the last row.
+	if row, _ := v.table.GetSelection(); row >= v.table.GetRowCount() && v.table.GetRowCount() > 1 {
+		v.table.Select(v.table.GetRowCount()-1, 0)
+	}
+	v.updateTitle()
+}
+
+func (v *EventView) updateTitle() {
+	title := fmt.Sprintf("[::b]Events (%d)", len(v.visible))
+	if v.opts.Filter != "" {
+		title += "[yellow] [Filter: " + escapeTViewText(v.opts.Filter) + "]"
+	}
+	switch {
+	case !v.running || v.status.Connected:
+	case v.status.Stopped:
+		title += fmt.Sprintf(" [red::b]Stream stopped (%s)[-:-:-]", v.status.State())
+	default:
+		title += fmt.Sprintf(" [red::b]Disconnected, reconnecting (attempt %d)[-:-:-]", v.status.Attempt)
+	}
+	v.table.SetTitle(title)
+}
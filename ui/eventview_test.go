@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// chanSource is an EventSource fed by a test.
+type chanSource chan kube.Event
+
+func (c chanSource) Watch(ctx context.Context, scope string, handlers kube.EventHandlers, onStatus func(kube.WatchStatus)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-c:
+			if kube.InNamespaces(scope, event.Namespace) {
+				handlers.OnAdd(event)
+			}
+		}
+	}
+}
+
+func TestEventViewEmbedsFilterableStream(t *testing.T) {
+	screen := newTestScreen()
+
+	source := make(chanSource)
+	selected := make(chan kube.Event, 1)
+	view := NewEventView(source, EventViewOptions{
+		Namespace: "shop",
+		OnSelect:  func(event kube.Event) { selected <- event },
+	})
+	// Embedded below a header of the host application.
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewTextView().SetText("host dashboard"), 1, 0, false).
+		AddItem(view, 0, 1, true)
+	app := tview.NewApplication().SetScreen(screen).SetRoot(layout, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = app.Run()
+	}()
+	go func() { _ = view.Run(ctx, app) }()
+	t.Cleanup(func() {
+		cancel()
+		app.Stop()
+		<-done
+	})
+
+	waitForScreen(t, screen, "Events (0)", func(string) bool { return true })
+	screen.resize(screenWidth, screenHeight)
+
+	now := time.Now()
+	source <- kube.Event{UID: "1", Time: now, Namespace: "shop", Kind: "Pod", Name: "api-0", Type: "Warning", Reason: "BackOff", Message: "back-off restarting api-0"}
+	source <- kube.Event{UID: "2", Time: now, Namespace: "billing", Kind: "Pod", Name: "ledger-0", Type: "Normal", Reason: "Pulled", Message: "image pulled for ledger-0"}
+	source <- kube.Event{UID: "3", Time: now, Namespace: "shop", Kind: "Pod", Name: "web-0", Type: "Normal", Reason: "Pulled", Message: "image pulled for web-0"}
+
+	waitForScreen(t, screen, "image pulled for web-0", func(text string) bool {
+		return strings.Contains(text, "host dashboard") && strings.Contains(text, "back-off restarting api-0") &&
+			!strings.Contains(text, "ledger-0")
+	})
+
+	screen.InjectKey(tcell.KeyRune, '/', tcell.ModNone)
+	for _, r := range "api-0" {
+		screen.InjectKey(tcell.KeyRune, r, tcell.ModNone)
+	}
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	waitForScreen(t, screen, "[Filter: api-0]", func(text string) bool {
+		return strings.Contains(text, "back-off restarting api-0") && !strings.Contains(text, "image pulled for web-0")
+	})
+
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	select {
+	case event := <-selected:
+		if event.Name != "api-0" {
+			t.Fatalf("Enter selected %s, want api-0", event.Name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Enter on a row did not select its event")
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/a0xAi/kubeve/kube"
 	"github.com/rivo/tview"
 )
 
+// headerRows is the height of the header when it is shown.
+const headerRows = 7
+
 // Header exposes the header flex and infoView for live updates.
 type Header struct {
 	Flex          *tview.Flex
@@ -22,22 +26,14 @@ func NewHeader(
 	clusterName, namespace, kubeRev string,
 	recentNamespaces []string,
 	disableLogo bool,
+	logo string,
+	keys *Keymap,
 ) *Header {
 	// Context/info pane
 	infoView := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignLeft)
-	namespaceText := namespace
-	if namespace == "" {
-		namespaceText = "All namespaces"
-	}
-	infoView.SetText(fmt.Sprintf(
-		"[yellow]Cluster:[-] %s\n"+
-			"[yellow]Namespace:[-] %s\n"+
-			"[yellow]K8s Rev:[-] %s\n"+
-			"[yellow]Kubeve Rev:[-] %s\n",
-		clusterName, namespaceText, kubeRev, "0.3.0",
-	))
+	infoView.SetText(infoText(clusterName, namespace, kubeRev, "0.3.0", streamStatusText(kube.WatchStatus{Connected: true})))
 
 	// Recent namespace shortcuts pane
 	recentNs := tview.NewTextView().
@@ -57,17 +53,20 @@ func NewHeader(
 	shortcuts := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignLeft)
-	shortcuts.SetText(ActionShortcuts())
+	shortcuts.SetText(keys.shortcutText(false, "  "))
 
 	shortcuts2 := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignLeft)
-	shortcuts2.SetText(ColumShortcuts())
+	shortcuts2.SetText(keys.shortcutText(true, "\t"))
 
 	logoView := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignRight)
-	logoView.SetText(LogoText())
+	if logo == "" {
+		logo = LogoText()
+	}
+	logoView.SetText(logo)
 
 	headerFlex := tview.NewFlex().
 		AddItem(infoView, 0, 2, false).
@@ -88,40 +87,6 @@ func NewHeader(
 	}
 }
 
-func ActionShortcuts() string {
-	items := []struct{ key, desc string }{
-		{":", "Command palette"},
-		{"<ctrl+t>", "Theme picker"},
-		{"</>", "Toggle filter"},
-		{"<w>", "Toggle wrap"},
-		{"<enter>", "Open drill-down"},
-		{"<ctrl+s>", "Toggle autoscroll"},
-		{"<ctrl+b>", "Go to last event"},
-		{"<ctrl+n>", "Change namespace"},
-		{"<↑↓>", "Scroll"},
-	}
-	var lines []string
-	for _, it := range items {
-		lines = append(lines, fmt.Sprintf("[blue]%s  [white]%s", it.key, it.desc))
-	}
-	return strings.Join(lines, "\n")
-}
-
-func ColumShortcuts() string {
-	items := []struct{ key, desc string }{
-		{"<shift+t>", "Toggle timestamp"},
-		{"<shift+s>", "Toggle status"},
-		{"<shift+a>", "Toggle action"},
-		{"<shift+r>", "Toggle resource"},
-		{"<shift+g>", "Toggle aggregate"},
-	}
-	var lines []string
-	for _, it := range items {
-		lines = append(lines, fmt.Sprintf("[blue]%s\t[white]%s", it.key, it.desc))
-	}
-	return strings.Join(lines, "\n")
-}
-
 func LogoText() string {
 	return `__        ___.                      
 |  | ____ _\_ |__   [red]_______  __ ____ 
@@ -130,3 +95,56 @@ func LogoText() string {
 [white]|__|_ \____/|___  /[red]\___  >\_/  \___ >
      [white]\/         \/     [red]\/          \/ `
 }
+
+// infoText renders the context info pane: cluster, namespace scope, versions and the
+// state of the event stream.
+func infoText(clusterText, namespace, kubeRev, kubeveRev, stream string) string {
+	namespaceText := namespace
+	if namespace == "" {
+		namespaceText = "All namespaces"
+	}
+	return fmt.Sprintf(
+		"[yellow]Cluster:[-] %s\n"+
+			"[yellow]Namespace:[-] %s\n"+
+			"[yellow]K8s Rev:[-] %s\n"+
+			"[yellow]Kubeve Rev:[-] %s\n"+
+			"[yellow]Stream:[-] %s\n",
+		clusterText, namespaceText, kubeRev, kubeveRev, stream,
+	)
+}
+
+// streamStatusText renders the state of the event stream, so a stale table is obvious:
+// green while connected, yellow while listing or reconnecting and red once the watch
+// stopped on rejected credentials or permissions.
+func streamStatusText(status kube.WatchStatus) string {
+	state := status.State()
+	switch state {
+	case kube.StateConnected:
+		return "[green]● " + state.String() + "[-]"
+	case kube.StateListing:
+		return "[yellow]● " + state.String() + "[-]"
+	case kube.StateReconnecting:
+		return fmt.Sprintf("[yellow]● %s (attempt %d)[-]", state, status.Attempt)
+	default:
+		return "[red]● " + state.String() + ", stopped[-]"
+	}
+}
+
+// clusterStatusText lists watched contexts for the info pane, each marked green while
+// its watch is connected, yellow while it reconnects and red once it stopped, and named
+// in the color of its rows' gutter.
+func clusterStatusText(contexts []string, statuses map[string]kube.WatchStatus) string {
+	bands := clusterBands(contexts)
+	marks := make([]string, 0, len(contexts))
+	for _, name := range contexts {
+		color := "[green]"
+		if status, ok := statuses[name]; ok && !status.Connected {
+			color = "[red]"
+			if status.State() == kube.StateReconnecting {
+				color = "[yellow]"
+			}
+		}
+		marks = append(marks, color+"●[-] ["+bands[name]+"]"+escapeTViewText(name)+"[-]")
+	}
+	return strings.Join(marks, " ")
+}
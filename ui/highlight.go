@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// changeHighlightDuration is how long the changed cells of an updated row stay
+// highlighted.
+const changeHighlightDuration = 2 * time.Second
+
+// changeHighlightColor is the background of a changed cell.
+const changeHighlightColor = tcell.ColorOlive
+
+// rowCells are the cells of a row whose changes are highlighted: the count of an
+// aggregated row, the type of a raw one, and the message.
+type rowCells struct {
+	status, message string
+}
+
+// changedCells tells which cells of a row changed recently, and until when they show it.
+type changedCells struct {
+	status, message bool
+	until           time.Time
+}
+
+// cellChanges remembers the cells of the rows that update in place, aggregated groups
+// and repeated events, to highlight what an update changed without moving the row.
+type cellChanges struct {
+	values  map[string]rowCells
+	changed map[string]changedCells
+}
+
+func newCellChanges() *cellChanges {
+	return &cellChanges{values: map[string]rowCells{}, changed: map[string]changedCells{}}
+}
+
+// note records the cells of the row with key and reports whether any of them differ
+// from the ones last noted for it. A row noted for the first time has not changed.
+func (c *cellChanges) note(key string, row tableRow, now time.Time) bool {
+	if key == "" {
+		return false
+	}
+	cells := rowCells{status: row.status(), message: row.message()}
+	last, seen := c.values[key]
+	c.values[key] = cells
+	if !seen || last == cells {
+		return false
+	}
+	changed := c.active(key, now)
+	changed.status = changed.status || last.status != cells.status
+	changed.message = changed.message || last.message != cells.message
+	changed.until = now.Add(changeHighlightDuration)
+	c.changed[key] = changed
+	return true
+}
+
+// active returns the cells of the row with key that are still highlighted at now.
+func (c *cellChanges) active(key string, now time.Time) changedCells {
+	changed, ok := c.changed[key]
+	if !ok || !now.Before(changed.until) {
+		delete(c.changed, key)
+		return changedCells{}
+	}
+	return changed
+}
+
+// reset forgets all rows, e.g. when rows start to stand for something else.
+func (c *cellChanges) reset() {
+	clear(c.values)
+	clear(c.changed)
+}
+
+// paintChanges highlights the changed cells of table row row, or clears the highlight.
+func paintChanges(table *tview.Table, row int, changed changedCells, opts ColumnOptions) {
+	paint := func(col int, on bool) {
+		cell := table.GetCell(row, col)
+		if on {
+			// The selected row would swap the colors and hide the highlight.
+			cell.SetBackgroundColor(changeHighlightColor).
+				SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(changeHighlightColor))
+		} else {
+			cell.SetTransparency(true).SetSelectedStyle(tcell.StyleDefault)
+		}
+	}
+	if col := statusColumn(opts); col >= 0 {
+		paint(col, changed.status)
+	}
+	paint(messageColumn(opts), changed.message)
+}
+
+// statusColumn returns the table column of the status, or count, cell, or -1 when it
+// is hidden.
+func statusColumn(opts ColumnOptions) int {
+	if !opts.Status {
+		return -1
+	}
+	col := 0
+	for _, shown := range []bool{opts.Timestamp, opts.Cluster, opts.Namespace} {
+		if shown {
+			col++
+		}
+	}
+	return col
+}
+
+// messageColumn returns the table column of the message, the last one.
+func messageColumn(opts ColumnOptions) int {
+	col := 0
+	for _, shown := range []bool{opts.Timestamp, opts.Cluster, opts.Namespace, opts.Status, opts.Action, opts.Resource} {
+		if shown {
+			col++
+		}
+	}
+	return col
+}
@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/internal/format"
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/a0xAi/kubeve/kube/drilldown"
+)
+
+// kubectlAge matches the LAST SEEN column of kubectl get events, e.g. 5m, 2m30s or <unknown>.
+var kubectlAge = regexp.MustCompile(`^(<unknown>|<invalid>|(\d+[smhdy])+)$`)
+
+// kubectlRepeat matches the repeat count kubectl events adds to LAST SEEN, e.g. the
+// "(x4 over 10m)" of "2m (x4 over 10m)".
+var kubectlRepeat = regexp.MustCompile(`\(x(\d+) over [^)]*\)`)
+
+// pastedEvent is the subset of fields shared by core/v1 events (kubectl -o json) and
+// kubeve's own JSON output.
+type pastedEvent struct {
+	Type          string `json:"type"`
+	Reason        string `json:"reason"`
+	Message       string `json:"message"`
+	Namespace     string `json:"namespace"`
+	Kind          string `json:"kind"`
+	Name          string `json:"name"`
+	Time          string `json:"time"`
+	LastTimestamp string `json:"lastTimestamp"`
+	EventTime     string `json:"eventTime"`
+	Metadata      struct {
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	InvolvedObject struct {
+		Kind      string `json:"kind"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"involvedObject"`
+	Regarding struct {
+		Kind      string `json:"kind"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"regarding"`
+	Note string `json:"note"`
+}
+
+// parsePastedEvent turns an event copied from elsewhere into a row for the details
+// view: a kubeve row, a kubectl get events line or a JSON event. namespace is used when
+// the text does not name one. Ages such as 5m are counted back from now.
+func parsePastedEvent(raw string, namespace string, now time.Time) (tableRow, error) {
+	text := strings.TrimSpace(raw)
+	if text == "" {
+		return tableRow{}, errors.New("paste an event line or JSON")
+	}
+	if strings.HasPrefix(text, "{") {
+		return parseEventJSON(text, namespace)
+	}
+	if parts := strings.SplitN(text, "│", 6); len(parts) == 6 {
+		return parseKubeveRow(parts), nil
+	}
+	return parseKubectlEventLine(text, namespace, now)
+}
+
+// parseKubeveRow reads the columns of a row as kubeve prints it: time, resource, type,
+// reason, namespace, written as context/namespace for one of several contexts, and
+// message.
+func parseKubeveRow(parts []string) tableRow {
+	field := func(i int) string {
+		return strings.TrimSpace(parts[i])
+	}
+	event := kube.Event{
+		Type:    field(2),
+		Reason:  field(3),
+		Message: field(5),
+	}
+	event.Time, _ = format.ParseTimestamp(field(0))
+	event.Kind, event.Name, _ = strings.Cut(field(1), "/")
+	event.Namespace = field(4)
+	if i := strings.LastIndex(event.Namespace, "/"); i >= 0 {
+		// Namespaces cannot contain a slash, context names can.
+		event.Cluster, event.Namespace = event.Namespace[:i], event.Namespace[i+1:]
+	}
+	return tableRow{event: event}
+}
+
+func parseEventJSON(text string, namespace string) (tableRow, error) {
+	var ev pastedEvent
+	if err := json.Unmarshal([]byte(text), &ev); err != nil {
+		return tableRow{}, fmt.Errorf("invalid event JSON: %w", err)
+	}
+	kind, name, ns := ev.InvolvedObject.Kind, ev.InvolvedObject.Name, ev.InvolvedObject.Namespace
+	if name == "" {
+		kind, name, ns = ev.Regarding.Kind, ev.Regarding.Name, ev.Regarding.Namespace
+	}
+	if name == "" && ev.Kind != "Event" {
+		kind, name, ns = ev.Kind, ev.Name, ev.Namespace
+	}
+	if kind == "" || name == "" {
+		return tableRow{}, errors.New("event JSON has no involved object")
+	}
+	if ns == "" {
+		ns = ev.Metadata.Namespace
+	}
+	if ns == "" {
+		ns = namespace
+	}
+	message := ev.Message
+	if message == "" {
+		message = ev.Note
+	}
+	event := kube.Event{Kind: kind, Name: name, Namespace: ns, Type: ev.Type, Reason: ev.Reason, Message: message}
+	// Both formats write times as RFC 3339, event times with microseconds.
+	event.Time, _ = time.Parse(time.RFC3339, firstNonEmpty(ev.Time, ev.LastTimestamp, ev.EventTime))
+	return tableRow{event: event}, nil
+}
+
+// parseKubectlEventLine reads the columns of kubectl get events / kubectl events, with or
+// without the leading NAMESPACE column: [NAMESPACE] LAST SEEN TYPE REASON OBJECT MESSAGE.
+// The repeat count kubectl events puts in LAST SEEN becomes the event's count.
+func parseKubectlEventLine(text string, namespace string, now time.Time) (tableRow, error) {
+	fields := strings.Fields(text)
+	objectIdx := -1
+	for i, field := range fields {
+		if ref, err := drilldown.ParseObjectRef(field); err == nil && !strings.Contains(ref.Name, "/") && isKindToken(field) {
+			objectIdx = i
+			break
+		}
+	}
+	if objectIdx < 0 {
+		return tableRow{}, errors.New("no <kind>/<name> object found in the pasted line")
+	}
+	ref, _ := drilldown.ParseObjectRef(fields[objectIdx])
+
+	leadText := strings.Join(fields[:objectIdx], " ")
+	var count int
+	if repeat := kubectlRepeat.FindStringSubmatch(leadText); repeat != nil {
+		count, _ = strconv.Atoi(repeat[1])
+		leadText = strings.Replace(leadText, repeat[0], "", 1)
+	}
+	lead := strings.Fields(leadText)
+	var age, eventType, reason string
+	if len(lead) >= 4 && !kubectlAge.MatchString(lead[0]) && kubectlAge.MatchString(lead[1]) {
+		namespace = lead[0]
+		lead = lead[1:]
+	}
+	if len(lead) >= 3 {
+		age, eventType, reason = lead[len(lead)-3], lead[len(lead)-2], lead[len(lead)-1]
+	} else if len(lead) == 2 {
+		eventType, reason = lead[0], lead[1]
+	}
+	event := kube.Event{
+		Kind:      ref.Kind,
+		Name:      ref.Name,
+		Namespace: namespace,
+		Type:      eventType,
+		Reason:    reason,
+		Message:   strings.Join(fields[objectIdx+1:], " "),
+		Count:     int32(count),
+	}
+	if elapsed, ok := parseKubectlAge(age); ok {
+		event.Time = now.Add(-elapsed)
+	}
+	return tableRow{event: event}, nil
+}
+
+// parseKubectlAge reads an age such as 5m or 2d3h. <unknown> and <invalid> have none.
+func parseKubectlAge(age string) (time.Duration, bool) {
+	units := map[byte]time.Duration{
+		's': time.Second,
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'y': 365 * 24 * time.Hour,
+	}
+	if !kubectlAge.MatchString(age) || strings.HasPrefix(age, "<") {
+		return 0, false
+	}
+	var elapsed time.Duration
+	n := 0
+	for i := 0; i < len(age); i++ {
+		if c := age[i]; c >= '0' && c <= '9' {
+			n = n*10 + int(c-'0')
+			continue
+		}
+		elapsed += time.Duration(n) * units[age[i]]
+		n = 0
+	}
+	return elapsed, true
+}
+
+// isKindToken rejects slashes inside messages or URLs, e.g. "http://..." or "a/b/c".
+func isKindToken(field string) bool {
+	kind, _, _ := strings.Cut(field, "/")
+	for _, r := range kind {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '.') {
+			return false
+		}
+	}
+	return kind != ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
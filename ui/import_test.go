@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+)
+
+func TestParsePastedEvent(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 30, 0, 0, time.UTC)
+	backOff := func(namespace string, at time.Time) kube.Event {
+		return kube.Event{
+			Time: at, Namespace: namespace, Kind: "Pod", Name: "api-0",
+			Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container api in pod api-0",
+		}
+	}
+	withCount := func(event kube.Event, count int32) kube.Event {
+		event.Count = count
+		return event
+	}
+	withCluster := func(event kube.Event, cluster string) kube.Event {
+		event.Cluster = cluster
+		return event
+	}
+
+	tests := []struct {
+		name  string
+		paste string
+		want  kube.Event
+	}{
+		{
+			name:  "kubectl get events",
+			paste: "5m          Warning   BackOff   pod/api-0   Back-off restarting failed container api in pod api-0",
+			want:  backOff("default", now.Add(-5*time.Minute)),
+		},
+		{
+			name:  "kubectl get events -A",
+			paste: "shop        2m30s       Warning   BackOff   pod/api-0   Back-off restarting failed container api in pod api-0",
+			want:  backOff("shop", now.Add(-150*time.Second)),
+		},
+		{
+			name:  "kubectl events with a repeat count",
+			paste: "2m (x4 over 10m)   Warning   BackOff   Pod/api-0   Back-off restarting failed container api in pod api-0",
+			want:  withCount(backOff("default", now.Add(-2*time.Minute)), 4),
+		},
+		{
+			name:  "kubectl events -A with a repeat count",
+			paste: "shop   2m (x4 over 10m)   Warning   BackOff   Pod/api-0   Back-off restarting failed container api in pod api-0",
+			want:  withCount(backOff("shop", now.Add(-2*time.Minute)), 4),
+		},
+		{
+			name: "core/v1 JSON",
+			paste: `{"kind":"Event","metadata":{"name":"api-0.1","namespace":"shop"},
+				"involvedObject":{"kind":"Pod","name":"api-0","namespace":"shop"},
+				"type":"Warning","reason":"BackOff","message":"Back-off restarting failed container api in pod api-0",
+				"lastTimestamp":"2025-05-01T12:10:00Z"}`,
+			want: backOff("shop", time.Date(2025, 5, 1, 12, 10, 0, 0, time.UTC)),
+		},
+		{
+			name: "events.k8s.io JSON",
+			paste: `{"kind":"Event","apiVersion":"events.k8s.io/v1","metadata":{"name":"api-0.1","namespace":"shop"},
+				"regarding":{"kind":"Pod","name":"api-0","namespace":"shop"},
+				"type":"Warning","reason":"BackOff","note":"Back-off restarting failed container api in pod api-0",
+				"eventTime":"2025-05-01T12:10:00.000000Z"}`,
+			want: backOff("shop", time.Date(2025, 5, 1, 12, 10, 0, 0, time.UTC)),
+		},
+		{
+			name:  "kubeve row",
+			paste: "2025-05-01T12:10:00Z │ Pod/api-0 │ Warning │ BackOff │ prod-eu/shop │ Back-off restarting failed container api in pod api-0",
+			want:  withCluster(backOff("shop", time.Date(2025, 5, 1, 12, 10, 0, 0, time.UTC)), "prod-eu"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row, err := parsePastedEvent(tt.paste, "default", now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := row.event
+			if !got.Time.Equal(tt.want.Time) {
+				t.Errorf("time %v, want %v", got.Time, tt.want.Time)
+			}
+			got.Time = tt.want.Time
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+
+	for _, paste := range []string{"", "no object in this line", `{"kind":"Event"}`} {
+		if _, err := parsePastedEvent(paste, "default", now); err == nil {
+			t.Errorf("%q parsed without an error", paste)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package ui
+
+import "time"
+
+const previousIncarnationMarker = "(previous incarnation) "
+
+// incarnationTracker follows which UID currently owns an object name, so events of an
+// object deleted and re-created under the same name can be told apart.
+type incarnationTracker struct {
+	latest map[string]incarnation
+}
+
+type incarnation struct {
+	uid  string
+	seen time.Time
+}
+
+func newIncarnationTracker() *incarnationTracker {
+	return &incarnationTracker{latest: make(map[string]incarnation)}
+}
+
+// observe records an event of key's object uid at ts. previous is true when the event
+// belongs to an older incarnation; replaced is the UID this event supersedes, if any.
+func (t *incarnationTracker) observe(key, uid string, ts time.Time) (previous bool, replaced string) {
+	if uid == "" {
+		return false, ""
+	}
+	current, ok := t.latest[key]
+	switch {
+	case !ok:
+		t.latest[key] = incarnation{uid: uid, seen: ts}
+	case current.uid == uid:
+		if ts.After(current.seen) {
+			t.latest[key] = incarnation{uid: uid, seen: ts}
+		}
+	case ts.Before(current.seen):
+		return true, ""
+	default:
+		t.latest[key] = incarnation{uid: uid, seen: ts}
+		return false, current.uid
+	}
+	return false, ""
+}
@@ -0,0 +1,335 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Actions that can be bound to keys under keys in the config file.
+const (
+	ActionCommandPalette = "command-palette"
+	ActionThemePicker    = "theme-picker"
+	ActionFilter         = "filter"
+	ActionWrap           = "wrap"
+	ActionPreview        = "preview"
+	ActionRowActions     = "row-actions"
+	ActionTriage         = "triage"
+	ActionNote           = "note"
+	ActionAutoscroll     = "autoscroll"
+	ActionLastEvent      = "last-event"
+	ActionNamespaces     = "namespaces"
+	ActionHeader         = "header"
+	ActionMute           = "mute-top-talker"
+	ActionQuit           = "quit"
+	ActionTimestamp      = "timestamp-column"
+	ActionStatus         = "status-column"
+	ActionAction         = "action-column"
+	ActionResource       = "resource-column"
+	ActionAggregate      = "aggregate"
+	ActionWarningsOnly   = "warnings-only"
+)
+
+// Bindings whose keys are fixed, listed so the keymap covers every key kubeve uses.
+const (
+	actionDrillDown       = "drill-down"
+	actionScroll          = "scroll"
+	actionTab             = "tab"
+	actionRecentNamespace = "recent-namespace"
+)
+
+// KeyBinding is an action and the keys that trigger it.
+type KeyBinding struct {
+	Action      string
+	Description string
+	Keys        []string
+	// Column bindings are listed with the column toggles in the header.
+	Column bool
+	// Fixed bindings cannot be changed in the config file.
+	Fixed bool
+	// Configured is set when the config file changed the keys.
+	Configured bool
+	// label replaces the keys in the header, for fixed bindings with many keys.
+	label string
+}
+
+var defaultBindings = []KeyBinding{
+	{Action: ActionCommandPalette, Description: "Command palette", Keys: []string{":"}},
+	{Action: ActionThemePicker, Description: "Theme picker", Keys: []string{"ctrl+t"}},
+	{Action: ActionFilter, Description: "Toggle filter", Keys: []string{"/"}},
+	{Action: ActionWrap, Description: "Toggle wrap", Keys: []string{"w"}},
+	{Action: actionDrillDown, Description: "Open drill-down", Keys: []string{"enter"}, Fixed: true},
+	{Action: ActionPreview, Description: "Preview row", Keys: []string{"p"}},
+	{Action: ActionRowActions, Description: "Row actions", Keys: []string{"a"}},
+	{Action: ActionTriage, Description: "Triage warnings", Keys: []string{"t"}},
+	{Action: ActionNote, Description: "Note on row", Keys: []string{"m"}},
+	{Action: ActionAutoscroll, Description: "Toggle autoscroll", Keys: []string{"ctrl+s"}},
+	{Action: ActionLastEvent, Description: "Go to last event", Keys: []string{"ctrl+b"}},
+	{Action: ActionNamespaces, Description: "Change namespace", Keys: []string{"ctrl+n"}},
+	{Action: ActionHeader, Description: "Toggle header", Keys: []string{"shift+h"}},
+	{Action: actionScroll, Description: "Scroll", Keys: []string{"up", "down", "pgup", "pgdn", "home", "end", "j", "k"}, Fixed: true, label: "↑↓"},
+	{Action: ActionMute, Description: "Mute top talker", Keys: []string{"shift+m"}},
+	{Action: actionTab, Description: "Switch tab", Keys: digitKeys("alt+", '1', '9'), Fixed: true, label: "alt+1..9"},
+	{Action: actionRecentNamespace, Description: "Recent namespace", Keys: digitKeys("", '0', '3'), Fixed: true, label: "0..3"},
+	{Action: ActionQuit, Description: "Quit", Keys: []string{"q", "ctrl+c"}},
+	{Action: ActionTimestamp, Description: "Toggle timestamp", Keys: []string{"shift+t"}, Column: true},
+	{Action: ActionStatus, Description: "Toggle status", Keys: []string{"shift+s"}, Column: true},
+	{Action: ActionAction, Description: "Toggle action", Keys: []string{"shift+a"}, Column: true},
+	{Action: ActionResource, Description: "Toggle resource", Keys: []string{"shift+r"}, Column: true},
+	{Action: ActionAggregate, Description: "Toggle aggregate", Keys: []string{"shift+g"}, Column: true},
+	{Action: ActionWarningsOnly, Description: "Toggle warnings only", Keys: []string{"ctrl+w"}, Column: true},
+}
+
+// multiplexerKeys are keys terminal multiplexers take by default, so kubeve never sees them.
+var multiplexerKeys = map[string]string{
+	"ctrl+b": "tmux prefix",
+	"ctrl+a": "GNU screen prefix",
+}
+
+func digitKeys(prefix string, from, to rune) []string {
+	var keys []string
+	for r := from; r <= to; r++ {
+		keys = append(keys, prefix+string(r))
+	}
+	return keys
+}
+
+// Keymap is the effective key bindings: the defaults with the config file's changes.
+type Keymap struct {
+	bindings []KeyBinding
+	specs    map[string][]keySpec
+}
+
+// KeyConflict is a key bound to more than one action, or taken by a terminal multiplexer.
+type KeyConflict struct {
+	Key     string
+	Actions []string
+	// Multiplexer names the multiplexer that takes the key by default, if any.
+	Multiplexer string
+}
+
+// NewKeymap applies overrides, which map action names to space separated keys, e.g.
+// "last-event": "ctrl+e" or "quit": "q ctrl+c", to the default bindings. An empty value
+// unbinds the action. Unknown actions and keys are reported and leave the default.
+func NewKeymap(overrides map[string]string) (*Keymap, []error) {
+	km := &Keymap{specs: make(map[string][]keySpec)}
+	var errs []error
+	known := make(map[string]bool, len(defaultBindings))
+	for _, binding := range defaultBindings {
+		known[binding.Action] = true
+		binding.Keys = append([]string(nil), binding.Keys...)
+		if value, ok := overrides[binding.Action]; ok && !binding.Fixed {
+			keys, err := canonicalKeys(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("keys.%s: %w", binding.Action, err))
+			} else {
+				binding.Keys, binding.Configured = keys, true
+			}
+		} else if ok {
+			errs = append(errs, fmt.Errorf("keys.%s: %s cannot be rebound", binding.Action, binding.Action))
+		}
+		for _, key := range binding.Keys {
+			spec, _ := parseKey(key)
+			km.specs[binding.Action] = append(km.specs[binding.Action], spec)
+		}
+		km.bindings = append(km.bindings, binding)
+	}
+	var unknown []string
+	for action := range overrides {
+		if !known[action] {
+			unknown = append(unknown, action)
+		}
+	}
+	sort.Strings(unknown)
+	for _, action := range unknown {
+		errs = append(errs, fmt.Errorf("keys.%s: unknown action", action))
+	}
+	return km, errs
+}
+
+// Bindings returns every binding in help order.
+func (km *Keymap) Bindings() []KeyBinding {
+	return append([]KeyBinding(nil), km.bindings...)
+}
+
+// Action returns the action event is bound to, or "" when it is not bound. Fixed
+// bindings are not reported; the views handling them match their keys themselves.
+func (km *Keymap) Action(event *tcell.EventKey) string {
+	for _, binding := range km.bindings {
+		if binding.Fixed {
+			continue
+		}
+		for _, spec := range km.specs[binding.Action] {
+			if spec.matches(event) {
+				return binding.Action
+			}
+		}
+	}
+	return ""
+}
+
+// Is reports whether event triggers action.
+func (km *Keymap) Is(event *tcell.EventKey, action string) bool {
+	return km.Action(event) == action
+}
+
+// Conflicts returns the keys bound to several actions and the bound keys a terminal
+// multiplexer takes, ordered by key. Where actions share a key, bindings that can be
+// changed win over fixed ones, and otherwise the first in Bindings order wins.
+func (km *Keymap) Conflicts() []KeyConflict {
+	actions := make(map[string][]string)
+	for _, binding := range km.bindings {
+		for _, key := range binding.Keys {
+			actions[key] = append(actions[key], binding.Action)
+		}
+	}
+	var conflicts []KeyConflict
+	for key, bound := range actions {
+		multiplexer := multiplexerKeys[key]
+		if len(bound) > 1 || multiplexer != "" {
+			conflicts = append(conflicts, KeyConflict{Key: key, Actions: bound, Multiplexer: multiplexer})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Key < conflicts[j].Key })
+	return conflicts
+}
+
+// shortcutText renders bindings as header lines, e.g. "[blue]<ctrl+t>  [white]Theme picker".
+func (km *Keymap) shortcutText(column bool, separator string) string {
+	var lines []string
+	for _, binding := range km.bindings {
+		if binding.Column != column || len(binding.Keys) == 0 {
+			continue
+		}
+		key := binding.label
+		if key == "" {
+			key = binding.Keys[0]
+		}
+		lines = append(lines, fmt.Sprintf("[blue]<%s>%s[white]%s", escapeTViewText(key), separator, binding.Description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// keySpec is a parsed key: a special key, or a rune with or without alt.
+type keySpec struct {
+	key  tcell.Key
+	r    rune
+	alt  bool
+	name string
+}
+
+var namedKeys = map[string]tcell.Key{
+	"enter":     tcell.KeyEnter,
+	"esc":       tcell.KeyEscape,
+	"tab":       tcell.KeyTab,
+	"backspace": tcell.KeyBackspace2,
+	"delete":    tcell.KeyDelete,
+	"insert":    tcell.KeyInsert,
+	"up":        tcell.KeyUp,
+	"down":      tcell.KeyDown,
+	"left":      tcell.KeyLeft,
+	"right":     tcell.KeyRight,
+	"pgup":      tcell.KeyPgUp,
+	"pgdn":      tcell.KeyPgDn,
+	"home":      tcell.KeyHome,
+	"end":       tcell.KeyEnd,
+	"f1":        tcell.KeyF1,
+	"f2":        tcell.KeyF2,
+	"f3":        tcell.KeyF3,
+	"f4":        tcell.KeyF4,
+	"f5":        tcell.KeyF5,
+	"f6":        tcell.KeyF6,
+	"f7":        tcell.KeyF7,
+	"f8":        tcell.KeyF8,
+	"f9":        tcell.KeyF9,
+	"f10":       tcell.KeyF10,
+	"f11":       tcell.KeyF11,
+	"f12":       tcell.KeyF12,
+}
+
+// parseKey parses keys such as "w", "shift+h", "ctrl+t", "alt+1", "space" or "enter".
+// Its name is the canonical spelling, so "H" and "Shift+H" both become "shift+h".
+func parseKey(text string) (keySpec, error) {
+	rest := strings.TrimSpace(text)
+	var ctrl, alt, shift bool
+	for {
+		lower := strings.ToLower(rest)
+		switch {
+		case strings.HasPrefix(lower, "ctrl+") && len(rest) > len("ctrl+"):
+			ctrl, rest = true, rest[len("ctrl+"):]
+			continue
+		case strings.HasPrefix(lower, "alt+") && len(rest) > len("alt+"):
+			alt, rest = true, rest[len("alt+"):]
+			continue
+		case strings.HasPrefix(lower, "shift+") && len(rest) > len("shift+"):
+			shift, rest = true, rest[len("shift+"):]
+			continue
+		}
+		break
+	}
+	if rest == "" {
+		return keySpec{}, fmt.Errorf("empty key")
+	}
+	prefix := ""
+	if alt {
+		prefix = "alt+"
+	}
+
+	if runes := []rune(rest); len(runes) == 1 || strings.EqualFold(rest, "space") {
+		r := ' '
+		if len(runes) == 1 {
+			r = runes[0]
+		}
+		if ctrl {
+			letter := unicode.ToLower(r)
+			if alt || letter < 'a' || letter > 'z' {
+				return keySpec{}, fmt.Errorf("%q: ctrl only combines with a letter", text)
+			}
+			return keySpec{key: tcell.KeyCtrlA + tcell.Key(letter-'a'), name: "ctrl+" + string(letter)}, nil
+		}
+		if shift && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		name := string(r)
+		switch {
+		case r >= 'A' && r <= 'Z':
+			name = "shift+" + string(r|0x20)
+		case r == ' ':
+			name = "space"
+		}
+		return keySpec{key: tcell.KeyRune, r: r, alt: alt, name: prefix + name}, nil
+	}
+
+	key, ok := namedKeys[strings.ToLower(rest)]
+	if !ok || ctrl || shift {
+		return keySpec{}, fmt.Errorf("unknown key %q", text)
+	}
+	return keySpec{key: key, alt: alt, name: prefix + strings.ToLower(rest)}, nil
+}
+
+// canonicalKeys parses space separated keys into their canonical names.
+func canonicalKeys(value string) ([]string, error) {
+	keys := []string{}
+	for _, field := range strings.Fields(value) {
+		spec, err := parseKey(field)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, spec.name)
+	}
+	return keys, nil
+}
+
+func (s keySpec) matches(event *tcell.EventKey) bool {
+	if s.key != tcell.KeyRune {
+		// Terminals report ctrl+letter as its own key, with or without the modifier.
+		if s.key >= tcell.KeyCtrlA && s.key <= tcell.KeyCtrlZ {
+			return event.Key() == s.key
+		}
+		return event.Key() == s.key && (event.Modifiers()&tcell.ModAlt != 0) == s.alt
+	}
+	return event.Key() == tcell.KeyRune && event.Rune() == s.r && (event.Modifiers()&tcell.ModAlt != 0) == s.alt
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestKeymapOverrides(t *testing.T) {
+	keys, errs := NewKeymap(map[string]string{
+		ActionLastEvent: "ctrl+e",
+		ActionWrap:      "W",
+		ActionTriage:    "p",
+		ActionHeader:    "",
+		"scroll":        "x",
+		"bogus":         "y",
+	})
+	if len(errs) != 2 {
+		t.Fatalf("want errors for the fixed and the unknown action, got %v", errs)
+	}
+
+	cases := []struct {
+		event *tcell.EventKey
+		want  string
+	}{
+		{tcell.NewEventKey(tcell.KeyCtrlE, 0, tcell.ModCtrl), ActionLastEvent},
+		{tcell.NewEventKey(tcell.KeyCtrlB, 0, tcell.ModCtrl), ""},
+		{tcell.NewEventKey(tcell.KeyRune, 'W', tcell.ModShift), ActionWrap},
+		{tcell.NewEventKey(tcell.KeyRune, 'w', tcell.ModNone), ""},
+		{tcell.NewEventKey(tcell.KeyRune, 'H', tcell.ModShift), ""},
+		{tcell.NewEventKey(tcell.KeyRune, 'p', tcell.ModNone), ActionPreview},
+		{tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModAlt), ""},
+		{tcell.NewEventKey(tcell.KeyCtrlC, 0, tcell.ModNone), ActionQuit},
+	}
+	for _, tc := range cases {
+		if got := keys.Action(tc.event); got != tc.want {
+			t.Errorf("%s: got action %q, want %q", tc.event.Name(), got, tc.want)
+		}
+	}
+
+	conflicts := keys.Conflicts()
+	if len(conflicts) != 1 || conflicts[0].Key != "p" {
+		t.Fatalf("want p to conflict, got %+v", conflicts)
+	}
+}
+
+func TestParseKeyCanonicalNames(t *testing.T) {
+	for text, want := range map[string]string{
+		"H":        "shift+h",
+		"Shift+h":  "shift+h",
+		"CTRL+T":   "ctrl+t",
+		"alt+1":    "alt+1",
+		"space":    "space",
+		"PgDn":     "pgdn",
+		"+":        "+",
+		"ctrl+alt": "",
+		"ctrl+1":   "",
+		"hyper+x":  "",
+	} {
+		spec, err := parseKey(text)
+		if want == "" {
+			if err == nil {
+				t.Errorf("%q: want an error, got %q", text, spec.name)
+			}
+			continue
+		}
+		if err != nil || spec.name != want {
+			t.Errorf("%q: got %q (%v), want %q", text, spec.name, err, want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/a0xAi/kubeve/kube/client"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// AuthModal explains a credential failure, shows the exec plugin's stderr and lets the user
+// retry once they have re-authenticated in another terminal.
+func AuthModal(
+	app *tview.Application,
+	frame *tview.Frame,
+	table *tview.Table,
+	authErr error,
+	onRetry func(),
+	onClose func(),
+) {
+	baseText := fmt.Sprintf(
+		"[red]Credentials rejected or unavailable[white]\n\n%s\n\n"+
+			"The event stream is paused. Re-authenticate in another terminal (e.g. [yellow]aws sso login[white]), then press [yellow]r[white] to retry.\n",
+		escapeTViewText(authErr.Error()),
+	)
+	helpText := "\n[gray]r to retry, Esc/q to dismiss.[white]"
+
+	view := tview.NewTextView()
+	view.SetDynamicColors(true)
+	view.SetWrap(true)
+	view.SetBorder(true)
+	view.SetTitle(" Authentication ")
+	view.SetBackgroundColor(0x000000)
+	view.SetScrollable(true)
+	view.SetText(baseText + "\n[gray]Running credential plugin...[white]" + helpText)
+
+	modalFlex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 0, 1, false).
+		AddItem(
+			tview.NewFlex().
+				AddItem(tview.NewBox(), 0, 1, false).
+				AddItem(view, 0, 3, true).
+				AddItem(tview.NewBox(), 0, 1, false),
+			0, 3, true,
+		).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	closeModal := func() {
+		cancel()
+		app.SetRoot(frame, true).SetFocus(table)
+		if onClose != nil {
+			onClose()
+		}
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+			closeModal()
+			return nil
+		case event.Rune() == 'r':
+			closeModal()
+			onRetry()
+			return nil
+		}
+		return event
+	})
+
+	app.SetRoot(modalFlex, true).SetFocus(view)
+
+	go func() {
+		command, output, isExec, err := client.ExecPluginOutput(ctx)
+		text := baseText
+		switch {
+		case !isExec:
+			text += "\n[gray]This context does not use an exec credential plugin.[white]"
+		case err == nil:
+			text += fmt.Sprintf("\n[green]%s now returns credentials, press r to resume.[white]", escapeTViewText(command))
+		default:
+			text += fmt.Sprintf("\n[yellow]%s failed:[white] %s", escapeTViewText(command), escapeTViewText(err.Error()))
+		}
+		if output != "" {
+			text += "\n\n[blue]Plugin output[white]\n" + escapeTViewText(output)
+		}
+		app.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			view.SetText(text + "\n" + helpText)
+		})
+	}()
+}
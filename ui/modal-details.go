@@ -6,7 +6,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/a0xAi/kubeve/analysis"
+	"github.com/a0xAi/kubeve/archive"
+	"github.com/a0xAi/kubeve/config"
 	"github.com/a0xAi/kubeve/kube"
+	"github.com/a0xAi/kubeve/kube/drilldown"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"k8s.io/client-go/kubernetes"
@@ -16,67 +20,72 @@ func DetailsModal(
 	app *tview.Application,
 	frame *tview.Frame,
 	table *tview.Table,
-	parts []string,
+	row tableRow,
 	kubeClient *kubernetes.Clientset,
+	eventArchive *archive.Archive,
+	annotation *config.Annotation,
+	note string,
+	analyzer analysis.Analyzer,
+	onClose func(),
 ) {
-	if len(parts) != 6 {
-		return
-	}
-
-	timeStr := strings.TrimSpace(parts[0])
-	resource := strings.TrimSpace(parts[1])
-	status := strings.TrimSpace(parts[2])
-	action := strings.TrimSpace(parts[3])
-	namespace := strings.TrimSpace(parts[4])
-	message := strings.TrimSpace(parts[5])
+	detailsModal(app, row, kubeClient, eventArchive, annotation, note, analyzer, nil, func() {
+		app.SetRoot(frame, true).SetFocus(table)
+		if onClose != nil {
+			onClose()
+		}
+	})
+}
 
-	defaultStatusColour := "[white]"
-	switch status {
-	case "Warning":
-		defaultStatusColour = "[yellow]"
-	}
+// maxRelatedListRows is how many related objects the drill-down lists without scrolling.
+const maxRelatedListRows = 8
 
-	defaultActionColour := "[white]"
-	switch action {
-	case "Created", "SuccessfulCreate", "Completed":
-		defaultActionColour = "[green]"
-	case "Started", "Pulled", "Pulling":
-		defaultActionColour = "[blue]"
-	case "Killing", "BackOff", "Unhealthy", "FailedToRetrieveImagePullSecret":
-		defaultActionColour = "[red]"
+// detailsModal shows the drill-down of row and calls back when it is closed. trail
+// holds the resources drilled through to reach it, empty for the drill-down of an event
+// row; those of related objects show the trail instead of an event.
+func detailsModal(
+	app *tview.Application,
+	row tableRow,
+	kubeClient *kubernetes.Clientset,
+	eventArchive *archive.Archive,
+	annotation *config.Annotation,
+	note string,
+	analyzer analysis.Analyzer,
+	trail []string,
+	back func(),
+) {
+	resource := row.resource()
+	baseDetail := eventDetailText(row, annotation, note)
+	title := " Event Drill-Down "
+	if len(trail) > 0 {
+		baseDetail = relatedDetailText(trail, row)
+		title = " Drill-Down "
 	}
 
-	baseDetail := fmt.Sprintf(
-		"[blue]Time:      [white]%s\n"+
-			"[blue]Resource:  [white]%s\n"+
-			"[blue]Namespace: [white]%s\n"+
-			"[blue]Status:    %s%s\n"+
-			"[blue]Action:    %s%s\n"+
-			"[blue]Message:   [white]%s\n",
-		escapeTViewText(timeStr),
-		escapeTViewText(resource),
-		escapeTViewText(namespace),
-		defaultStatusColour, escapeTViewText(status),
-		defaultActionColour, escapeTViewText(action),
-		escapeTViewText(message),
-	)
-
 	detailView := tview.NewTextView()
 	detailView.SetDynamicColors(true)
 	detailView.SetTextAlign(tview.AlignLeft)
 	detailView.SetBorder(true)
-	detailView.SetTitle(" Event Drill-Down ")
+	detailView.SetTitle(title)
 	detailView.SetBackgroundColor(0x000000)
 	detailView.SetScrollable(true)
 	detailView.SetText(baseDetail + "\n[gray]Loading resource drill-down...[white]")
 
+	// The related objects are listed below the text once loaded.
+	relatedList := tview.NewTable().SetSelectable(true, false)
+	relatedList.SetBorder(true)
+	relatedList.SetTitle(" Related Objects ")
+	relatedList.SetBackgroundColor(0x000000)
+	body := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(detailView, 0, 1, true)
+
 	modalFlex := tview.NewFlex().
 		SetDirection(tview.FlexRow).
 		AddItem(tview.NewBox(), 1, 0, false).
 		AddItem(
 			tview.NewFlex().
 				AddItem(tview.NewBox(), 2, 0, false).
-				AddItem(detailView, 0, 1, true).
+				AddItem(body, 0, 1, true).
 				AddItem(tview.NewBox(), 2, 0, false),
 			0, 1, true,
 		).
@@ -85,40 +94,392 @@ func DetailsModal(
 	app.SetRoot(modalFlex, true).SetFocus(detailView)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	analysisCtx, cancelAnalysis := context.WithCancel(context.Background())
 	closed := false
 
+	// Only touched from the UI goroutine.
+	var drilldownText string
+	var loaded drillDownText
+	var related []kube.RelatedObject
+	relatedPage := 0
+	var bundle *analysis.Bundle
+	analyzing := false
+	analysisText := ""
+	// Secret values stay redacted until s is pressed and confirmed with y.
+	kind, name, hasObject := splitResource(resource)
+	hasObject = hasObject && kubeClient != nil
+	isSecret := strings.EqualFold(kind, "secret")
+	confirmingReveal := false
+	secretText := ""
+
+	fragments := jsonFragments(row.detailMessage())
+	var keyHelp []string
+	if len(fragments) > 0 {
+		keyHelp = append(keyHelp, "v to view the message's JSON")
+	}
+	if hasObject {
+		keyHelp = append(keyHelp, "y to view the object's YAML")
+	}
+	hasLogs := hasObject && drilldown.HasLogs(kind)
+	if hasLogs {
+		keyHelp = append(keyHelp, "f to follow the logs", "p for the previous run's logs")
+	}
+	if analyzer != nil {
+		keyHelp = append(keyHelp, "a to analyze")
+	}
+	if isSecret {
+		keyHelp = append(keyHelp, "s to reveal the secret's values")
+	}
+	closeHelp := "Esc/q to close"
+	if len(trail) > 0 {
+		closeHelp = "Esc/q to go back"
+	}
+	keyHelp = append(keyHelp, closeHelp)
+	helpText := "\n\n[gray]" + strings.Join(keyHelp, ", ") + ". Use arrow keys to scroll.[white]"
+
+	setText := func() {
+		detailView.SetText(drilldownText + secretText + analysisText + helpText)
+	}
+
+	// turnPage shows another page of the related resources, keeping the scroll position.
+	turnPage := func(delta int) {
+		if loaded.pages() < 2 {
+			return
+		}
+		relatedPage = (relatedPage + delta + loaded.pages()) % loaded.pages()
+		row, column := detailView.GetScrollOffset()
+		drilldownText = baseDetail + loaded.page(relatedPage)
+		setText()
+		detailView.ScrollTo(row, column)
+	}
+
+	runAnalysis := func() {
+		if analyzer == nil || bundle == nil || analyzing {
+			return
+		}
+		analyzing = true
+		payload := *bundle
+		analysisText = "\n\n[green]Analysis[white]\n[gray]Running " + escapeTViewText(analyzer.Name()) + "...[white]"
+		setText()
+		go func() {
+			summary, err := analyzer.Analyze(analysisCtx, payload)
+			section := "\n\n[green]Analysis[white]\n"
+			switch {
+			case err != nil:
+				section += "[red]" + escapeTViewText(err.Error()) + "[white]"
+			case summary == "":
+				section += "[gray]The analyzer returned no output.[white]"
+			default:
+				section += escapeTViewText(summary)
+			}
+			app.QueueUpdateDraw(func() {
+				if closed {
+					return
+				}
+				analyzing = false
+				analysisText = section
+				setText()
+			})
+		}()
+	}
+
+	// revealSecret reads the secret's values after the user confirmed.
+	revealSecret := func() {
+		secretText = "\n\n[red::b]Secret Values[-:-:-]\n[gray]Loading...[white]"
+		setText()
+		namespace := row.event.Namespace
+		go func() {
+			// The drill-down's context may have expired by the time the user asks.
+			revealCtx, cancelReveal := context.WithTimeout(analysisCtx, 8*time.Second)
+			defer cancelReveal()
+			values := drilldown.SecretValues(revealCtx, kubeClient, namespace, name)
+			app.QueueUpdateDraw(func() {
+				if closed {
+					return
+				}
+				secretText = "\n\n[red::b]Secret Values[-:-:-]\n" + escapeTViewText(values)
+				setText()
+			})
+		}()
+	}
+
+	closeModal := func() {
+		closed = true
+		cancel()
+		cancelAnalysis()
+		back()
+	}
+
+	// openRelated drills into a related object, with Esc leading back here.
+	openRelated := func(object kube.RelatedObject) {
+		path := trail
+		if len(path) == 0 {
+			path = []string{resource}
+		}
+		path = append(path[:len(path):len(path)], object.Kind+"/"+object.Name)
+		relatedRow := tableRow{event: kube.Event{
+			Kind:      object.Kind,
+			Name:      object.Name,
+			Namespace: row.event.Namespace,
+			Cluster:   row.event.Cluster,
+		}}
+		detailsModal(app, relatedRow, kubeClient, eventArchive, nil, "", analyzer, path, func() {
+			app.SetRoot(modalFlex, true).SetFocus(relatedList)
+		})
+	}
+
+	relatedList.SetSelectedFunc(func(row, _ int) {
+		if row >= 0 && row < len(related) {
+			openRelated(related[row])
+		}
+	})
+	relatedList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyTab || event.Key() == tcell.KeyBacktab:
+			app.SetFocus(detailView)
+			return nil
+		case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+			closeModal()
+			return nil
+		}
+		return event
+	})
+
 	detailView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if confirmingReveal {
+			confirmingReveal = false
+			if event.Rune() == 'y' {
+				revealSecret()
+			} else {
+				secretText = ""
+				setText()
+			}
+			return nil
+		}
 		if event.Key() == tcell.KeyEsc || event.Rune() == 'q' {
-			closed = true
-			cancel()
-			app.SetRoot(frame, true).SetFocus(table)
+			closeModal()
+			return nil
+		}
+		if (event.Key() == tcell.KeyTab || event.Key() == tcell.KeyBacktab) && len(related) > 0 {
+			app.SetFocus(relatedList)
+			return nil
+		}
+		if event.Rune() == 'a' {
+			runAnalysis()
+			return nil
+		}
+		if event.Rune() == 's' && isSecret && drilldownText != "" && secretText == "" {
+			confirmingReveal = true
+			secretText = "\n\n[yellow]Show the values of Secret " + escapeTViewText(name) + " on screen? y to reveal, any other key to cancel.[white]"
+			setText()
+			detailView.ScrollToEnd()
+			return nil
+		}
+		if event.Rune() == ']' || event.Rune() == '[' {
+			delta := 1
+			if event.Rune() == '[' {
+				delta = -1
+			}
+			turnPage(delta)
+			return nil
+		}
+		if event.Rune() == 'y' && hasObject {
+			YAMLModal(app, kubeClient, row.event.Namespace, kind, name, func() {
+				app.SetRoot(modalFlex, true).SetFocus(detailView)
+			})
+			return nil
+		}
+		if (event.Rune() == 'f' || event.Rune() == 'p') && hasLogs {
+			mode := LogsFollow
+			if event.Rune() == 'p' {
+				mode = LogsPrevious
+			}
+			LogsModal(app, kubeClient, row.event.Namespace, kind, name, mode, func() {
+				app.SetRoot(modalFlex, true).SetFocus(detailView)
+			})
+			return nil
+		}
+		if event.Rune() == 'v' && len(fragments) > 0 {
+			JSONModal(app, fragments, func() {
+				app.SetRoot(modalFlex, true).SetFocus(detailView)
+			})
 			return nil
 		}
 		return event
 	})
 
-	kind, name, ok := splitResource(resource)
-	if !ok || kubeClient == nil {
-		detailView.SetText(baseDetail + "\n[yellow]Drill-down unavailable for this row.[white]")
+	if !hasObject {
+		unavailable := baseDetail + "\n[yellow]Drill-down unavailable for this row.[white]"
+		if len(fragments) > 0 {
+			unavailable += helpText
+		}
+		detailView.SetText(unavailable)
 		return
 	}
 
 	go func() {
-		drilldown := kube.GetResourceDrillDown(ctx, kubeClient, namespace, kind, name)
-		text := baseDetail +
-			"\n[green]Describe[white]\n" + escapeTViewText(drilldown.Describe) +
-			"\n\n[green]Related Resources[white]\n" + escapeTViewText(drilldown.Related) +
-			"\n\n[green]Recent Logs[white]\n" + escapeTViewText(drilldown.Logs) +
-			"\n\n[gray]Esc/q to close. Use arrow keys to scroll.[white]"
+		text, payload := loadDrillDown(ctx, kubeClient, eventArchive, row)
 		app.QueueUpdateDraw(func() {
 			if closed {
 				return
 			}
-			detailView.SetText(text)
+			loaded = text
+			drilldownText = baseDetail + text.page(0)
+			payload.Note = note
+			bundle = &payload
+			if text.pages() > 1 {
+				helpText = strings.Replace(helpText, closeHelp, "]/[ for more related resources, "+closeHelp, 1)
+			}
+			if len(text.objects) > 0 {
+				related = text.objects
+				for row, object := range related {
+					relatedList.SetCell(row, 0, tview.NewTableCell(escapeTViewText(object.Kind+"/"+object.Name)).SetExpansion(1))
+					relatedList.SetCell(row, 1, tview.NewTableCell(object.Relation).SetTextColor(tcell.ColorGray))
+				}
+				body.AddItem(relatedList, min(len(related), maxRelatedListRows)+2, 0, false)
+				helpText = strings.Replace(helpText, closeHelp, "Tab to pick a related object, Enter to open it, "+closeHelp, 1)
+			}
+			setText()
 		})
 	}()
 }
 
+// eventDetailText renders the summary of an event row shown above its drill-down.
+func eventDetailText(row tableRow, annotation *config.Annotation, note string) string {
+	status := row.status()
+	action := row.event.Reason
+
+	defaultStatusColour := "[white]"
+	switch status {
+	case "Warning":
+		defaultStatusColour = "[yellow]"
+	}
+
+	defaultActionColour := "[white]"
+	switch action {
+	case "Created", "SuccessfulCreate", "Completed":
+		defaultActionColour = "[green]"
+	case "Started", "Pulled", "Pulling":
+		defaultActionColour = "[blue]"
+	case "Killing", "BackOff", "Unhealthy", "FailedToRetrieveImagePullSecret":
+		defaultActionColour = "[red]"
+	}
+
+	detail := fmt.Sprintf("[blue]Time:      [white]%s\n", escapeTViewText(row.timeText()))
+	if row.event.Cluster != "" {
+		detail += "[blue]Cluster:   [white]" + escapeTViewText(row.event.Cluster) + "\n"
+	}
+	detail += fmt.Sprintf(
+		"[blue]Resource:  [white]%s\n"+
+			"[blue]Namespace: [white]%s\n"+
+			"[blue]Status:    %s%s\n"+
+			"[blue]Action:    %s%s\n"+
+			"[blue]Message:   [white]%s\n",
+		escapeTViewText(row.resource()),
+		escapeTViewText(row.event.Namespace),
+		defaultStatusColour, escapeTViewText(status),
+		defaultActionColour, escapeTViewText(action),
+		escapeTViewText(row.detailMessage()),
+	)
+	if note != "" {
+		detail += "[blue]Note:      [yellow]" + escapeTViewText(note) + "[white]\n"
+	}
+
+	if annotation != nil {
+		detail += "\n[green]Explanation[white]\n" + escapeTViewText(annotation.Explanation) + "\n"
+		if annotation.Runbook != "" {
+			detail += "[blue]Runbook:   [white]" + escapeTViewText(annotation.Runbook) + "\n"
+		}
+	}
+	return detail
+}
+
+// relatedDetailText renders the summary shown above the drill-down of a related object:
+// the trail of resources drilled through to reach it, e.g. "Pod/a › ReplicaSet/b".
+func relatedDetailText(trail []string, row tableRow) string {
+	detail := "[blue]Trail:     [white]" + escapeTViewText(strings.Join(trail, " › ")) + "\n"
+	if row.event.Cluster != "" {
+		detail += "[blue]Cluster:   [white]" + escapeTViewText(row.event.Cluster) + "\n"
+	}
+	return detail +
+		"[blue]Resource:  [white]" + escapeTViewText(row.resource()) + "\n" +
+		"[blue]Namespace: [white]" + escapeTViewText(row.event.Namespace) + "\n"
+}
+
+// drillDownText is a rendered drill-down whose related resources may span pages.
+type drillDownText struct {
+	head, tail string
+	related    []string
+	// objects are the related objects the drill-down can move on to.
+	objects []kube.RelatedObject
+}
+
+func (d drillDownText) pages() int {
+	return len(d.related)
+}
+
+// page renders the drill-down with page i of the related resources.
+func (d drillDownText) page(i int) string {
+	return d.head + "\n\n[green]Related Resources[white]\n" + escapeTViewText(d.related[i]) + d.tail
+}
+
+// loadDrillDown queries the cluster for the object of an event row and renders the
+// diagnosis, describe, related resources and logs sections. The row's event must name
+// its object's kind and name. When the object is gone and eventArchive is set, its newest
+// archived snapshot is appended.
+func loadDrillDown(ctx context.Context, kubeClient *kubernetes.Clientset, eventArchive *archive.Archive, row tableRow) (drillDownText, analysis.Bundle) {
+	namespace, kind, name := row.event.Namespace, row.event.Kind, row.event.Name
+	message := row.detailMessage()
+
+	inspected := drilldown.Get(ctx, kubeClient, namespace, kind, name)
+	if diagnosis := drilldown.DiagnoseMessage(ctx, kubeClient, namespace, kind, name, message); diagnosis != "" {
+		inspected.Diagnosis = strings.TrimSpace(inspected.Diagnosis + "\n\n" + diagnosis)
+	}
+	if drilldown.IsDNSFailure(message) || drilldown.IsDNSFailure(inspected.Logs) {
+		inspected.Diagnosis = strings.TrimSpace(inspected.Diagnosis + "\n\nDNS lookups are failing. Cluster DNS:\n" + drilldown.CoreDNSHealth(ctx, kubeClient))
+	}
+	text := ""
+	if inspected.Diagnosis != "" {
+		text += "\n[red::b]Diagnosis[-:-:-]\n" + escapeTViewText(inspected.Diagnosis) + "\n"
+	}
+	if inspected.Termination != "" {
+		text += "\n[red::b]Last Termination[-:-:-]\n" + escapeTViewText(inspected.Termination) + "\n"
+	}
+	text += "\n[green]Describe[white]\n" + escapeTViewText(inspected.Describe)
+	related := inspected.RelatedPages
+	if len(related) == 0 {
+		related = []string{inspected.Related}
+	}
+	tail := "\n\n[green]Recent Logs[white]\n" + escapeTViewText(inspected.Logs)
+	if eventArchive != nil && drilldown.ObjectGone(ctx, kubeClient, namespace, kind, name) {
+		tail += archivedSnapshotText(eventArchive, namespace, kind, name)
+	}
+	bundle := analysis.NewBundle(analysis.BundleEvent{
+		Time:      row.timeText(),
+		Resource:  row.resource(),
+		Namespace: namespace,
+		Type:      row.status(),
+		Reason:    row.event.Reason,
+		Message:   message,
+	}, inspected)
+	return drillDownText{head: text, tail: tail, related: related, objects: inspected.RelatedObjects}, bundle
+}
+
+// archivedSnapshotText renders the newest archived snapshot of an object that no longer
+// exists in the cluster, or "" when none was archived.
+func archivedSnapshotText(eventArchive *archive.Archive, namespace, kind, name string) string {
+	record, ok, err := eventArchive.LatestSnapshot(namespace, kind, name)
+	if err != nil || !ok {
+		return ""
+	}
+	manifest, err := drilldown.SnapshotYAML(record.Snapshot)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("\n\n[green]Archived Snapshot[white] [gray](object is gone; taken %s)[white]\n%s",
+		record.Time.Local().Format(time.DateTime), escapeTViewText(manifest))
+}
+
 func splitResource(resource string) (string, string, bool) {
 	parts := strings.SplitN(strings.TrimSpace(resource), "/", 2)
 	if len(parts) != 2 {
@@ -133,5 +494,5 @@ func splitResource(resource string) (string, string, bool) {
 }
 
 func escapeTViewText(text string) string {
-	return strings.ReplaceAll(text, "[", "[[")
+	return tview.Escape(text)
 }
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube/drilldown"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DNSModal shows the health of the cluster DNS deployment, suggested when events keep
+// reporting name resolution failures.
+func DNSModal(
+	app *tview.Application,
+	frame *tview.Frame,
+	table *tview.Table,
+	kubeClient *kubernetes.Clientset,
+	failures int,
+	onClose func(),
+) {
+	baseText := "[green]Cluster DNS[white]\n"
+	if failures > 0 {
+		baseText = fmt.Sprintf("[yellow]%d events reported DNS lookup failures recently.[white]\n\n", failures) + baseText
+	}
+	helpText := "\n\n[gray]r to refresh, Esc/q to close.[white]"
+
+	view := tview.NewTextView()
+	view.SetDynamicColors(true)
+	view.SetWrap(true)
+	view.SetBorder(true)
+	view.SetTitle(" DNS Check ")
+	view.SetBackgroundColor(0x000000)
+	view.SetScrollable(true)
+
+	modalFlex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 0, 1, false).
+		AddItem(
+			tview.NewFlex().
+				AddItem(tview.NewBox(), 0, 1, false).
+				AddItem(view, 0, 3, true).
+				AddItem(tview.NewBox(), 0, 1, false),
+			0, 3, true,
+		).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	load := func() {
+		view.SetText(baseText + "[gray]Checking CoreDNS...[white]" + helpText)
+		go func() {
+			reqCtx, reqCancel := context.WithTimeout(ctx, 8*time.Second)
+			health := drilldown.CoreDNSHealth(reqCtx, kubeClient)
+			reqCancel()
+			app.QueueUpdateDraw(func() {
+				if ctx.Err() != nil {
+					return
+				}
+				view.SetText(baseText + escapeTViewText(health) + helpText)
+			})
+		}()
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+			cancel()
+			app.SetRoot(frame, true).SetFocus(table)
+			if onClose != nil {
+				onClose()
+			}
+			return nil
+		case event.Rune() == 'r':
+			load()
+			return nil
+		}
+		return event
+	})
+
+	app.SetRoot(modalFlex, true).SetFocus(view)
+	load()
+}
@@ -0,0 +1,297 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// jsonValue is a parsed JSON value that keeps the order of object keys.
+type jsonValue struct {
+	// key is the object key the value is stored under, if any.
+	key    string
+	hasKey bool
+	// kind is '{' or '[' for containers, 's' for strings, 'n' for numbers, 'b' for
+	// booleans and '0' for null.
+	kind     byte
+	scalar   string
+	children []*jsonValue
+	folded   bool
+}
+
+func (v *jsonValue) container() bool {
+	return v.kind == '{' || v.kind == '['
+}
+
+// substantial reports whether the value is worth a viewer: a non-empty object, or an
+// array holding objects or arrays. Bracketed lists such as "[3]" in messages are not.
+func (v *jsonValue) substantial() bool {
+	if v.kind == '{' {
+		return len(v.children) > 0
+	}
+	for _, child := range v.children {
+		if child.container() {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonFragments returns the JSON objects and arrays embedded in a message, e.g. the
+// response body an admission webhook or CNI plugin error quotes. Messages that quote
+// JSON with escaped quotes are unescaped first.
+func jsonFragments(message string) []*jsonValue {
+	fragments := scanJSON(message)
+	if len(fragments) == 0 && strings.Contains(message, `\"`) {
+		fragments = scanJSON(strings.ReplaceAll(message, `\"`, `"`))
+	}
+	return fragments
+}
+
+func scanJSON(text string) []*jsonValue {
+	var fragments []*jsonValue
+	for i := 0; i < len(text); i++ {
+		if text[i] != '{' && text[i] != '[' {
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(text[i:]))
+		dec.UseNumber()
+		value, err := decodeJSON(dec)
+		if err != nil || !value.substantial() {
+			continue
+		}
+		fragments = append(fragments, value)
+		i += int(dec.InputOffset()) - 1
+	}
+	return fragments
+}
+
+func decodeJSON(dec *json.Decoder) (*jsonValue, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token := token.(type) {
+	case json.Delim:
+		value := &jsonValue{kind: byte(token)}
+		for dec.More() {
+			var key string
+			if value.kind == '{' {
+				keyToken, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ = keyToken.(string)
+			}
+			child, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			child.key, child.hasKey = key, value.kind == '{'
+			value.children = append(value.children, child)
+		}
+		if _, err := dec.Token(); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		return value, nil
+	case string:
+		return &jsonValue{kind: 's', scalar: token}, nil
+	case json.Number:
+		return &jsonValue{kind: 'n', scalar: token.String()}, nil
+	case bool:
+		return &jsonValue{kind: 'b', scalar: fmt.Sprint(token)}, nil
+	default:
+		return &jsonValue{kind: '0', scalar: "null"}, nil
+	}
+}
+
+// jsonLine renders the line a value starts on: its key, and its scalar or opening
+// bracket. Folded containers show their size instead of their content.
+func jsonLine(value *jsonValue, last bool) string {
+	line := ""
+	if value.hasKey {
+		line = "[blue]" + escapeTViewText(quoteJSON(value.key)) + "[-]: "
+	}
+	comma := ","
+	if last {
+		comma = ""
+	}
+	switch value.kind {
+	case '{', '[':
+		open, closing := string(value.kind), "}"
+		unit := "keys"
+		if value.kind == '[' {
+			closing, unit = "]", "items"
+		}
+		if !value.folded {
+			return line + escapeTViewText(open)
+		}
+		return line + escapeTViewText(open) + "[gray]…" + fmt.Sprintf(" %d %s", len(value.children), unit) + "[-]" + escapeTViewText(closing) + comma
+	case 's':
+		return line + "[green]" + escapeTViewText(quoteJSON(value.scalar)) + "[-]" + comma
+	case 'n':
+		return line + "[cyan]" + value.scalar + "[-]" + comma
+	case 'b':
+		return line + "[yellow]" + value.scalar + "[-]" + comma
+	default:
+		return line + "[gray]null[-]" + comma
+	}
+}
+
+func quoteJSON(text string) string {
+	quoted, _ := json.Marshal(text)
+	return string(quoted)
+}
+
+// jsonNodes renders value as tree nodes: its own line, and for unfolded containers the
+// closing bracket as a sibling, so the tree reads as indented JSON.
+func jsonNodes(value *jsonValue, last bool) []*tview.TreeNode {
+	node := tview.NewTreeNode(jsonLine(value, last)).SetReference(value)
+	if !value.container() {
+		return []*tview.TreeNode{node}
+	}
+	if value.folded {
+		return []*tview.TreeNode{node}
+	}
+	for i, child := range value.children {
+		for _, childNode := range jsonNodes(child, i == len(value.children)-1) {
+			node.AddChild(childNode)
+		}
+	}
+	closing := "}"
+	if value.kind == '[' {
+		closing = "]"
+	}
+	if !last {
+		closing += ","
+	}
+	return []*tview.TreeNode{node, tview.NewTreeNode(escapeTViewText(closing)).SetSelectable(false)}
+}
+
+func setFolded(value *jsonValue, folded bool) {
+	if value.container() {
+		value.folded = folded
+	}
+	for _, child := range value.children {
+		setFolded(child, folded)
+	}
+}
+
+// JSONModal shows the JSON fragments of an event message pretty-printed and colored.
+// Enter or space folds and unfolds the selected object or array, e and c unfold and
+// fold everything, and n and N step through the fragments. onClose is called when the
+// viewer is closed with Esc or q.
+func JSONModal(app *tview.Application, fragments []*jsonValue, onClose func()) {
+	if len(fragments) == 0 {
+		return
+	}
+	current := 0
+
+	tree := tview.NewTreeView()
+	tree.SetGraphics(false)
+	tree.SetTopLevel(1)
+	tree.SetBorder(true)
+	tree.SetBackgroundColor(0x000000)
+
+	render := func(selected *jsonValue) {
+		root := tview.NewTreeNode("")
+		for _, node := range jsonNodes(fragments[current], true) {
+			root.AddChild(node)
+		}
+		tree.SetRoot(root)
+		tree.SetCurrentNode(root.GetChildren()[0])
+		if selected != nil {
+			root.Walk(func(node, _ *tview.TreeNode) bool {
+				if node.GetReference() == selected {
+					tree.SetCurrentNode(node)
+					return false
+				}
+				return true
+			})
+		}
+		title := " JSON "
+		if len(fragments) > 1 {
+			title = fmt.Sprintf(" JSON %d/%d ", current+1, len(fragments))
+		}
+		tree.SetTitle(title)
+	}
+	render(nil)
+
+	help := tview.NewTextView().SetDynamicColors(true)
+	help.SetBackgroundColor(0x000000)
+	helpText := "[gray]Enter/space to fold, e to unfold all, c to fold all, Esc/q to close.[white]"
+	if len(fragments) > 1 {
+		helpText = "[gray]Enter/space to fold, e to unfold all, c to fold all, n/N for the next/previous fragment, Esc/q to close.[white]"
+	}
+	help.SetText(helpText)
+
+	modalFlex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 1, 0, false).
+		AddItem(
+			tview.NewFlex().
+				AddItem(tview.NewBox(), 2, 0, false).
+				AddItem(
+					tview.NewFlex().
+						SetDirection(tview.FlexRow).
+						AddItem(tree, 0, 1, true).
+						AddItem(help, 1, 0, false),
+					0, 1, true,
+				).
+				AddItem(tview.NewBox(), 2, 0, false),
+			0, 1, true,
+		).
+		AddItem(tview.NewBox(), 1, 0, false)
+
+	selectedValue := func() *jsonValue {
+		if node := tree.GetCurrentNode(); node != nil {
+			value, _ := node.GetReference().(*jsonValue)
+			return value
+		}
+		return nil
+	}
+	tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		if value, ok := node.GetReference().(*jsonValue); ok && value.container() {
+			value.folded = !value.folded
+			render(value)
+		}
+	})
+
+	tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+			if onClose != nil {
+				onClose()
+			}
+			return nil
+		case event.Rune() == 'e':
+			setFolded(fragments[current], false)
+			render(selectedValue())
+			return nil
+		case event.Rune() == 'c':
+			// The outermost bracket stays open so the top-level keys remain visible.
+			setFolded(fragments[current], true)
+			fragments[current].folded = false
+			render(nil)
+			return nil
+		case event.Rune() == 'n' && len(fragments) > 1:
+			current = (current + 1) % len(fragments)
+			render(nil)
+			return nil
+		case event.Rune() == 'N' && len(fragments) > 1:
+			current = (current + len(fragments) - 1) % len(fragments)
+			render(nil)
+			return nil
+		}
+		return event
+	})
+
+	app.SetRoot(modalFlex, true).SetFocus(tree)
+}
@@ -7,8 +7,22 @@ import (
 	"github.com/rivo/tview"
 )
 
-func NamespacesModal(app *tview.Application, frame tview.Primitive, table *tview.Table, namespaceList []string, updateNamespace func(string)) {
-	names := append([]string{}, namespaceList...)
+// NamespacesModal lets the user pick a namespace from namespaceList. Namespaces in
+// denied, whose events cannot be read, are listed last and greyed out, or left out
+// when hideDenied is set.
+func NamespacesModal(app *tview.Application, frame tview.Primitive, table *tview.Table, namespaceList []string, denied map[string]bool, hideDenied bool, updateNamespace func(string)) {
+	names := make([]string, 0, len(namespaceList))
+	var deniedNames []string
+	for _, ns := range namespaceList {
+		if denied[ns] {
+			deniedNames = append(deniedNames, ns)
+		} else {
+			names = append(names, ns)
+		}
+	}
+	if !hideDenied {
+		names = append(names, deniedNames...)
+	}
 	filtered := append([]string{}, names...)
 	selection := 0
 	filterText := ""
@@ -72,10 +86,15 @@ func NamespacesModal(app *tview.Application, frame tview.Primitive, table *tview
 			screen.SetContent(x, y+ofs+i, ' ', nil, tcell.StyleDefault.Background(borderBg))
 
 			fg := tcell.ColorWhite
+			label := filtered[row]
+			if denied[label] {
+				fg = tcell.ColorGray
+				label += " (no access to events)"
+			}
 			if row == selection {
 				fg = tcell.ColorYellow
 			}
-			tview.Print(screen, filtered[row], x+1, y+ofs+i, width-1, tview.AlignLeft, fg)
+			tview.Print(screen, label, x+1, y+ofs+i, width-1, tview.AlignLeft, fg)
 		}
 		// draw filter input at bottom
 		input.SetRect(x, y+listH, width, 1)
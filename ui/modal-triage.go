@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/a0xAi/kubeve/archive"
+	"github.com/a0xAi/kubeve/config"
+	"github.com/a0xAi/kubeve/internal/format"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TriageModal steps through the unreviewed warnings returned by pending one at a time,
+// oldest first. The drill-down of the next warning loads while the current one is read.
+// a acknowledges a warning, s snoozes it and n skips it until the queue is reopened.
+// clientFor returns the client of the cluster a warning's row came from.
+func TriageModal(
+	app *tview.Application,
+	frame *tview.Frame,
+	table *tview.Table,
+	clientFor func(row tableRow) *kubernetes.Clientset,
+	eventArchive *archive.Archive,
+	state *triageState,
+	pending func() []triageItem,
+	annotate func(row tableRow) *config.Annotation,
+	notes *sessionNotes,
+	onClose func(),
+) {
+	helpText := "\n\n[gray]a to acknowledge, s to snooze " + format.Duration(triageSnooze) + ", n to skip, Esc/q to close. Use arrow keys to scroll.[white]"
+
+	view := tview.NewTextView()
+	view.SetDynamicColors(true)
+	view.SetTextAlign(tview.AlignLeft)
+	view.SetBorder(true)
+	view.SetBackgroundColor(0x000000)
+	view.SetScrollable(true)
+
+	modalFlex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 1, 0, false).
+		AddItem(
+			tview.NewFlex().
+				AddItem(tview.NewBox(), 2, 0, false).
+				AddItem(view, 0, 1, true).
+				AddItem(tview.NewBox(), 2, 0, false),
+			0, 1, true,
+		).
+		AddItem(tview.NewBox(), 1, 0, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Only touched from the UI goroutine.
+	skipped := make(map[string]bool)
+	drilldowns := make(map[string]string)
+	loading := make(map[string]bool)
+	var current *triageItem
+	remaining := 0
+
+	render := func() {
+		if current == nil {
+			view.SetTitle(" Triage ")
+			view.SetText("[green]No unreviewed warnings.[white]" +
+				"\n\n[gray]Warnings that arrive later join the queue. Esc/q to close.[white]")
+			return
+		}
+		view.SetTitle(fmt.Sprintf(" Triage: %d left ", remaining))
+		drilldown, ok := drilldowns[current.fingerprint]
+		if !ok {
+			drilldown = "\n[gray]Loading resource drill-down...[white]"
+		}
+		view.SetText(eventDetailText(current.row, annotate(current.row), notes.get(current.fingerprint)) + drilldown + helpText)
+	}
+
+	preload := func(item triageItem) {
+		if loading[item.fingerprint] {
+			return
+		}
+		loading[item.fingerprint] = true
+		kubeClient := clientFor(item.row)
+		if _, _, ok := splitResource(item.row.resource()); !ok || kubeClient == nil {
+			drilldowns[item.fingerprint] = "\n[yellow]Drill-down unavailable for this row.[white]"
+			return
+		}
+		go func() {
+			reqCtx, reqCancel := context.WithTimeout(ctx, 8*time.Second)
+			loaded, _ := loadDrillDown(reqCtx, kubeClient, eventArchive, item.row)
+			reqCancel()
+			app.QueueUpdateDraw(func() {
+				if ctx.Err() != nil {
+					return
+				}
+				drilldowns[item.fingerprint] = loaded.page(0)
+				if current != nil && current.fingerprint == item.fingerprint {
+					render()
+				}
+			})
+		}()
+	}
+
+	advance := func() {
+		var queue []triageItem
+		for _, item := range pending() {
+			if !skipped[item.fingerprint] {
+				queue = append(queue, item)
+			}
+		}
+		current = nil
+		remaining = len(queue)
+		if len(queue) > 0 {
+			current = &queue[0]
+			preload(queue[0])
+		}
+		if len(queue) > 1 {
+			preload(queue[1])
+		}
+		render()
+		view.ScrollToBeginning()
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+			cancel()
+			app.SetRoot(frame, true).SetFocus(table)
+			if onClose != nil {
+				onClose()
+			}
+			return nil
+		case current == nil:
+			return event
+		case event.Rune() == 'a':
+			state.ack(current.fingerprint)
+			advance()
+			return nil
+		case event.Rune() == 's':
+			state.snooze(current.fingerprint, time.Now())
+			advance()
+			return nil
+		case event.Rune() == 'n':
+			skipped[current.fingerprint] = true
+			advance()
+			return nil
+		}
+		return event
+	})
+
+	app.SetRoot(modalFlex, true).SetFocus(view)
+	advance()
+}
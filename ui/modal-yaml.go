@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube/drilldown"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"k8s.io/client-go/kubernetes"
+)
+
+// yamlKeyLine splits a YAML line into its indentation and list marker, its key and the
+// rest. Keys may be quoted.
+var yamlKeyLine = regexp.MustCompile(`^(\s*(?:- )*)("[^"]*"|'[^']*'|[^\s#'"][^:#]*?):(\s.*|)$`)
+
+// highlightYAML colors YAML for a TextView with dynamic colors in the JSON viewer's
+// colors: keys blue, strings green, numbers cyan, booleans yellow, nulls and comments
+// grey. Block scalars (| and >) are strings to the end of their indentation.
+func highlightYAML(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	// blockIndent is the indentation a block scalar's lines exceed, -1 outside of one.
+	blockIndent := -1
+	for _, line := range lines {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if blockIndent >= 0 {
+			if strings.TrimSpace(line) == "" || indent > blockIndent {
+				out = append(out, "[green]"+escapeTViewText(line)+"[-]")
+				continue
+			}
+			blockIndent = -1
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			out = append(out, "[gray]"+escapeTViewText(line)+"[-]")
+			continue
+		}
+		prefix, value := "", line
+		if match := yamlKeyLine.FindStringSubmatch(line); match != nil {
+			prefix = escapeTViewText(match[1]) + "[blue]" + escapeTViewText(match[2]) + "[-]:"
+			value = match[3]
+		} else {
+			item := line[indent:]
+			for strings.HasPrefix(item, "- ") {
+				item = item[2:]
+			}
+			if cut := len(line) - len(item); cut > indent {
+				prefix, value = escapeTViewText(line[:cut-1]), " "+item
+			}
+		}
+		scalar := strings.TrimSpace(value)
+		if strings.HasPrefix(scalar, "|") || strings.HasPrefix(scalar, ">") {
+			blockIndent = indent
+			out = append(out, prefix+escapeTViewText(value))
+			continue
+		}
+		if scalar == "" {
+			out = append(out, prefix)
+			continue
+		}
+		out = append(out, prefix+strings.TrimSuffix(value, scalar)+yamlScalarColor(scalar)+escapeTViewText(scalar)+"[-]")
+	}
+	return strings.Join(out, "\n")
+}
+
+// yamlScalarColor returns the color tag for a plain or quoted YAML scalar.
+func yamlScalarColor(scalar string) string {
+	switch scalar {
+	case "true", "false":
+		return "[yellow]"
+	case "null", "~":
+		return "[gray]"
+	case "{}", "[]":
+		return "[white]"
+	}
+	if _, err := strconv.ParseFloat(scalar, 64); err == nil {
+		return "[cyan]"
+	}
+	return "[green]"
+}
+
+// YAMLModal shows the live manifest of an object as highlighted YAML, read with
+// kubeClient; onClose returns to the drill-down.
+func YAMLModal(
+	app *tview.Application,
+	kubeClient *kubernetes.Clientset,
+	namespace, kind, name string,
+	onClose func(),
+) {
+	helpText := "\n\n[gray]r to reload, Esc/q to close. Use arrow keys to scroll.[white]"
+
+	view := tview.NewTextView()
+	view.SetDynamicColors(true)
+	view.SetBorder(true)
+	view.SetTitle(fmt.Sprintf(" YAML: %s/%s ", kind, name))
+	view.SetBackgroundColor(0x000000)
+	view.SetScrollable(true)
+	view.SetWrap(false)
+
+	modalFlex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 1, 0, false).
+		AddItem(
+			tview.NewFlex().
+				AddItem(tview.NewBox(), 2, 0, false).
+				AddItem(view, 0, 1, true).
+				AddItem(tview.NewBox(), 2, 0, false),
+			0, 1, true,
+		).
+		AddItem(tview.NewBox(), 1, 0, false)
+
+	// Only touched from the UI goroutine.
+	closed := false
+	loading := false
+	var cancel context.CancelFunc = func() {}
+
+	load := func() {
+		if loading {
+			return
+		}
+		loading = true
+		view.SetText("[gray]Loading manifest...[white]")
+		cancel()
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(context.Background(), 8*time.Second)
+		go func() {
+			manifest, err := drilldown.ObjectYAML(ctx, kubeClient, namespace, kind, name)
+			app.QueueUpdateDraw(func() {
+				if closed {
+					return
+				}
+				loading = false
+				if err != nil {
+					view.SetText("[red]" + escapeTViewText("Failed to load the manifest: "+err.Error()) + "[white]" + helpText)
+					return
+				}
+				view.SetText(highlightYAML(manifest) + helpText)
+				view.ScrollToBeginning()
+			})
+		}()
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+			closed = true
+			cancel()
+			if onClose != nil {
+				onClose()
+			}
+			return nil
+		case event.Rune() == 'r':
+			load()
+			return nil
+		}
+		return event
+	})
+
+	app.SetRoot(modalFlex, true).SetFocus(view)
+	load()
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// noteMarker prefixes the message of a row whose event has a note.
+const noteMarker = "✎ "
+
+// maxNoteLength bounds a note, which is a remark for whoever looks next, not a write-up.
+const maxNoteLength = 200
+
+// sessionNotes holds the notes attached to events during the session. Like triage
+// state they are keyed by event fingerprint, so repeats of a noted event carry its
+// note. They are not saved.
+type sessionNotes struct {
+	notes map[string]string
+}
+
+func newSessionNotes() *sessionNotes {
+	return &sessionNotes{notes: make(map[string]string)}
+}
+
+// get returns the note of the events with fingerprint, or "".
+func (n *sessionNotes) get(fingerprint string) string {
+	return n.notes[fingerprint]
+}
+
+// set attaches text to the events with fingerprint; empty text removes their note.
+func (n *sessionNotes) set(fingerprint, text string) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		delete(n.notes, fingerprint)
+		return
+	}
+	n.notes[fingerprint] = text
+}
+
+// NoteModal edits the note of the event of row, starting from note. Enter saves
+// through onSave, with "" when the text was cleared; Esc leaves the note as it was.
+func NoteModal(
+	app *tview.Application,
+	frame *tview.Frame,
+	table *tview.Table,
+	row tableRow,
+	note string,
+	onSave func(text string),
+) {
+	input := tview.NewInputField()
+	input.SetLabel("Note: ")
+	input.SetText(note)
+	input.SetAcceptanceFunc(tview.InputFieldMaxLength(maxNoteLength))
+	input.SetFieldBackgroundColor(0x000000)
+	input.SetBackgroundColor(0x000000)
+
+	help := tview.NewTextView()
+	help.SetDynamicColors(true)
+	help.SetBackgroundColor(0x000000)
+	help.SetText("[gray]Enter to save, an empty note removes it, Esc to cancel. Notes last for this session.[white]")
+
+	box := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true).
+		AddItem(help, 1, 0, false)
+	box.SetBorder(true)
+	box.SetTitle(fmt.Sprintf(" Note: %s ", row.resource()))
+	box.SetBackgroundColor(0x000000)
+
+	modalFlex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 0, 1, false).
+		AddItem(
+			tview.NewFlex().
+				AddItem(tview.NewBox(), 0, 1, false).
+				AddItem(box, 0, 3, true).
+				AddItem(tview.NewBox(), 0, 1, false),
+			4, 0, true,
+		).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			onSave(input.GetText())
+		case tcell.KeyEsc:
+		default:
+			return
+		}
+		app.SetRoot(frame, true).SetFocus(table)
+	})
+
+	app.SetRoot(modalFlex, true).SetFocus(input)
+}
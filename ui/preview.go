@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	previewMaxWidth  = 100
+	previewMaxHeight = 12
+)
+
+// rowPreview is a transient popup with the full message and object reference of one
+// table row. It is drawn over the table after each frame and never takes focus.
+type rowPreview struct {
+	view *tview.TextView
+	// row is the table row being previewed, 0 while hidden.
+	row int
+}
+
+func newRowPreview() *rowPreview {
+	view := tview.NewTextView()
+	view.SetDynamicColors(true)
+	view.SetWrap(true)
+	view.SetWordWrap(true)
+	view.SetBorder(true)
+	view.SetTitle(" Preview ")
+	return &rowPreview{view: view}
+}
+
+// show previews table row row, which shows event row r.
+func (p *rowPreview) show(row int, r tableRow) {
+	p.row = row
+	p.view.SetText(previewText(r))
+	p.view.ScrollToBeginning()
+}
+
+// hide removes the popup and reports whether it was shown.
+func (p *rowPreview) hide() bool {
+	shown := p.row > 0
+	p.row = 0
+	return shown
+}
+
+func (p *rowPreview) visible() bool {
+	return p.row > 0
+}
+
+// draw places the popup under the previewed row, or above it when the row is near the
+// bottom of the table. Nothing is drawn while the row is scrolled out of view.
+func (p *rowPreview) draw(screen tcell.Screen, table *tview.Table) {
+	if p.row <= 0 {
+		return
+	}
+	_, _, width, height := table.GetInnerRect()
+	popupWidth := min(width-4, previewMaxWidth)
+	if popupWidth < 20 {
+		return
+	}
+	lines := 0
+	for _, line := range strings.Split(p.view.GetText(false), "\n") {
+		lines += max(1, len(tview.WordWrap(line, popupWidth-2)))
+	}
+	popupHeight := min(lines+2, previewMaxHeight, height)
+
+	popupX, popupY, ok := placeUnderRow(table, p.row, popupHeight)
+	if !ok {
+		return
+	}
+	p.view.SetRect(popupX, popupY, popupWidth, popupHeight)
+	p.view.Draw(screen)
+}
+
+// placeUnderRow returns where a popup of popupHeight rows goes for a table row: under
+// it, or above it when the row is near the bottom of the table. ok is false while the
+// row is scrolled out of view.
+func placeUnderRow(table *tview.Table, row, popupHeight int) (popupX, popupY int, ok bool) {
+	x, y, _, height := table.GetInnerRect()
+	rowOffset, _ := table.GetOffset()
+	// NewTable fixes the header row; the rows below it scroll.
+	rowY := y + row - rowOffset
+	if rowY < y+1 || rowY >= y+height {
+		return 0, 0, false
+	}
+	popupY = rowY + 1
+	if popupY+popupHeight > y+height {
+		popupY = max(y, rowY-popupHeight)
+	}
+	return x + 2, popupY, true
+}
+
+// isNavigationKey reports keys that move the table selection, which keep a preview open.
+func isNavigationKey(event *tcell.EventKey) bool {
+	switch event.Key() {
+	case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn, tcell.KeyHome, tcell.KeyEnd:
+		return true
+	case tcell.KeyRune:
+		return event.Rune() == 'j' || event.Rune() == 'k'
+	}
+	return false
+}
+
+func previewText(row tableRow) string {
+	object := escapeTViewText(row.resource())
+	if namespace := row.scope(); namespace != "" {
+		object += " [gray]in namespace[white] " + escapeTViewText(namespace)
+	}
+	status := "[white]"
+	if row.status() == "Warning" {
+		status = "[yellow]"
+	}
+	return "[blue]Object:  [white]" + object + "\n" +
+		"[blue]Event:   " + status + escapeTViewText(row.status()) + " [white]" + escapeTViewText(row.event.Reason) +
+		" [gray]at[white] " + row.timeText() + "\n" +
+		escapeTViewText(row.detailMessage())
+}
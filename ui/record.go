@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// castRecorder writes terminal output in the asciinema v2 format
+// (https://docs.asciinema.org/manual/asciicast/v2/): a JSON header line followed by
+// one [elapsed, code, data] line per chunk of output or resize.
+type castRecorder struct {
+	mu    sync.Mutex
+	file  *os.File
+	out   *bufio.Writer
+	start time.Time
+	err   error
+}
+
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+func newCastRecorder(path string, width, height int) (*castRecorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &castRecorder{file: file, out: bufio.NewWriter(file), start: time.Now()}
+	header, err := json.Marshal(castHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.start.Unix(),
+		Title:     "kubeve",
+		Env:       map[string]string{"TERM": os.Getenv("TERM"), "SHELL": os.Getenv("SHELL")},
+	})
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if _, err := r.out.Write(append(header, '\n')); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *castRecorder) output(data []byte) {
+	r.event("o", string(data))
+}
+
+func (r *castRecorder) resize(width, height int) {
+	r.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+func (r *castRecorder) event(code, data string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	line, err := json.Marshal([]any{time.Since(r.start).Seconds(), code, data})
+	if err != nil {
+		r.err = err
+		return
+	}
+	if _, err := r.out.Write(append(line, '\n')); err != nil {
+		r.err = err
+	}
+}
+
+func (r *castRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.out.Flush(); err != nil && r.err == nil {
+		r.err = err
+	}
+	if err := r.file.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
+
+// recordingTty passes everything through to the real terminal and copies output and
+// size changes to a recorder.
+type recordingTty struct {
+	tcell.Tty
+	recorder *castRecorder
+}
+
+func (t *recordingTty) Write(p []byte) (int, error) {
+	n, err := t.Tty.Write(p)
+	if n > 0 {
+		t.recorder.output(p[:n])
+	}
+	return n, err
+}
+
+func (t *recordingTty) NotifyResize(cb func()) {
+	if cb == nil {
+		t.Tty.NotifyResize(nil)
+		return
+	}
+	t.Tty.NotifyResize(func() {
+		if size, err := t.Tty.WindowSize(); err == nil {
+			t.recorder.resize(size.Width, size.Height)
+		}
+		cb()
+	})
+}
+
+// newRecordingScreen opens the controlling terminal and returns a screen whose output
+// is also written to an asciinema cast at path.
+func newRecordingScreen(path string) (tcell.Screen, *castRecorder, error) {
+	tty, err := openTty()
+	if err != nil {
+		return nil, nil, fmt.Errorf("open terminal: %w", err)
+	}
+	size, err := tty.WindowSize()
+	if err != nil {
+		_ = tty.Close()
+		return nil, nil, fmt.Errorf("terminal size: %w", err)
+	}
+	recorder, err := newCastRecorder(path, size.Width, size.Height)
+	if err != nil {
+		_ = tty.Close()
+		return nil, nil, err
+	}
+	screen, err := tcell.NewTerminfoScreenFromTty(&recordingTty{Tty: tty, recorder: recorder})
+	if err != nil {
+		_ = recorder.Close()
+		_ = tty.Close()
+		return nil, nil, err
+	}
+	return screen, recorder, nil
+}
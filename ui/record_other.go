@@ -0,0 +1,13 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos)
+
+package ui
+
+import (
+	"errors"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func openTty() (tcell.Tty, error) {
+	return nil, errors.New("session recording is not supported on this platform")
+}
@@ -0,0 +1,9 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos
+
+package ui
+
+import "github.com/gdamore/tcell/v2"
+
+func openTty() (tcell.Tty, error) {
+	return tcell.NewDevTty()
+}
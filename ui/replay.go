@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/internal/format"
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// replaySpeeds are the playback speeds of a replay, picked with the keys 1 to 3.
+var replaySpeeds = []int{1, 10, 60}
+
+// replayTick is how often a playing replay moves on.
+const replayTick = 100 * time.Millisecond
+
+// replayBarWidth is the number of cells of the timeline scrubber.
+const replayBarWidth = 50
+
+// ReplayOptions configures a replay of archived events.
+type ReplayOptions struct {
+	// Name says what is replayed, e.g. the context of the archive, in the title.
+	Name string
+	// Namespace is the scope to replay: "" for all namespaces, or a comma separated list.
+	Namespace string
+	// Speed is the playback speed to start with: 1, 10 or 60 times real time.
+	Speed int
+	// Screen replaces the terminal, e.g. with a tcell.SimulationScreen in tests.
+	Screen tcell.Screen
+}
+
+// replayTimeline plays events back on their own clock. position is the time of the
+// replayed stream; while playing it moves on by the wall time that passed times speed.
+type replayTimeline struct {
+	// events are sorted by time; those up to next have been played.
+	events     []kube.Event
+	next       int
+	start, end time.Time
+	position   time.Time
+	speed      int
+	playing    bool
+}
+
+func newReplayTimeline(events []kube.Event, speed int) *replayTimeline {
+	events = append([]kube.Event(nil), events...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	t := &replayTimeline{events: events, speed: replaySpeeds[0], playing: true}
+	for _, s := range replaySpeeds {
+		if s == speed {
+			t.speed = speed
+		}
+	}
+	if len(events) > 0 {
+		t.start, t.end = events[0].Time, events[len(events)-1].Time
+	}
+	t.position = t.start
+	return t
+}
+
+// advance moves the timeline on by elapsed wall time while it plays, and returns the
+// events it passed. Playback pauses at the end.
+func (t *replayTimeline) advance(elapsed time.Duration) []kube.Event {
+	if !t.playing {
+		return nil
+	}
+	t.position = t.position.Add(elapsed * time.Duration(t.speed))
+	if !t.position.Before(t.end) {
+		t.position = t.end
+		t.playing = false
+	}
+	from := t.next
+	for t.next < len(t.events) && !t.events[t.next].Time.After(t.position) {
+		t.next++
+	}
+	return t.events[from:t.next]
+}
+
+// seek moves the timeline to at, kept within its start and end, and returns every
+// event up to there, which is what the table shows at that point.
+func (t *replayTimeline) seek(at time.Time) []kube.Event {
+	if at.Before(t.start) {
+		at = t.start
+	}
+	if at.After(t.end) {
+		at = t.end
+	}
+	t.position = at
+	t.next = sort.Search(len(t.events), func(i int) bool { return t.events[i].Time.After(at) })
+	return t.events[:t.next]
+}
+
+// step is how far the scrubber moves per key press: one cell of the bar, at least a
+// second.
+func (t *replayTimeline) step() time.Duration {
+	return max(t.end.Sub(t.start)/replayBarWidth, time.Second)
+}
+
+// toggle plays or pauses the replay. Playing at the end starts over, which is reported
+// with the events to show from the start.
+func (t *replayTimeline) toggle() (restarted bool) {
+	if t.playing {
+		t.playing = false
+		return false
+	}
+	t.playing = true
+	return !t.position.Before(t.end) && t.end.After(t.start)
+}
+
+// status renders the play state, speed, position and scrubber of the timeline.
+func (t *replayTimeline) status() string {
+	state := "[green]▶ playing"
+	if !t.playing {
+		state = "[yellow]❚❚ paused"
+	}
+	played := replayBarWidth - 1
+	if span := t.end.Sub(t.start); span > 0 {
+		played = int(int64(replayBarWidth-1) * int64(t.position.Sub(t.start)) / int64(span))
+	}
+	bar := "[green]" + strings.Repeat("━", played) + "[white]●[gray]" + strings.Repeat("─", replayBarWidth-1-played)
+	return fmt.Sprintf("%s %dx[-] %s  [gray]%s[-] %s [gray]%s[-]  %d/%d events",
+		state, t.speed, format.Timestamp(t.position), format.Clock(t.start), bar, format.Clock(t.end),
+		t.next, len(t.events))
+}
+
+// StartReplay plays events, e.g. read from the local archive, back in the event table
+// at the pace they happened. Space plays and pauses, 1, 2 and 3 switch between 1x, 10x
+// and 60x speed, and the left and right arrows scrub along the timeline. "/" filters
+// the table as in the live view; q quits.
+func StartReplay(events []kube.Event, opts ReplayOptions) error {
+	app := tview.NewApplication()
+	if opts.Screen != nil {
+		app.SetScreen(opts.Screen)
+	}
+	timeline := newReplayTimeline(events, opts.Speed)
+	view := NewEventView(nil, EventViewOptions{Namespace: opts.Namespace})
+	status := tview.NewTextView().SetDynamicColors(true)
+	help := tview.NewTextView().SetDynamicColors(true).
+		SetText("[gray]space play/pause · 1/2/3 speed 1x/10x/60x · ←/→ scrub · / filter · q quit[-]")
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewTextView().SetText("Replay of "+opts.Name), 1, 0, false).
+		AddItem(view, 0, 1, true).
+		AddItem(status, 1, 0, false).
+		AddItem(help, 1, 0, false)
+
+	// Only touched from the UI goroutine.
+	last := time.Now()
+	show := func(shown []kube.Event) {
+		view.clear()
+		view.add(shown...)
+	}
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if view.filter.HasFocus() {
+			return event
+		}
+		switch {
+		case event.Rune() == ' ':
+			if timeline.toggle() {
+				show(timeline.seek(timeline.start))
+			}
+			last = time.Now()
+		case event.Rune() >= '1' && event.Rune() <= '3':
+			timeline.speed = replaySpeeds[event.Rune()-'1']
+		case event.Key() == tcell.KeyLeft:
+			show(timeline.seek(timeline.position.Add(-timeline.step())))
+		case event.Key() == tcell.KeyRight:
+			show(timeline.seek(timeline.position.Add(timeline.step())))
+		case event.Rune() == 'q':
+			app.Stop()
+		default:
+			return event
+		}
+		status.SetText(timeline.status())
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		ticker := time.NewTicker(replayTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			app.QueueUpdateDraw(func() {
+				now := time.Now()
+				elapsed := now.Sub(last)
+				last = now
+				if !timeline.playing {
+					return
+				}
+				view.add(timeline.advance(elapsed)...)
+				status.SetText(timeline.status())
+			})
+		}
+	}()
+
+	view.add(timeline.advance(0)...)
+	status.SetText(timeline.status())
+	return app.SetRoot(layout, true).Run()
+}
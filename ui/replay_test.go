@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/gdamore/tcell/v2"
+)
+
+func replayFixture() []kube.Event {
+	at := func(minute int) time.Time {
+		return time.Date(2025, 5, 1, 12, minute, 0, 0, time.UTC)
+	}
+	return []kube.Event{
+		{UID: "3", Time: at(50), Namespace: "shop", Kind: "Pod", Name: "api-2", Type: "Warning", Reason: "BackOff", Message: "third"},
+		{UID: "1", Time: at(0), Namespace: "shop", Kind: "Pod", Name: "api-0", Type: "Normal", Reason: "Pulled", Message: "first"},
+		{UID: "2", Time: at(10), Namespace: "shop", Kind: "Pod", Name: "api-1", Type: "Warning", Reason: "BackOff", Message: "second"},
+	}
+}
+
+func TestReplayTimelinePlaysAtSpeedAndSeeks(t *testing.T) {
+	timeline := newReplayTimeline(replayFixture(), 60)
+
+	if got := timeline.advance(0); len(got) != 1 || got[0].UID != "1" {
+		t.Fatalf("at the start want the first event, got %v", got)
+	}
+	// Ten seconds at 60x are ten minutes of the incident.
+	if got := timeline.advance(10 * time.Second); len(got) != 1 || got[0].UID != "2" {
+		t.Fatalf("after ten minutes want the second event, got %v", got)
+	}
+	timeline.toggle()
+	if got := timeline.advance(time.Hour); got != nil || timeline.playing {
+		t.Fatalf("a paused replay moved on to %v", got)
+	}
+
+	if got := timeline.seek(timeline.start.Add(5 * time.Minute)); len(got) != 1 {
+		t.Fatalf("scrubbing back to minute 5 want one event shown, got %v", got)
+	}
+	if got := timeline.seek(timeline.end.Add(time.Hour)); len(got) != 3 || !timeline.position.Equal(timeline.end) {
+		t.Fatalf("scrubbing past the end want all events at the end, got %v at %v", got, timeline.position)
+	}
+	if !timeline.toggle() {
+		t.Fatal("playing at the end did not start over")
+	}
+}
+
+func TestReplayControls(t *testing.T) {
+	screen := newTestScreen()
+	done := make(chan error, 1)
+	go func() {
+		done <- StartReplay(replayFixture(), ReplayOptions{Name: "prod-eu", Screen: screen})
+	}()
+	t.Cleanup(func() {
+		screen.InjectKey(tcell.KeyRune, 'q', tcell.ModNone)
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Error("replay did not stop")
+		}
+	})
+
+	// At 1x the events ten minutes later are far off.
+	waitForScreen(t, screen, "first", func(text string) bool {
+		return strings.Contains(text, "▶ playing 1x") && !strings.Contains(text, "second")
+	})
+	screen.resize(screenWidth, screenHeight)
+
+	screen.InjectKey(tcell.KeyRune, '3', tcell.ModNone)
+	waitForScreen(t, screen, "60x", func(text string) bool { return strings.Contains(text, "1/3 events") })
+
+	// Each step is a fiftieth of the 50 minutes replayed.
+	for range 10 {
+		screen.InjectKey(tcell.KeyRight, 0, tcell.ModNone)
+	}
+	waitForScreen(t, screen, "second", func(text string) bool { return !strings.Contains(text, "third") })
+
+	screen.InjectKey(tcell.KeyRune, ' ', tcell.ModNone)
+	waitForScreen(t, screen, "paused", func(string) bool { return true })
+
+	for range 10 {
+		screen.InjectKey(tcell.KeyLeft, 0, tcell.ModNone)
+	}
+	waitForScreen(t, screen, "1/3 events", func(text string) bool { return !strings.Contains(text, "second") })
+}
@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/internal/format"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	// restartLookupInterval is how often one pod's status is read at most.
+	restartLookupInterval = 10 * time.Second
+	// restartEventWindow skips events older than this, e.g. from the initial list.
+	restartEventWindow = 5 * time.Minute
+	// restartAlertThreshold is how many restarts of one workload during the session
+	// flag it as crash looping in the table title.
+	restartAlertThreshold = 3
+)
+
+// restartReasons are the pod events that follow a container restart; ContainerRestarted
+// comes from -status-changes.
+var restartReasons = map[string]bool{"BackOff": true, "Started": true, "ContainerRestarted": true}
+
+// podRestarts is what the tracker knows of one pod.
+type podRestarts struct {
+	cluster   string
+	namespace string
+	workload  string
+	// baseline is the restart count when the pod was first looked up; restarts before
+	// the session are not counted against it.
+	baseline int32
+	current  int32
+	backOffs int
+	known    bool
+	pending  bool
+	lookedUp time.Time
+	// restarted is when a lookup last found more restarts than the one before.
+	restarted time.Time
+}
+
+// workloadRestarts sums the pods of one workload.
+type workloadRestarts struct {
+	cluster   string
+	namespace string
+	workload  string
+	// session counts restarts seen during the session, total all restarts of its pods.
+	session  int
+	total    int32
+	backOffs int
+	last     time.Time
+}
+
+func (w workloadRestarts) name() string {
+	name := w.namespace + "/" + w.workload
+	if w.cluster != "" {
+		name = w.cluster + "/" + name
+	}
+	return name
+}
+
+// restartTracker counts container restarts per workload during the session. BackOff
+// and Started events of a pod trigger a lookup of its status, whose restart count is
+// compared with the first one seen. Only used from the UI goroutine.
+type restartTracker struct {
+	pods map[string]*podRestarts
+}
+
+func newRestartTracker() *restartTracker {
+	return &restartTracker{pods: make(map[string]*podRestarts)}
+}
+
+func restartKey(cluster, namespace, pod string) string {
+	return cluster + "/" + namespace + "/" + pod
+}
+
+func (t *restartTracker) pod(cluster, namespace, name string) *podRestarts {
+	key := restartKey(cluster, namespace, name)
+	pod, ok := t.pods[key]
+	if !ok {
+		pod = &podRestarts{cluster: cluster, namespace: namespace}
+		t.pods[key] = pod
+	}
+	return pod
+}
+
+// noteBackOff records a BackOff event of a pod.
+func (t *restartTracker) noteBackOff(cluster, namespace, name string) {
+	t.pod(cluster, namespace, name).backOffs++
+}
+
+// wantsLookup reports whether the pod's status should be read now and marks the lookup
+// as pending if so.
+func (t *restartTracker) wantsLookup(cluster, namespace, name string, now time.Time) bool {
+	pod := t.pod(cluster, namespace, name)
+	if pod.pending || now.Sub(pod.lookedUp) < restartLookupInterval {
+		return false
+	}
+	pod.pending = true
+	return true
+}
+
+// update stores the result of a lookup.
+func (t *restartTracker) update(cluster, namespace, name, workload string, restarts int32, now time.Time) {
+	pod := t.pod(cluster, namespace, name)
+	pod.pending, pod.lookedUp, pod.workload = false, now, workload
+	if !pod.known {
+		pod.known, pod.baseline = true, restarts
+	} else if restarts > pod.current {
+		pod.restarted = now
+	}
+	pod.current = restarts
+}
+
+// failed ends a lookup that did not return, e.g. for a pod that is gone.
+func (t *restartTracker) failed(cluster, namespace, name string, now time.Time) {
+	pod := t.pod(cluster, namespace, name)
+	pod.pending, pod.lookedUp = false, now
+}
+
+// workloads returns the workloads with known pods, most restarted during the session
+// first, then by total restarts.
+func (t *restartTracker) workloads() []workloadRestarts {
+	byKey := make(map[string]*workloadRestarts)
+	for _, pod := range t.pods {
+		if !pod.known {
+			continue
+		}
+		key := restartKey(pod.cluster, pod.namespace, pod.workload)
+		w, ok := byKey[key]
+		if !ok {
+			w = &workloadRestarts{cluster: pod.cluster, namespace: pod.namespace, workload: pod.workload}
+			byKey[key] = w
+		}
+		w.session += int(pod.current - pod.baseline)
+		w.total += pod.current
+		w.backOffs += pod.backOffs
+		if pod.restarted.After(w.last) {
+			w.last = pod.restarted
+		}
+	}
+	workloads := make([]workloadRestarts, 0, len(byKey))
+	for _, w := range byKey {
+		workloads = append(workloads, *w)
+	}
+	sort.Slice(workloads, func(i, j int) bool {
+		a, b := workloads[i], workloads[j]
+		if a.session != b.session {
+			return a.session > b.session
+		}
+		if a.total != b.total {
+			return a.total > b.total
+		}
+		return a.name() < b.name()
+	})
+	return workloads
+}
+
+// top returns the workload restarted most during the session, if any restarted.
+func (t *restartTracker) top() (workloadRestarts, bool) {
+	workloads := t.workloads()
+	if len(workloads) == 0 || workloads[0].session == 0 {
+		return workloadRestarts{}, false
+	}
+	return workloads[0], true
+}
+
+// restartsText renders workloads as a table for the restarts panel.
+func restartsText(workloads []workloadRestarts, now time.Time) string {
+	if len(workloads) == 0 {
+		return "[gray]No pod restarts looked up yet. Workloads appear here once their pods report BackOff or Started events.[white]"
+	}
+	width := len("WORKLOAD")
+	for _, w := range workloads {
+		width = max(width, len(w.name()))
+	}
+	lines := []string{fmt.Sprintf("[::b]%-*s  %7s  %5s  %8s  %s[::-]", width, "WORKLOAD", "SESSION", "TOTAL", "BACKOFFS", "LAST RESTART")}
+	for _, w := range workloads {
+		last := "-"
+		if !w.last.IsZero() {
+			last = format.Ago(w.last, now)
+		}
+		color := "[white]"
+		switch {
+		case w.session >= restartAlertThreshold:
+			color = "[red]"
+		case w.session > 0:
+			color = "[yellow]"
+		}
+		lines = append(lines, color+escapeTViewText(fmt.Sprintf("%-*s  %7d  %5d  %8d  %s", width, w.name(), w.session, w.total, w.backOffs, last))+"[white]")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RestartsModal shows the workloads whose containers restarted, most restarted during
+// the session first. workloads is called again on r to refresh.
+func RestartsModal(
+	app *tview.Application,
+	frame *tview.Frame,
+	table *tview.Table,
+	workloads func() []workloadRestarts,
+	onClose func(),
+) {
+	header := "[green]Most restarted workloads[white]\n[gray]SESSION counts restarts since kubeve first looked at the pod, TOTAL all restarts of its current pods.[white]\n\n"
+	helpText := "\n\n[gray]r to refresh, Esc/q to close.[white]"
+
+	view := tview.NewTextView()
+	view.SetDynamicColors(true)
+	view.SetBorder(true)
+	view.SetTitle(" Restarts ")
+	view.SetBackgroundColor(0x000000)
+	view.SetScrollable(true)
+
+	modalFlex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 0, 1, false).
+		AddItem(
+			tview.NewFlex().
+				AddItem(tview.NewBox(), 0, 1, false).
+				AddItem(view, 0, 3, true).
+				AddItem(tview.NewBox(), 0, 1, false),
+			0, 3, true,
+		).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	render := func() {
+		view.SetText(header + restartsText(workloads(), time.Now()) + helpText)
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+			app.SetRoot(frame, true).SetFocus(table)
+			if onClose != nil {
+				onClose()
+			}
+			return nil
+		case event.Rune() == 'r':
+			render()
+			return nil
+		}
+		return event
+	})
+
+	app.SetRoot(modalFlex, true).SetFocus(view)
+	render()
+}
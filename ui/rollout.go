@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"context"
+
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/a0xAi/kubeve/kube/watch"
+)
+
+// cachedRolloutDetails prefixes rollout events with the Deployment revision they belong
+// to and ends ScalingReplicaSet events with the Deployment's replica counts, as far as
+// the resolvers have them cached, so it never waits on the API server. pending reports
+// whether a lookup is still needed, see resolveRolloutDetails.
+func cachedRolloutDetails(event kube.Event, revisions *watch.RevisionResolver, scales *watch.ScaleTracker) (kube.Event, bool) {
+	revision, revisionOK := revisions.CachedRevision(event)
+	replicas, replicasOK := scales.CachedReplicas(event)
+	if revision != "" {
+		event.Message = "(rev " + revision + ") " + event.Message
+	}
+	if replicas != "" {
+		event.Message += " (" + replicas + ")"
+	}
+	return event, !revisionOK || !replicasOK
+}
+
+// resolveRolloutDetails runs the lookups cachedRolloutDetails is missing for event, each
+// on its own goroutine, since they may wait on the API server until ctx is done. After
+// each one it calls resolved with the event and the details known by then.
+func resolveRolloutDetails(ctx context.Context, event kube.Event, revisions *watch.RevisionResolver, scales *watch.ScaleTracker, resolved func(kube.Event)) {
+	lookup := func(resolve func()) {
+		go func() {
+			resolve()
+			if ctx.Err() == nil {
+				detailed, _ := cachedRolloutDetails(event, revisions, scales)
+				resolved(detailed)
+			}
+		}()
+	}
+	if _, ok := revisions.CachedRevision(event); !ok {
+		lookup(func() { revisions.Revision(ctx, event) })
+	}
+	if _, ok := scales.CachedReplicas(event); !ok {
+		lookup(func() { scales.Replicas(ctx, event) })
+	}
+}
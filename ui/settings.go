@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// runtimeSetting is a setting :set changes while kubeve runs, without editing the
+// config file or restarting.
+type runtimeSetting struct {
+	name        string
+	description string
+	get         func() string
+	set         func(value string) error
+}
+
+// applySetting parses "key=value" (or "key value") and sets the named setting. Names
+// are matched case-insensitively.
+func applySetting(settings []runtimeSetting, arg string) (*runtimeSetting, error) {
+	arg = strings.TrimSpace(arg)
+	name, value, ok := strings.Cut(arg, "=")
+	if !ok {
+		name, value, ok = strings.Cut(arg, " ")
+	}
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("want set <key>=<value>, got %q", arg)
+	}
+	for i := range settings {
+		if strings.EqualFold(settings[i].name, name) {
+			if err := settings[i].set(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", settings[i].name, err)
+			}
+			return &settings[i], nil
+		}
+	}
+	names := make([]string, len(settings))
+	for i, setting := range settings {
+		names[i] = setting.name
+	}
+	return nil, fmt.Errorf("unknown setting %q, want one of %s", name, strings.Join(names, ", "))
+}
+
+// parseSettingInt parses a whole number of at least minimum.
+func parseSettingInt(value string, minimum int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	if n < minimum {
+		return 0, fmt.Errorf("must be at least %d", minimum)
+	}
+	return n, nil
+}
+
+// parseSettingDuration parses a duration such as 90s or 5m; a bare number is seconds.
+func parseSettingDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		value = strconv.Itoa(seconds) + "s"
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a duration such as 90s or 5m", value)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("must not be negative")
+	}
+	return d, nil
+}
+
+// settingsText renders the settings with their current values, aligned in columns.
+func settingsText(settings []runtimeSetting) string {
+	nameWidth, valueWidth := 0, 0
+	for _, setting := range settings {
+		nameWidth = max(nameWidth, len(setting.name))
+		valueWidth = max(valueWidth, len(setting.get()))
+	}
+	var lines []string
+	for _, setting := range settings {
+		lines = append(lines, fmt.Sprintf("[blue]%-*s[white]  %-*s  [gray]%s[white]",
+			nameWidth, setting.name, valueWidth, escapeTViewText(setting.get()), escapeTViewText(setting.description)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SettingsModal lists the runtime settings and their current values.
+func SettingsModal(app *tview.Application, frame *tview.Frame, table *tview.Table, settings []runtimeSetting) {
+	view := tview.NewTextView()
+	view.SetDynamicColors(true)
+	view.SetWrap(true)
+	view.SetBorder(true)
+	view.SetTitle(" Settings ")
+	view.SetBackgroundColor(0x000000)
+	view.SetScrollable(true)
+	view.SetText(settingsText(settings) + "\n\n[gray]Change one with :set <key>=<value>. Esc/q to close.[white]")
+
+	modalFlex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 0, 1, false).
+		AddItem(
+			tview.NewFlex().
+				AddItem(tview.NewBox(), 0, 1, false).
+				AddItem(view, 0, 3, true).
+				AddItem(tview.NewBox(), 0, 1, false),
+			0, 2, true,
+		).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc || event.Rune() == 'q' {
+			app.SetRoot(frame, true).SetFocus(table)
+			return nil
+		}
+		return event
+	})
+
+	app.SetRoot(modalFlex, true).SetFocus(view)
+}
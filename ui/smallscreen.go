@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// minScreenWidth and minScreenHeight are the smallest terminal the layout fits: below
+// them the header, the table's columns and the filter overlap.
+const (
+	minScreenWidth  = 60
+	minScreenHeight = 12
+)
+
+// drawTooSmall draws a note asking for a larger terminal instead of the UI when screen
+// is smaller than the minimum, and reports whether it did. The application redraws on
+// resize, so the UI comes back by itself once the terminal is large enough.
+func drawTooSmall(screen tcell.Screen) bool {
+	width, height := screen.Size()
+	if width >= minScreenWidth && height >= minScreenHeight {
+		return false
+	}
+	lines := []string{
+		"[::b]Terminal too small",
+		fmt.Sprintf("%dx%d", width, height),
+		fmt.Sprintf("Resize to at least %dx%d", minScreenWidth, minScreenHeight),
+	}
+	top := max((height-len(lines))/2, 0)
+	for i, line := range lines {
+		tview.Print(screen, line, 0, top+i, width, tview.AlignCenter, tview.Styles.PrimaryTextColor)
+	}
+	return true
+}
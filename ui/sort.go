@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sortFields are the keys rows can be sorted by. count is the number of occurrences:
+// the group size in aggregate mode, the event's own count otherwise.
+var sortFields = []string{"time", "cluster", "namespace", "resource", "type", "reason", "count", "message"}
+
+// sortKey is one key of a sort order; desc sorts largest or latest first.
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+// sortOrder lists sort keys by priority; later keys break ties of earlier ones.
+type sortOrder []sortKey
+
+// defaultAggregateOrder puts the noisiest groups first.
+var defaultAggregateOrder = sortOrder{
+	{field: "count", desc: true},
+	{field: "time", desc: true},
+	{field: "cluster"},
+	{field: "namespace"},
+	{field: "resource"},
+	{field: "reason"},
+}
+
+// parseSortOrder parses comma or space separated keys such as "namespace,-time". A
+// leading - sorts a key in descending order. An empty spec is the default order.
+func parseSortOrder(spec string) (sortOrder, error) {
+	var order sortOrder
+	for _, field := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == ' ' }) {
+		key := sortKey{field: strings.ToLower(field)}
+		if rest, ok := strings.CutPrefix(key.field, "-"); ok {
+			key.field, key.desc = rest, true
+		} else {
+			key.field = strings.TrimPrefix(key.field, "+")
+		}
+		switch key.field {
+		case "last-seen", "lastseen":
+			key.field = "time"
+		case "ns":
+			key.field = "namespace"
+		case "status":
+			key.field = "type"
+		}
+		known := false
+		for _, name := range sortFields {
+			known = known || name == key.field
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown sort key %q, want one of %s", field, strings.Join(sortFields, ", "))
+		}
+		order = append(order, key)
+	}
+	return order, nil
+}
+
+func (o sortOrder) String() string {
+	keys := make([]string, len(o))
+	for i, key := range o {
+		keys[i] = key.field
+		if key.desc {
+			keys[i] = "-" + key.field
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// sortRecord holds the values a row is sorted by.
+type sortRecord struct {
+	time      time.Time
+	cluster   string
+	namespace string
+	resource  string
+	eventType string
+	reason    string
+	count     int
+	message   string
+}
+
+// compare orders a and b by the keys of o, returning 0 when they tie on all of them.
+func (o sortOrder) compare(a, b sortRecord) int {
+	for _, key := range o {
+		var c int
+		switch key.field {
+		case "time":
+			c = a.time.Compare(b.time)
+		case "cluster":
+			c = cmp.Compare(a.cluster, b.cluster)
+		case "namespace":
+			c = cmp.Compare(a.namespace, b.namespace)
+		case "resource":
+			c = cmp.Compare(a.resource, b.resource)
+		case "type":
+			c = cmp.Compare(a.eventType, b.eventType)
+		case "reason":
+			c = cmp.Compare(a.reason, b.reason)
+		case "count":
+			c = cmp.Compare(a.count, b.count)
+		case "message":
+			c = cmp.Compare(a.message, b.message)
+		}
+		if key.desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func (e streamEvent) sortRecord() sortRecord {
+	return sortRecord{
+		time:      e.event.Time,
+		cluster:   e.event.Cluster,
+		namespace: e.event.Namespace,
+		resource:  e.event.Kind + "/" + e.event.Name,
+		eventType: e.event.Type,
+		reason:    e.event.Reason,
+		count:     int(e.event.Count),
+		message:   e.event.Message,
+	}
+}
+
+// sortStreamRows sorts the rows streamRows returned, and their sources along with them,
+// by order. Rows that tie keep their arrival order.
+func sortStreamRows(events []streamEvent, rows []tableRow, sources []int, order sortOrder) {
+	if len(order) == 0 {
+		return
+	}
+	sort.Stable(streamRowSorter{events: events, rows: rows, sources: sources, order: order})
+}
+
+type streamRowSorter struct {
+	events  []streamEvent
+	rows    []tableRow
+	sources []int
+	order   sortOrder
+}
+
+func (s streamRowSorter) Len() int { return len(s.rows) }
+
+func (s streamRowSorter) Less(i, j int) bool {
+	return s.order.compare(s.events[s.sources[i]].sortRecord(), s.events[s.sources[j]].sortRecord()) < 0
+}
+
+func (s streamRowSorter) Swap(i, j int) {
+	s.rows[i], s.rows[j] = s.rows[j], s.rows[i]
+	s.sources[i], s.sources[j] = s.sources[j], s.sources[i]
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"time"
+)
+
+const (
+	// dnsFailureWindow and dnsFailureThreshold decide when DNS errors in events are
+	// frequent enough to suggest checking cluster DNS.
+	dnsFailureWindow    = 5 * time.Minute
+	dnsFailureThreshold = 3
+)
+
+// stormDetector counts events per involved object within a sliding window to find
+// objects flooding the stream ("top talkers").
+type stormDetector struct {
+	window time.Duration
+	hits   map[string][]time.Time
+}
+
+func newStormDetector(window time.Duration) *stormDetector {
+	return &stormDetector{
+		window: window,
+		hits:   make(map[string][]time.Time),
+	}
+}
+
+// observe records an event for key that happened at ts. Events older than the window
+// (e.g. from the initial list) are ignored so history does not look like a storm.
+func (d *stormDetector) observe(key string, ts time.Time, now time.Time) {
+	if ts.IsZero() || now.Sub(ts) > d.window {
+		return
+	}
+	d.hits[key] = append(d.prune(d.hits[key], now), ts)
+}
+
+// top returns the object with the most events inside the window.
+func (d *stormDetector) top(now time.Time) (string, int) {
+	best := ""
+	bestCount := 0
+	for key, hits := range d.hits {
+		hits = d.prune(hits, now)
+		if len(hits) == 0 {
+			delete(d.hits, key)
+			continue
+		}
+		d.hits[key] = hits
+		if len(hits) > bestCount || (len(hits) == bestCount && key < best) {
+			best = key
+			bestCount = len(hits)
+		}
+	}
+	return best, bestCount
+}
+
+func (d *stormDetector) forget(key string) {
+	delete(d.hits, key)
+}
+
+func (d *stormDetector) prune(hits []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-d.window)
+	kept := hits[:0]
+	for _, ts := range hits {
+		if !ts.Before(cutoff) {
+			kept = append(kept, ts)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,86 @@
+package ui
+
+import "github.com/a0xAi/kubeve/kube"
+
+const deletedMarker = "(deleted) "
+
+// streamEvent is one event of the stream with the marks its row carries. Rows are only
+// ever rendered from the event, so the event behind a row is never looked up by
+// parsing or comparing row text.
+type streamEvent struct {
+	event kube.Event
+	// previous marks events of an object that was since deleted and re-created.
+	previous bool
+	// deleted marks events the cluster deleted, usually because they expired.
+	deleted bool
+	// hidden drops the event from the table; it keeps its place so indexes stay valid.
+	hidden bool
+	// noted marks events with a session note.
+	noted bool
+	// band is the gutter color of the event's cluster, see clusterBands.
+	band string
+}
+
+func newStreamEvent(event kube.Event, previous bool) streamEvent {
+	return streamEvent{event: event, previous: previous}
+}
+
+// row returns the table row of the event.
+func (e *streamEvent) row() tableRow {
+	return tableRow{event: e.event, previous: e.previous, deleted: e.deleted, noted: e.noted, band: e.band}
+}
+
+// update replaces the event, keeping its marks.
+func (e *streamEvent) update(event kube.Event) {
+	e.event = event
+}
+
+// repeatedBy reports whether event, which has the same UID, is a later state of the
+// event, e.g. with a higher count or a later last-seen time.
+func (e *streamEvent) repeatedBy(event kube.Event) bool {
+	return event.Time.After(e.event.Time) || event.Count > e.event.Count || event.Message != e.event.Message
+}
+
+// markPrevious marks the event as belonging to an older incarnation of its object.
+func (e *streamEvent) markPrevious() {
+	e.previous = true
+}
+
+// setNoted marks whether the event has a session note.
+func (e *streamEvent) setNoted(noted bool) {
+	e.noted = noted
+}
+
+// markDeleted marks the event as deleted by the cluster.
+func (e *streamEvent) markDeleted() {
+	e.deleted = true
+}
+
+// streamRows returns the rows of the events in namespace scope that match filterText,
+// with the index in events each row was rendered from.
+func streamRows(events []streamEvent, namespace, filterText string) (rows []tableRow, sources []int) {
+	for i := range events {
+		entry := &events[i]
+		if entry.hidden || !kube.InNamespaces(namespace, entry.event.Namespace) {
+			continue
+		}
+		if row := entry.row(); matchesFilter(row, filterText) {
+			rows = append(rows, row)
+			sources = append(sources, i)
+		}
+	}
+	return rows, sources
+}
+
+// latestEventOf returns the index in events of the latest event an aggregated row
+// stands for, by its namespace, resource and reason, or -1.
+func latestEventOf(events []streamEvent, row tableRow) int {
+	for i := len(events) - 1; i >= 0; i-- {
+		other := events[i].event
+		if other.Cluster == row.event.Cluster && other.Namespace == row.event.Namespace &&
+			other.Kind == row.event.Kind && other.Name == row.event.Name && other.Reason == row.event.Reason {
+			return i
+		}
+	}
+	return -1
+}
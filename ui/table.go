@@ -3,16 +3,17 @@ package ui
 import (
 	"fmt"
 	"sort"
-	"strconv"
 	"strings"
-	"time"
 
+	"github.com/a0xAi/kubeve/internal/format"
+	"github.com/a0xAi/kubeve/kube"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
 type ColumnOptions struct {
 	Timestamp bool
+	Cluster   bool
 	Namespace bool
 	Status    bool
 	Action    bool
@@ -39,6 +40,11 @@ func renderTableHeader(table *tview.Table, opts ColumnOptions) {
 			SetSelectable(false).SetAttributes(tcell.AttrBold).SetExpansion(1))
 		col++
 	}
+	if opts.Cluster {
+		table.SetCell(0, col, tview.NewTableCell("CLUSTER").
+			SetSelectable(false).SetAttributes(tcell.AttrBold).SetExpansion(1))
+		col++
+	}
 	if opts.Namespace {
 		table.SetCell(0, col, tview.NewTableCell("NAMESPACE").
 			SetSelectable(false).SetAttributes(tcell.AttrBold).SetExpansion(1))
@@ -72,30 +78,49 @@ func renderTableHeader(table *tview.Table, opts ColumnOptions) {
 		SetSelectable(false).SetAttributes(tcell.AttrBold).SetExpansion(5))
 }
 
-func renderRow(table *tview.Table, row int, parts []string, opts ColumnOptions) {
+func renderRow(table *tview.Table, row int, r tableRow, opts ColumnOptions) {
+	renderCells(table, row, r.cells(), opts)
+}
+
+// renderCells sets the cells of table row row. Rows of deleted events are greyed out,
+// and the first cell starts with the gutter of the row's cluster.
+func renderCells(table *tview.Table, row int, cells rowText, opts ColumnOptions) {
+	textColor := tview.Styles.PrimaryTextColor
+	if cells.deleted {
+		textColor = tcell.ColorGray
+	}
+	gutter := bandGutter(cells.band)
+	cell := func(text string) *tview.TableCell {
+		text, gutter = gutter+text, ""
+		return tview.NewTableCell(text)
+	}
 	col := 0
 	if opts.Timestamp {
-		table.SetCell(row, col, tview.NewTableCell(strings.TrimSpace(parts[0])).SetExpansion(1))
+		table.SetCell(row, col, cell(cells.time).SetExpansion(1).SetTextColor(textColor))
+		col++
+	}
+	if opts.Cluster {
+		table.SetCell(row, col, cell(cells.cluster).SetExpansion(1).SetTextColor(textColor))
 		col++
 	}
 	if opts.Namespace {
-		table.SetCell(row, col, tview.NewTableCell(strings.TrimSpace(parts[4])).SetExpansion(1))
+		table.SetCell(row, col, cell(cells.namespace).SetExpansion(1).SetTextColor(textColor))
 		col++
 	}
 	if opts.Status {
-		statusText := strings.TrimSpace(parts[2])
 		statusColor := "[white]"
-		switch statusText {
-		case "Warning":
+		switch {
+		case cells.deleted:
+			statusColor = "[gray]"
+		case cells.status == "Warning":
 			statusColor = "[yellow]"
 		}
-		table.SetCell(row, col, tview.NewTableCell(fmt.Sprintf("%s%s", statusColor, statusText)).SetExpansion(1))
+		table.SetCell(row, col, cell(fmt.Sprintf("%s%s", statusColor, cells.status)).SetExpansion(1))
 		col++
 	}
 	if opts.Action {
-		actionText := strings.TrimSpace(parts[3])
 		actionColor := "[white]"
-		switch actionText {
+		switch cells.action {
 		case "Created", "SuccessfulCreate", "Completed":
 			actionColor = "[green]"
 		case "Started", "Pulled", "Pulling":
@@ -103,26 +128,165 @@ func renderRow(table *tview.Table, row int, parts []string, opts ColumnOptions)
 		case "Killing", "BackOff", "Unhealthy", "FailedToRetrieveImagePullSecret":
 			actionColor = "[red]"
 		}
-		table.SetCell(row, col, tview.NewTableCell(fmt.Sprintf("%s%s", actionColor, actionText)).
+		if cells.deleted {
+			actionColor = "[gray]"
+		}
+		table.SetCell(row, col, cell(fmt.Sprintf("%s%s", actionColor, cells.action)).
 			SetExpansion(1).SetTextColor(tcell.ColorWhite))
 		col++
 	}
 	if opts.Resource {
-		table.SetCell(row, col, tview.NewTableCell(strings.TrimSpace(parts[1])).SetExpansion(2))
+		table.SetCell(row, col, cell(cells.resource).SetExpansion(2).SetTextColor(textColor))
 		col++
 	}
-	table.SetCell(row, col, tview.NewTableCell(strings.TrimSpace(parts[5])).SetExpansion(5))
+	table.SetCell(row, col, cell(cells.message).SetExpansion(5).SetTextColor(textColor))
+}
+
+// lineBreakMarker joins the lines of a multi-line message in its table row.
+const lineBreakMarker = " ↵ "
+
+// tableRow is a row of the event table: an event, or the latest event of an aggregated
+// group, with the marks the stream put on it. Its cells are rendered from the event's
+// fields, so the event behind a row is never recovered from row text.
+type tableRow struct {
+	event kube.Event
+	// count is the size of an aggregated group, shown instead of the event's type. It
+	// is 0 for the row of a single event.
+	count int
+	// previous, deleted and noted mark events of an older incarnation of their object,
+	// events the cluster deleted and events with a session note.
+	previous, deleted, noted bool
+	// band is the gutter color of the event's cluster in a stream of several contexts,
+	// or "".
+	band string
+}
+
+// rowText holds the text of the cells of a table row.
+type rowText struct {
+	time, cluster, namespace, status, action, resource, message string
+	deleted                                                     bool
+	band                                                        string
+}
+
+func (r tableRow) cells() rowText {
+	return rowText{
+		time:      r.timeText(),
+		cluster:   r.event.Cluster,
+		namespace: r.event.Namespace,
+		status:    r.status(),
+		action:    r.event.Reason,
+		resource:  r.resource(),
+		message:   r.message(),
+		deleted:   r.deleted,
+		band:      r.band,
+	}
+}
+
+// timeText is the time cell, "-" for events without a time.
+func (r tableRow) timeText() string {
+	if r.event.Time.IsZero() {
+		return "-"
+	}
+	return format.Timestamp(r.event.Time)
+}
+
+func (r tableRow) resource() string {
+	return r.event.Kind + "/" + r.event.Name
+}
+
+// status is the event's type, or the size of an aggregated group.
+func (r tableRow) status() string {
+	if r.count > 0 {
+		return format.Count(int64(r.count))
+	}
+	return r.event.Type
+}
+
+// scope is the namespace written as context/namespace for events from one of several
+// watched contexts.
+func (r tableRow) scope() string {
+	if r.event.Cluster != "" {
+		return r.event.Cluster + "/" + r.event.Namespace
+	}
+	return r.event.Namespace
+}
+
+// message is the message cell: the message flattened to one line by rowMessage, with
+// the row's marks and eventOrigin around it.
+func (r tableRow) message() string {
+	return r.marked(rowMessage(r.event.Message))
+}
+
+// detailMessage is message with the message as the cluster reported it, line breaks
+// included, for the drill-down and the other views that show a message whole.
+func (r tableRow) detailMessage() string {
+	return r.marked(strings.TrimSpace(r.event.Message))
+}
+
+func (r tableRow) marked(message string) string {
+	message += eventOrigin(r.event)
+	if r.noted {
+		message = noteMarker + message
+	}
+	if r.deleted {
+		message = deletedMarker + message
+	}
+	if r.previous {
+		message = previousIncarnationMarker + message
+	}
+	return strings.TrimSpace(message)
+}
+
+// text is the row as one line of its cells, which filters and searches match.
+func (r tableRow) text() string {
+	return strings.Join([]string{r.timeText(), r.resource(), r.status(), r.event.Reason, r.scope(), r.message()}, " ")
+}
+
+// rowMessage flattens a message for a table row, where a line break would start a row
+// of its own: runs of whitespace become one space, blank lines are dropped and the
+// remaining lines are joined by lineBreakMarker.
+func rowMessage(message string) string {
+	var lines []string
+	for _, line := range strings.FieldsFunc(message, isLineBreak) {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, lineBreakMarker)
 }
 
-func matchesFilter(line string, filterText string) bool {
-	return strings.Contains(line, filterText)
+func isLineBreak(r rune) bool {
+	switch r {
+	case '\n', '\r', '\v', '\f', '\u0085', '\u2028', '\u2029':
+		return true
+	}
+	return false
 }
 
-func filterEvents(events []string, filterText string) []string {
-	filtered := make([]string, 0, len(events))
-	for _, line := range events {
-		if matchesFilter(line, filterText) {
-			filtered = append(filtered, line)
+// eventOrigin renders how often an event occurred and who reported it, e.g. " (x12, kubelet)".
+func eventOrigin(event kube.Event) string {
+	var parts []string
+	if event.Count > 1 {
+		parts = append(parts, "x"+format.Count(int64(event.Count)))
+	}
+	if event.Source != "" {
+		parts = append(parts, event.Source)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+func matchesFilter(row tableRow, filterText string) bool {
+	return strings.Contains(row.text(), filterText)
+}
+
+func filterRows(rows []tableRow, filterText string) []tableRow {
+	filtered := make([]tableRow, 0, len(rows))
+	for _, row := range rows {
+		if matchesFilter(row, filterText) {
+			filtered = append(filtered, row)
 		}
 	}
 	return filtered
@@ -139,6 +303,10 @@ func messageColumnWidth(tableWidth int, opts ColumnOptions) int {
 		columns++
 		expansionTotal++
 	}
+	if opts.Cluster {
+		columns++
+		expansionTotal++
+	}
 	if opts.Namespace {
 		columns++
 		expansionTotal++
@@ -240,52 +408,51 @@ func wrapMessage(text string, width int) []string {
 	return lines
 }
 
+// aggregatedEvent is a group of events with the same namespace, resource and reason.
 type aggregatedEvent struct {
-	namespace   string
-	resource    string
-	reason      string
-	lastMessage string
-	lastSeen    time.Time
-	lastType    string
-	count       int
+	latest tableRow
+	count  int
 }
 
-func aggregateEvents(events []string) []string {
-	groups := make(map[string]*aggregatedEvent, len(events))
-	for _, line := range events {
-		parts := strings.SplitN(line, "│", 6)
-		if len(parts) != 6 {
-			continue
-		}
+func (g *aggregatedEvent) row() tableRow {
+	row := g.latest
+	row.count = g.count
+	return row
+}
 
-		lastSeenText := strings.TrimSpace(parts[0])
-		resource := strings.TrimSpace(parts[1])
-		eventType := strings.TrimSpace(parts[2])
-		reason := strings.TrimSpace(parts[3])
-		namespace := strings.TrimSpace(parts[4])
-		message := strings.TrimSpace(parts[5])
+func (g *aggregatedEvent) sortRecord() sortRecord {
+	row := g.row()
+	return sortRecord{
+		time:      row.event.Time,
+		cluster:   row.event.Cluster,
+		namespace: row.event.Namespace,
+		resource:  row.resource(),
+		eventType: row.event.Type,
+		reason:    row.event.Reason,
+		count:     row.count,
+		message:   row.message(),
+	}
+}
 
-		key := namespace + "|" + resource + "|" + reason
+func aggregateEvents(rows []tableRow) []tableRow {
+	return aggregateEventsBy(rows, nil)
+}
+
+// aggregateEventsBy groups rows by namespace, resource and reason into rows of the
+// latest event of each group, and sorts the groups by order, falling back to the
+// default order for ties.
+func aggregateEventsBy(rows []tableRow, order sortOrder) []tableRow {
+	groups := make(map[string]*aggregatedEvent, len(rows))
+	for _, row := range rows {
+		key := row.scope() + "|" + row.resource() + "|" + row.event.Reason
 		group, exists := groups[key]
 		if !exists {
-			group = &aggregatedEvent{
-				namespace: namespace,
-				resource:  resource,
-				reason:    reason,
-				lastType:  eventType,
-			}
+			group = &aggregatedEvent{latest: row}
 			groups[key] = group
 		}
 		group.count++
-
-		parsedTime, err := time.Parse(time.RFC3339, lastSeenText)
-		if err != nil {
-			parsedTime = time.Time{}
-		}
-		if group.lastSeen.IsZero() || parsedTime.After(group.lastSeen) {
-			group.lastSeen = parsedTime
-			group.lastType = eventType
-			group.lastMessage = message
+		if row.event.Time.After(group.latest.event.Time) {
+			group.latest = row
 		}
 	}
 
@@ -293,80 +460,53 @@ func aggregateEvents(events []string) []string {
 	for _, group := range groups {
 		summary = append(summary, group)
 	}
+	order = append(append(sortOrder{}, order...), defaultAggregateOrder...)
 	sort.Slice(summary, func(i, j int) bool {
-		if summary[i].count != summary[j].count {
-			return summary[i].count > summary[j].count
-		}
-		if !summary[i].lastSeen.Equal(summary[j].lastSeen) {
-			return summary[i].lastSeen.After(summary[j].lastSeen)
-		}
-		if summary[i].namespace != summary[j].namespace {
-			return summary[i].namespace < summary[j].namespace
-		}
-		if summary[i].resource != summary[j].resource {
-			return summary[i].resource < summary[j].resource
-		}
-		return summary[i].reason < summary[j].reason
+		return order.compare(summary[i].sortRecord(), summary[j].sortRecord()) < 0
 	})
 
-	lines := make([]string, 0, len(summary))
+	aggregated := make([]tableRow, 0, len(summary))
 	for _, group := range summary {
-		lastSeenText := ""
-		if group.lastSeen.IsZero() {
-			lastSeenText = "-"
-		} else {
-			lastSeenText = group.lastSeen.Format(time.RFC3339)
-		}
-		lines = append(lines, fmt.Sprintf("%-25s │ %-60s │ %-10s │ %-20s │ %-10s │ %s",
-			lastSeenText,
-			group.resource,
-			strconv.Itoa(group.count),
-			group.reason,
-			group.namespace,
-			group.lastMessage,
-		))
+		aggregated = append(aggregated, group.row())
 	}
-
-	return lines
+	return aggregated
 }
 
 func renderTableContent(
 	table *tview.Table,
-	events []string,
+	rows []tableRow,
 	filterText string,
 	opts ColumnOptions,
 	wrapMessages bool,
 	tableWidth int,
 ) []int {
-	rowToEvent := make([]int, 0, len(events))
+	rowToEvent := make([]int, 0, len(rows))
 	row := 1
 	msgWidth := messageColumnWidth(tableWidth, opts)
-	for eventIdx, line := range filterEvents(events, filterText) {
-		parts := strings.SplitN(line, "│", 6)
-		if len(parts) == 6 {
-			if !wrapMessages {
-				renderRow(table, row, parts, opts)
-				rowToEvent = append(rowToEvent, eventIdx)
-				row++
-				continue
-			}
+	for eventIdx, r := range filterRows(rows, filterText) {
+		if !wrapMessages {
+			renderRow(table, row, r, opts)
+			rowToEvent = append(rowToEvent, eventIdx)
+			row++
+			continue
+		}
 
-			wrapped := wrapMessage(strings.TrimSpace(parts[5]), msgWidth)
-			if len(wrapped) == 0 {
-				wrapped = []string{""}
-			}
+		// Wrapped rows have room to give each line of a message its own.
+		cells := r.cells()
+		wrapped := wrapMessage(strings.ReplaceAll(cells.message, lineBreakMarker, "\n"), msgWidth)
+		if len(wrapped) == 0 {
+			wrapped = []string{""}
+		}
+
+		cells.message = wrapped[0]
+		renderCells(table, row, cells, opts)
+		rowToEvent = append(rowToEvent, eventIdx)
+		row++
 
-			first := append([]string(nil), parts...)
-			first[5] = wrapped[0]
-			renderRow(table, row, first, opts)
+		for _, cont := range wrapped[1:] {
+			renderCells(table, row, rowText{message: cont, deleted: cells.deleted, band: cells.band}, opts)
 			rowToEvent = append(rowToEvent, eventIdx)
 			row++
-
-			for _, cont := range wrapped[1:] {
-				renderRow(table, row, []string{"", "", "", "", "", cont}, opts)
-				rowToEvent = append(rowToEvent, eventIdx)
-				row++
-			}
 		}
 	}
 	return rowToEvent
@@ -374,7 +514,7 @@ func renderTableContent(
 
 func renderTable(
 	table *tview.Table,
-	events []string,
+	rows []tableRow,
 	filterText string,
 	opts ColumnOptions,
 	wrapMessages bool,
@@ -382,5 +522,5 @@ func renderTable(
 ) []int {
 	table.Clear()
 	renderTableHeader(table, opts)
-	return renderTableContent(table, events, filterText, opts, wrapMessages, tableWidth)
+	return renderTableContent(table, rows, filterText, opts, wrapMessages, tableWidth)
 }
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// tableFixture is a set of events rendered in every golden case.
+func tableFixture() []streamEvent {
+	at := func(minute int) time.Time {
+		return time.Date(2025, 5, 1, 12, minute, 0, 0, time.UTC)
+	}
+	events := []streamEvent{
+		newStreamEvent(kube.Event{UID: "1", Time: at(0), Namespace: "shop", Kind: "Pod", Name: "api-7d9c4-x2x8q",
+			Type: "Normal", Reason: "Pulled", Message: `Successfully pulled image "registry.example.com/shop/api:1.4.2" in 2.1s`, Source: "kubelet"}, false),
+		newStreamEvent(kube.Event{UID: "2", Time: at(1), Namespace: "shop", Kind: "Pod", Name: "api-7d9c4-x2x8q",
+			Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container api in pod api-7d9c4-x2x8q_shop(0b6f)", Count: 12, Source: "kubelet"}, false),
+		newStreamEvent(kube.Event{UID: "3", Time: at(2), Namespace: "payments", Kind: "Deployment", Name: "ledger",
+			Type: "Normal", Reason: "ScalingReplicaSet", Message: "Scaled up replica set ledger-5f7b8 to 3", Source: "deployment-controller"}, false),
+		newStreamEvent(kube.Event{UID: "4", Time: at(3), Namespace: "shop", Kind: "Pod", Name: "api-7d9c4-x2x8q",
+			Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container api in pod api-7d9c4-x2x8q_shop(0b6f)", Count: 13, Source: "kubelet"}, true),
+		newStreamEvent(kube.Event{UID: "5", Time: at(4), Namespace: "payments", Kind: "Job", Name: "nightly-settle",
+			Type: "Normal", Reason: "Completed", Message: "Job completed", Source: "job-controller"}, false),
+	}
+	events[4].markDeleted()
+	return events
+}
+
+func TestRenderTableGolden(t *testing.T) {
+	all := ColumnOptions{Timestamp: true, Namespace: true, Status: true, Action: true, Resource: true}
+	cases := []struct {
+		name      string
+		opts      ColumnOptions
+		width     int
+		wrap      bool
+		aggregate bool
+		filter    string
+		clusters  bool
+		sort      string
+	}{
+		{name: "all-columns-160", opts: all, width: 160},
+		{name: "all-columns-100", opts: all, width: 100},
+		{name: "message-only-80", opts: ColumnOptions{}, width: 80},
+		{name: "wrap-100", opts: all, width: 100, wrap: true},
+		{name: "filter-backoff-120", opts: all, width: 120, filter: "BackOff"},
+		{name: "clusters-140", opts: ColumnOptions{Timestamp: true, Cluster: true, Namespace: true, Status: true, Action: true, Resource: true}, width: 140, clusters: true},
+		{name: "aggregate-120", opts: ColumnOptions{Timestamp: true, Namespace: true, Status: true, Action: true, Resource: true, Aggregate: true}, width: 120, aggregate: true},
+		{name: "sort-namespace-time-120", opts: all, width: 120, sort: "namespace,-time"},
+		{name: "aggregate-sort-namespace-120", opts: ColumnOptions{Timestamp: true, Namespace: true, Status: true, Action: true, Resource: true, Aggregate: true}, width: 120, aggregate: true, sort: "namespace"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			events := tableFixture()
+			if tc.clusters {
+				contexts := []string{"prod-eu", "prod-us"}
+				for i := range events {
+					events[i].event.Cluster = contexts[i%2]
+					events[i].band = clusterBands(contexts)[events[i].event.Cluster]
+				}
+			}
+			order, err := parseSortOrder(tc.sort)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows, sources := streamRows(events, "", "")
+			if tc.aggregate {
+				rows = aggregateEventsBy(rows, order)
+			} else {
+				sortStreamRows(events, rows, sources, order)
+			}
+			got := renderToScreen(t, rows, tc.filter, tc.opts, tc.wrap, tc.width, 12)
+			compareGolden(t, filepath.Join("testdata", "table-"+tc.name+".golden"), got)
+		})
+	}
+}
+
+// renderToScreen renders rows into a bordered table on a simulation screen of the given
+// size and returns the screen's text.
+func renderToScreen(t *testing.T, rows []tableRow, filter string, opts ColumnOptions, wrap bool, width, height int) string {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(width, height)
+
+	table := NewTable("events")
+	table.SetRect(0, 0, width, height)
+	_, _, innerWidth, _ := table.GetInnerRect()
+	renderTable(table, rows, filter, opts, wrap, innerWidth)
+	table.Draw(screen)
+	screen.Show()
+
+	var out strings.Builder
+	for _, line := range screenLines(screen) {
+		out.WriteString(strings.TrimRight(line, " "))
+		out.WriteByte('\n')
+	}
+	return out.String()
+}
+
+func compareGolden(t *testing.T, path, got string) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run go test ./ui -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("rendering differs from %s (run go test ./ui -update to accept):\n--- got\n%s--- want\n%s", path, got, want)
+	}
+}
+
+func TestClusterGutterColors(t *testing.T) {
+	contexts := []string{"prod-eu", "prod-us"}
+	bands := clusterBands(contexts)
+	var rows []tableRow
+	for _, cluster := range contexts {
+		rows = append(rows, tableRow{
+			event: kube.Event{Cluster: cluster, Namespace: "shop", Kind: "Pod", Name: "api", Type: "Normal", Reason: "Pulled"},
+			band:  bands[cluster],
+		})
+	}
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("init screen: %v", err)
+	}
+	defer screen.Fini()
+	screen.SetSize(80, 6)
+	table := NewTable("events")
+	table.SetRect(0, 0, 80, 6)
+	// The selected row would swap the colors.
+	table.SetSelectable(false, false)
+	renderTable(table, rows, "", ColumnOptions{Cluster: true}, false, 78)
+	table.Draw(screen)
+
+	// Inside the border, the header is on screen row 1 and the rows follow.
+	for i, cluster := range contexts {
+		mainc, _, style, _ := screen.GetContent(1, 2+i)
+		fg, _, _ := style.Decompose()
+		if mainc != '▌' || fg != tcell.GetColor(bands[cluster]) {
+			t.Errorf("row of %s starts with %q in %v, want the gutter in %s", cluster, mainc, fg, bands[cluster])
+		}
+		_, _, style, _ = screen.GetContent(3, 2+i)
+		if fg, _, _ := style.Decompose(); fg != tview.Styles.PrimaryTextColor {
+			t.Errorf("the gutter color of %s runs into the cell text: %v", cluster, fg)
+		}
+	}
+	if bands["prod-eu"] == bands["prod-us"] {
+		t.Error("both clusters got the same gutter color")
+	}
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/a0xAi/kubeve/kube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const maxTabs = 9
+
+// tabView holds the settings of one tab. Events are shared by all tabs and filtered per view.
+type tabView struct {
+	namespace    string
+	filterText   string
+	autoScroll   bool
+	columns      ColumnOptions
+	wrapMessages bool
+	sorting      sortOrder
+}
+
+func (t tabView) label() string {
+	label := t.namespace
+	if label == metav1.NamespaceAll {
+		label = "all"
+	}
+	if t.filterText != "" {
+		label += " /" + shortLabel(t.filterText, 12)
+	}
+	return label
+}
+
+// tabBarText renders the tab strip, highlighting the active tab.
+func tabBarText(tabs []tabView, active int) string {
+	parts := make([]string, 0, len(tabs))
+	for i, tab := range tabs {
+		text := fmt.Sprintf(" %d %s ", i+1, escapeTViewText(tab.label()))
+		if i == active {
+			text = "[black:green:b]" + text + "[-:-:-]"
+		} else {
+			text = "[gray]" + text + "[-]"
+		}
+		parts = append(parts, text)
+	}
+	return strings.Join(parts, " ") + "  [gray]alt+1..9 switch[-]"
+}
+
+// watchNamespaceFor returns the narrowest namespace a single watch can serve all tabs from.
+func watchNamespaceFor(tabs []tabView) string {
+	if len(tabs) == 0 {
+		return metav1.NamespaceAll
+	}
+	var namespaces []string
+	for _, tab := range tabs {
+		if tab.namespace == metav1.NamespaceAll {
+			return metav1.NamespaceAll
+		}
+		namespaces = append(namespaces, tab.namespace)
+	}
+	return kube.JoinNamespaces(namespaces)
+}
+
+func shortLabel(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max-1] + "…"
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a0xAi/kubeve/config"
+)
+
+// backgroundQueryTimeout bounds how long startup waits for the terminal to report its
+// background color. Most terminals answer within a few milliseconds, also over SSH.
+const backgroundQueryTimeout = 300 * time.Millisecond
+
+// backgroundQuery asks for the background color (OSC 11) and then for the device
+// attributes (DA1). Every terminal answers DA1, and after OSC 11 if it supports that,
+// so the DA1 reply ends the wait on terminals that ignore the color query.
+const backgroundQuery = "\x1b]11;?\x1b\\\x1b[c"
+
+var deviceAttributesReply = regexp.MustCompile(`\x1b\[\?[0-9;]*c`)
+
+// detectTheme returns the built-in theme for the terminal's background color, the dark
+// one when the terminal does not tell.
+func detectTheme() config.Theme {
+	return config.ThemeForBackground(terminalIsLight())
+}
+
+// terminalIsLight reports whether the controlling terminal has a light background.
+func terminalIsLight() bool {
+	tty, err := openTty()
+	if err != nil {
+		return false
+	}
+	defer tty.Close()
+	if err := tty.Start(); err != nil {
+		return false
+	}
+	defer tty.Stop()
+	if _, err := tty.Write([]byte(backgroundQuery)); err != nil {
+		return false
+	}
+
+	replies := make(chan []byte, 1)
+	go func() {
+		var reply []byte
+		chunk := make([]byte, 256)
+		for {
+			n, err := tty.Read(chunk)
+			reply = append(reply, chunk[:n]...)
+			if err != nil || n == 0 || deviceAttributesReply.Match(reply) || len(reply) > 1024 {
+				replies <- reply
+				return
+			}
+		}
+	}()
+	var reply []byte
+	select {
+	case reply = <-replies:
+	case <-time.After(backgroundQueryTimeout):
+		// Unblocks the read; whatever arrived so far may still hold the color.
+		_ = tty.Drain()
+		reply = <-replies
+	}
+	light, _ := parseBackgroundReply(reply)
+	return light
+}
+
+// parseBackgroundReply finds the OSC 11 reply in a terminal's output, e.g.
+// "\x1b]11;rgb:ffff/ffff/dddd\x07", and reports whether the color is light. ok is false
+// when there is no reply or it does not parse.
+func parseBackgroundReply(reply []byte) (light, ok bool) {
+	_, rest, found := bytes.Cut(reply, []byte("\x1b]11;"))
+	if !found {
+		return false, false
+	}
+	if end := bytes.IndexAny(rest, "\x07\x1b"); end >= 0 {
+		rest = rest[:end]
+	}
+	spec, found := strings.CutPrefix(string(rest), "rgb:")
+	if !found {
+		spec, found = strings.CutPrefix(string(rest), "rgba:")
+	}
+	fields := strings.Split(spec, "/")
+	if !found || len(fields) < 3 {
+		return false, false
+	}
+	var rgb [3]float64
+	for i := range rgb {
+		// Each component has 1 to 4 hex digits, scaled to its own maximum.
+		field := fields[i]
+		value, err := strconv.ParseUint(field, 16, 16)
+		if err != nil || len(field) == 0 || len(field) > 4 {
+			return false, false
+		}
+		rgb[i] = float64(value) / float64(uint64(1)<<(4*len(field))-1)
+	}
+	// Brightness with the Rec. 709 weights of the red, green and blue components.
+	luminance := 0.2126*rgb[0] + 0.7152*rgb[1] + 0.0722*rgb[2]
+	return luminance > 0.5, true
+}
@@ -0,0 +1,25 @@
+package ui
+
+import "testing"
+
+func TestParseBackgroundReply(t *testing.T) {
+	cases := []struct {
+		name      string
+		reply     string
+		light, ok bool
+	}{
+		{"xterm white, BEL", "\x1b]11;rgb:ffff/ffff/ffff\x07\x1b[?62;22c", true, true},
+		{"solarized light, ST", "\x1b]11;rgb:fdfd/f6f6/e3e3\x1b\\\x1b[?1;2c", true, true},
+		{"black", "\x1b]11;rgb:0000/0000/0000\x1b\\", false, true},
+		{"two digit components", "\x1b]11;rgb:00/2b/36\x07", false, true},
+		{"rgba", "\x1b]11;rgba:eeee/eeee/eeee/ffff\x07", true, true},
+		{"no color reply", "\x1b[?62;22c", false, false},
+		{"garbled", "\x1b]11;rgb:zz/00/00\x07", false, false},
+	}
+	for _, tc := range cases {
+		light, ok := parseBackgroundReply([]byte(tc.reply))
+		if light != tc.light || ok != tc.ok {
+			t.Errorf("%s: got light=%v ok=%v, want light=%v ok=%v", tc.name, light, ok, tc.light, tc.ok)
+		}
+	}
+}
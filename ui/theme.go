@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/a0xAi/kubeve/config"
+	"github.com/gdamore/tcell/v2"
+)
+
+// parseThemeColors resolves a theme's colors, falling back to black on white for
+// colors that do not parse. The first parse error is returned so it can be shown.
+func parseThemeColors(theme config.Theme) (tcell.Color, tcell.Color, error) {
+	bg, bgErr := parseColor(theme.BackgroundColor)
+	if bgErr != nil {
+		bg = tcell.ColorBlack
+		bgErr = fmt.Errorf("backgroundColor: %w", bgErr)
+	}
+	text, textErr := parseColor(theme.TextColor)
+	if textErr != nil {
+		text = tcell.ColorWhite
+		textErr = fmt.Errorf("textColor: %w", textErr)
+	}
+	if bgErr != nil {
+		return bg, text, bgErr
+	}
+	return bg, text, textErr
+}
+
+// parseColor accepts #rgb and #rrggbb hex colors, W3C/X11 color names such as
+// "navy" and 256-color palette indexes written as "208" or "color208".
+func parseColor(raw string) (tcell.Color, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	switch {
+	case value == "":
+		return tcell.ColorDefault, fmt.Errorf("empty color")
+	case strings.HasPrefix(value, "#"):
+		return parseHexColor(value[1:])
+	}
+
+	index := strings.TrimPrefix(value, "color")
+	if n, err := strconv.Atoi(index); err == nil {
+		if n < 0 || n > 255 {
+			return tcell.ColorDefault, fmt.Errorf("palette index %d out of range 0-255", n)
+		}
+		return tcell.PaletteColor(n), nil
+	}
+	if color, ok := tcell.ColorNames[value]; ok {
+		return color, nil
+	}
+	if isHexDigits(value) && (len(value) == 3 || len(value) == 6) {
+		return parseHexColor(value)
+	}
+	return tcell.ColorDefault, fmt.Errorf("unknown color %q (use #rrggbb, a color name or a 0-255 palette index)", raw)
+}
+
+func parseHexColor(value string) (tcell.Color, error) {
+	if !isHexDigits(value) {
+		return tcell.ColorDefault, fmt.Errorf("invalid hex color #%s", value)
+	}
+	switch len(value) {
+	case 3:
+		// #abc is shorthand for #aabbcc.
+		value = string([]byte{value[0], value[0], value[1], value[1], value[2], value[2]})
+	case 6:
+	default:
+		return tcell.ColorDefault, fmt.Errorf("hex color #%s must have 3 or 6 digits", value)
+	}
+	rgb, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return tcell.ColorDefault, fmt.Errorf("invalid hex color #%s", value)
+	}
+	return tcell.NewRGBColor(int32(rgb>>16&0xff), int32(rgb>>8&0xff), int32(rgb&0xff)), nil
+}
+
+func isHexDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
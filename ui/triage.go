@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/a0xAi/kubeve/kube"
+)
+
+// triageSnooze is how long a snoozed warning stays out of the triage queue.
+const triageSnooze = 30 * time.Minute
+
+// triageItem is a warning waiting for review, shown through its latest row.
+type triageItem struct {
+	fingerprint string
+	row         tableRow
+}
+
+// triageState remembers which warnings were acknowledged or snoozed during the session.
+// Warnings are keyed by event fingerprint, so repeats of an acknowledged warning stay
+// acknowledged.
+type triageState struct {
+	acked   map[string]bool
+	snoozed map[string]time.Time
+}
+
+func newTriageState() *triageState {
+	return &triageState{
+		acked:   make(map[string]bool),
+		snoozed: make(map[string]time.Time),
+	}
+}
+
+func (t *triageState) ack(fingerprint string) {
+	t.acked[fingerprint] = true
+	delete(t.snoozed, fingerprint)
+}
+
+func (t *triageState) snooze(fingerprint string, now time.Time) {
+	t.snoozed[fingerprint] = now.Add(triageSnooze)
+}
+
+// pending returns the unreviewed warnings among events, one per fingerprint, in the
+// order they first appeared, which is oldest first. Only events of namespaces in scope
+// are considered.
+func (t *triageState) pending(events []streamEvent, scope string, now time.Time) []triageItem {
+	var items []triageItem
+	index := make(map[string]int)
+	for _, entry := range events {
+		if entry.event.Type != "Warning" || !kube.InNamespaces(scope, entry.event.Namespace) {
+			continue
+		}
+		fingerprint := entry.event.Fingerprint
+		if t.acked[fingerprint] || now.Before(t.snoozed[fingerprint]) {
+			continue
+		}
+		// Keep the place of the first occurrence but show the latest one.
+		if at, ok := index[fingerprint]; ok {
+			items[at].row = entry.row()
+			continue
+		}
+		index[fingerprint] = len(items)
+		items = append(items, triageItem{fingerprint: fingerprint, row: entry.row()})
+	}
+	return items
+}
@@ -3,46 +3,156 @@ package ui
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/a0xAi/kubeve/analysis"
+	"github.com/a0xAi/kubeve/archive"
+	"github.com/a0xAi/kubeve/audit"
 	"github.com/a0xAi/kubeve/config"
+	"github.com/a0xAi/kubeve/internal/format"
 	"github.com/a0xAi/kubeve/kube"
+	"github.com/a0xAi/kubeve/kube/client"
+	"github.com/a0xAi/kubeve/kube/drilldown"
+	"github.com/a0xAi/kubeve/kube/watch"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
 )
 
-func StartUI(version string, overrideNamespace string) {
+// StartOptions carries command line settings into the UI.
+type StartOptions struct {
+	Namespace string
+	// For scopes the stream to an object and its descendants, e.g. deployment/foo.
+	For string
+	// Record writes the session to this path as an asciinema v2 cast.
+	Record string
+	// AuditLog is a JSON audit log file whose entries are shown alongside events.
+	AuditLog    string
+	AuditFilter audit.Filter
+	// Watch is where events come from: the kubeconfig contexts to watch, whose events are
+	// merged into one stream with a CLUSTER column when there are several, and the
+	// filters, list limit and backfill of the watch, which can be changed while the UI runs.
+	Watch watch.Source
+	// StatusChanges watches Pods and Deployments and adds rows for their status
+	// transitions, which often produce no Event object.
+	StatusChanges bool
+	// Screen replaces the terminal, e.g. with a tcell.SimulationScreen in tests.
+	Screen tcell.Screen
+}
+
+const scopeRefreshInterval = 15 * time.Second
+
+func StartUI(version string, opts StartOptions) {
+	// client-go logs watch errors to stderr, which would draw over the terminal UI.
+	klog.LogToStderr(false)
+	klog.SetOutput(io.Discard)
+
+	overrideNamespace := opts.Namespace
 	var filterText string
-	var allEvents []string
-	var visibleEvents []string
+	var allEvents []streamEvent
+	// eventIndex maps event UIDs to their entry in allEvents, so repeats update it.
+	eventIndex := make(map[string]int)
+	triage := newTriageState()
+	notes := newSessionNotes()
+	var visibleEvents []tableRow
+	// visibleSources holds the index in allEvents of each visible event; it is nil in
+	// aggregate mode, where a row stands for several events.
+	var visibleSources []int
 	var rowToVisibleEvent []int
 	var recentNamespaces []string
 	var header *Header
-	var watchCancel context.CancelFunc
-	var watchGeneration int
+	var authModalOpen bool
+	mutedObjects := make(map[string]bool)
+	var scopeRoot drilldown.ObjectRef
+	var scopeTree map[drilldown.ObjectRef]bool
+	var scopeNamespace string
+	var scopeCancel context.CancelFunc
+	stormTalker := ""
+	// goneNamespaces holds namespaces deleted (true) or being deleted (false) since start.
+	goneNamespaces := make(map[string]bool)
+	// deniedNamespaces holds listed namespaces whose events cannot be read, as probed in
+	// the background; the namespace picker marks or hides them.
+	deniedNamespaces := make(map[string]bool)
+	var tabs []tabView
+	activeTab := 0
+	var eventArchive *archive.Archive
+	var archiveCancel func()
+	var statusCancel context.CancelFunc
+	defer func() {
+		if statusCancel != nil {
+			statusCancel()
+		}
+	}()
+	retentionNotice := ""
+	restrictedCount := 0
+	watchStatus := kube.WatchStatus{Connected: true}
 	var bgCol tcell.Color
 	var textCol tcell.Color
 	cfg := config.Load()
 	currentTheme := config.ResolveTheme(cfg.Theme)
-	bgCol, textCol = parseThemeColors(currentTheme)
+	if currentTheme.Name == config.AutoTheme {
+		// Queried before the screen takes over the terminal; a simulated screen has none.
+		currentTheme = config.ThemeForBackground(false)
+		if opts.Screen == nil {
+			currentTheme = detectTheme()
+		}
+	}
+	bgCol, textCol, themeErr := parseThemeColors(currentTheme)
+	themePreview := ""
 
-	namespace, rawConfig, kubeClient, namespaceList, err := kube.Kinit(overrideNamespace)
+	namespace, rawConfig, kubeClient, namespaceList, err := client.Kinit(overrideNamespace)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing Kubernetes: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error initializing Kubernetes: %v\nRun `kubeve doctor` for diagnostics.\n", err)
 		os.Exit(1)
 	}
 	currentContext := rawConfig.CurrentContext
-	ctxConfig := rawConfig.Contexts[currentContext]
-	clusterName := ctxConfig.Cluster
+	clusterName := "in-cluster"
+	if ctxConfig, ok := rawConfig.Contexts[currentContext]; ok && ctxConfig != nil {
+		clusterName = ctxConfig.Cluster
+	}
+	archiveName := currentContext
+	if archiveName == "" {
+		archiveName = clusterName
+	}
+	// With several contexts each event carries the context it came from, and is resolved
+	// and drilled into with that context's client.
+	contexts := opts.Watch.Contexts
+	multiCluster := len(contexts) > 1
+	bands := clusterBands(contexts)
+	clients := make(map[string]*kubernetes.Clientset, len(contexts))
+	if multiCluster {
+		for _, name := range contexts {
+			client, err := client.ContextClient(name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error initializing Kubernetes context %s: %v\nRun `kubeve doctor` for diagnostics.\n", name, err)
+				os.Exit(1)
+			}
+			clients[name] = client
+		}
+		clusterName = strings.Join(contexts, ", ")
+	}
+	clientOf := func(cluster string) *kubernetes.Clientset {
+		if client, ok := clients[cluster]; ok {
+			return client
+		}
+		return kubeClient
+	}
+	clientForRow := func(row tableRow) *kubernetes.Clientset {
+		return clientOf(row.event.Cluster)
+	}
+
 	showTimestampColumn := true
 	autoScroll := true
-	showNamespaceColumn := (namespace == metav1.NamespaceAll)
+	showNamespaceColumn := len(kube.SplitNamespaces(namespace)) != 1
 	showStatusColumn := true
 	showActionColumn := true
 	showResourceColumn := true
@@ -50,40 +160,99 @@ func StartUI(version string, overrideNamespace string) {
 	wrapMessages := false
 	filterVisible := false
 
-	versionInfo, verErr := kubeClient.Discovery().ServerVersion()
-	if verErr != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching server version: %v\n", verErr)
-		os.Exit(1)
+	// The server version is only shown in the header, so it is fetched once the UI runs:
+	// a slow or air-gapped endpoint must not hold up the start.
+	versionInfo := &k8sversion.Info{GitVersion: "checking..."}
+	if cfg.Connection.SkipVersionCheck {
+		versionInfo.GitVersion = "not checked"
 	}
 
 	app := tview.NewApplication()
+	// source is changed by the watch settings and commands; restartWatch applies it.
+	source := opts.Watch
+	hub := watch.NewHub(source)
+	watches := watch.NewManager(hub)
+	var recorder *castRecorder
+	if opts.Record != "" {
+		screen, rec, recErr := newRecordingScreen(opts.Record)
+		if recErr != nil {
+			fmt.Fprintf(os.Stderr, "Error starting session recording: %v\n", recErr)
+			os.Exit(1)
+		}
+		recorder = rec
+		app.SetScreen(screen)
+	} else if opts.Screen != nil {
+		app.SetScreen(opts.Screen)
+	}
 	tview.Styles.PrimitiveBackgroundColor = bgCol
 	tview.Styles.ContrastBackgroundColor = bgCol
 	tview.Styles.PrimaryTextColor = textCol
 
 	app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
 		screen.Clear()
-		return false
+		return drawTooSmall(screen)
 	})
+	app.EnableMouse(cfg.Flags.Mouse)
 	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	padX, padY := cfg.Layout.FramePadding()
 	frame := tview.NewFrame(nil).
-		SetBorders(1, 1, 1, 1, 1, 1)
+		SetBorders(padY, padY, 0, 0, padX, padX)
 
 	frame.SetPrimitive(flex)
 
+	keys, keyErrs := NewKeymap(cfg.Keys)
 	header = NewHeader(
 		clusterName,
 		namespace,
 		versionInfo.GitVersion,
 		recentNamespaces,
 		cfg.Flags.DisableLogo,
+		cfg.Flags.Logo,
+		keys,
 	)
+	headerVisible := !cfg.Flags.HideHeader
 
 	table := NewTable(" [::b][green]Autoscroll ✓ ")
+	preview := newRowPreview()
+	rowActions := newRowActionMenu()
+	// previewPinned is set while p keeps the preview open; it then follows the selection
+	// instead of the mouse.
+	previewPinned := false
+	app.SetAfterDrawFunc(func(screen tcell.Screen) {
+		// Modals and the filter take the focus; the preview only floats over the table.
+		if table.HasFocus() {
+			preview.draw(screen, table)
+			rowActions.draw(screen, table)
+		}
+	})
+
+	dictionary, dictionaryErrs := newEventDictionary(cfg.Dictionary)
+	// sorting orders the rows; nil keeps arrival order, or the noisiest groups first.
+	sorting, sortErr := parseSortOrder(cfg.Flags.Sort)
+	analyzer := analysis.New(cfg.Analysis)
+
+	incarnations := newIncarnationTracker()
+	revisions := watch.NewRevisionResolver(kubeClient)
+	scales := watch.NewScaleTracker(kubeClient)
+	defer scales.Stop()
+	// The storm and deleted-row settings can be changed with :set for the session; they
+	// live outside cfg so saving the theme or archive setting does not persist them.
+	storms := newStormDetector(time.Duration(cfg.Noise.StormWindowSeconds) * time.Second)
+	stormThreshold := cfg.Noise.StormThreshold
+	dropDeleted := cfg.Flags.DropDeleted
+	stormBanner := tview.NewTextView().SetDynamicColors(true)
+	namespaceBanner := tview.NewTextView().SetDynamicColors(true)
+	// DNS failures are counted under one key: many apps failing lookups at once points
+	// at cluster DNS rather than at any of them.
+	dnsFailures := newStormDetector(dnsFailureWindow)
+	restarts := newRestartTracker()
+	changes := newCellChanges()
+	tabBar := tview.NewTextView().SetDynamicColors(true)
 
 	currentColumns := func() ColumnOptions {
 		return ColumnOptions{
 			Timestamp: showTimestampColumn,
+			Cluster:   multiCluster,
 			Namespace: showNamespaceColumn,
 			Status:    showStatusColumn,
 			Action:    showActionColumn,
@@ -94,8 +263,19 @@ func StartUI(version string, overrideNamespace string) {
 
 	updateTableTitle := func() {
 		filterTableText := ""
+		if !headerVisible {
+			// The header is gone, so the title carries where we are.
+			namespaceText := namespace
+			if namespace == metav1.NamespaceAll {
+				namespaceText = "all"
+			}
+			filterTableText = fmt.Sprintf("[yellow]%s/%s ", clusterName, namespaceText)
+		}
 		if filterText != "" {
-			filterTableText = "[yellow] [Filter: " + filterText + "]"
+			filterTableText += "[yellow] [Filter: " + filterText + "]"
+		}
+		if scopeTree != nil {
+			filterTableText += fmt.Sprintf("[cyan] [For: %s (%d objects)]", scopeRoot, len(scopeTree))
 		}
 		aggregateTableText := "[gray]Raw"
 		if aggregateMode {
@@ -105,11 +285,68 @@ func StartUI(version string, overrideNamespace string) {
 		if wrapMessages {
 			wrapTableText = "[cyan]Wrap"
 		}
+		if len(sorting) > 0 {
+			wrapTableText += " [cyan]Sort:" + sorting.String()
+		}
 		themeLabel := currentTheme.Name
 		if themeLabel == "" {
 			themeLabel = "custom"
 		}
+		if cfg.Theme.Name == config.AutoTheme {
+			themeLabel += " (auto)"
+		}
 		themeTableText := "[gray]Theme:" + themeLabel
+		if themePreview != "" {
+			themeTableText += " [yellow](previewing " + themePreview + ", :theme <name> to keep)"
+		}
+		if len(mutedObjects) > 0 {
+			themeTableText += fmt.Sprintf(" [gray]Muted:%d", len(mutedObjects))
+		}
+		if len(namespaceList) == 0 {
+			themeTableText += " [yellow]Namespace list unavailable"
+		}
+		if restrictedCount > 0 {
+			themeTableText += fmt.Sprintf(" [yellow]Limited context: %s not permitted", strings.Join(drilldown.RestrictedActions(), ", "))
+		}
+		if retentionNotice != "" {
+			themeTableText += " " + retentionNotice
+		}
+		if !watchStatus.Connected {
+			reconnect := fmt.Sprintf(" [red::b]Disconnected, reconnecting (attempt %d)[-:-:-]", watchStatus.Attempt)
+			switch watch.ClassifyError(watchStatus.Err) {
+			case watch.ErrorClassThrottled:
+				reconnect = fmt.Sprintf(" [yellow::b]Throttled by the API server, backing off (attempt %d)[-:-:-]", watchStatus.Attempt)
+			case watch.ErrorClassGone:
+				reconnect = " [yellow::b]Watch expired, relisting[-:-:-]"
+			case watch.ErrorClassNetwork:
+				reconnect = fmt.Sprintf(" [red::b]Connection lost, reconnecting (attempt %d)[-:-:-]", watchStatus.Attempt)
+			}
+			if watchStatus.Stopped {
+				reconnect = fmt.Sprintf(" [red::b]Stream stopped (%s)[-:-:-]", watchStatus.State())
+			}
+			if watchStatus.Err != nil {
+				reconnect += "[red] " + escapeTViewText(watchStatus.Err.Error())
+			}
+			themeTableText += reconnect
+		}
+		if watchStatus.Listing {
+			themeTableText += " [yellow]Loading events: " + format.Count(int64(watchStatus.Listed))
+		}
+		if source.WarningsOnly {
+			themeTableText += " [yellow]Warnings only"
+		}
+		if selector := source.FieldSelector; selector != "" {
+			themeTableText += " [yellow]Fields: " + escapeTViewText(selector)
+		}
+		if _, count := dnsFailures.top(time.Now()); count >= dnsFailureThreshold {
+			themeTableText += fmt.Sprintf(" [red]DNS failures: %d, :dns to check CoreDNS", count)
+		}
+		if top, ok := restarts.top(); ok && top.session >= restartAlertThreshold {
+			themeTableText += fmt.Sprintf(" [red]Crash looping: %s (%d restarts), :restarts", escapeTViewText(top.name()), top.session)
+		}
+		if client.InsecureTLS() {
+			themeTableText += " [red::b]TLS VERIFY OFF[-:-:-]"
+		}
 		if autoScroll {
 			table.SetTitle("[::b]" + filterTableText + "[green]Autoscroll ✓ " + aggregateTableText + " " + wrapTableText + " " + themeTableText)
 		} else {
@@ -117,25 +354,401 @@ func StartUI(version string, overrideNamespace string) {
 		}
 	}
 
+	// changeKey identifies the row of visible event idx across updates: the group of an
+	// aggregated row, the event's UID otherwise.
+	changeKey := func(idx int) string {
+		if aggregateMode {
+			row := visibleEvents[idx]
+			return row.scope() + "|" + row.resource() + "|" + row.event.Reason
+		}
+		if idx >= len(visibleSources) || allEvents[visibleSources[idx]].event.UID == "" {
+			return ""
+		}
+		return "uid:" + string(allEvents[visibleSources[idx]].event.UID)
+	}
+
+	// paintRowChanges highlights the recently changed cells of the table's rows and
+	// clears the highlights that ran out.
+	paintRowChanges := func() {
+		now := time.Now()
+		columns := currentColumns()
+		for row, visible := range rowToVisibleEvent {
+			if key := changeKey(visible); key != "" {
+				paintChanges(table, row+1, changes.active(key, now), columns)
+			}
+		}
+	}
+
+	// fadeRowChanges clears the highlights of a change once they ran out.
+	fadeRowChanges := func() {
+		time.AfterFunc(changeHighlightDuration, func() {
+			app.QueueUpdateDraw(paintRowChanges)
+		})
+	}
+
 	refreshTable := func() {
-		displayEvents := allEvents
+		// The watch may cover more namespaces than this tab shows.
 		if aggregateMode {
-			displayEvents = aggregateEvents(allEvents)
+			rows, _ := streamRows(allEvents, namespace, "")
+			visibleEvents = filterRows(aggregateEventsBy(rows, sorting), filterText)
+			visibleSources = nil
+		} else {
+			visibleEvents, visibleSources = streamRows(allEvents, namespace, filterText)
+			sortStreamRows(allEvents, visibleEvents, visibleSources, sorting)
 		}
-		visibleEvents = filterEvents(displayEvents, filterText)
 		_, _, tableWidth, _ := table.GetInnerRect()
 		rowToVisibleEvent = renderTable(table, visibleEvents, "", currentColumns(), wrapMessages, tableWidth)
+		if aggregateMode {
+			changed := false
+			now := time.Now()
+			for idx, row := range visibleEvents {
+				if changes.note(changeKey(idx), row, now) {
+					changed = true
+				}
+			}
+			if changed {
+				fadeRowChanges()
+			}
+		}
+		paintRowChanges()
 	}
 
-	var updateNamespace func(string)
+	updateStormBanner := func() {
+		talker, count := storms.top(time.Now())
+		if count < stormThreshold {
+			if stormTalker != "" {
+				stormTalker = ""
+				flex.ResizeItem(stormBanner, 0, 0)
+			}
+			return
+		}
+		stormTalker = talker
+		stormBanner.SetText(fmt.Sprintf(
+			"[black:yellow:b] ⚠ Event storm: %s produced %s events in the last %s. Press M to mute it. [-:-:-]",
+			escapeTViewText(talker), format.Count(int64(count)), format.Duration(storms.window),
+		))
+		flex.ResizeItem(stormBanner, 1, 0)
+	}
 
-	updateNamespace = func(newNS string) {
-		if watchCancel != nil {
-			watchCancel()
+	// updateNamespaceBanner warns while a namespace of the scope is being or was deleted;
+	// its watch stays up but will not see new events.
+	updateNamespaceBanner := func() {
+		text := ""
+		for _, ns := range kube.SplitNamespaces(namespace) {
+			deleted, gone := goneNamespaces[ns]
+			if !gone || text != "" && !deleted {
+				continue
+			}
+			status := "[black:yellow:b] ⚠ Namespace %s is being deleted."
+			if deleted {
+				status = "[white:red:b] ✗ Namespace %s was deleted."
+			}
+			text = fmt.Sprintf(status+" Press 0 for all namespaces or ctrl+n to pick another. [-:-:-]", escapeTViewText(ns))
+			if deleted {
+				break
+			}
+		}
+		if text == "" {
+			flex.ResizeItem(namespaceBanner, 0, 0)
+			return
+		}
+		namespaceBanner.SetText(text)
+		flex.ResizeItem(namespaceBanner, 1, 0)
+	}
+
+	muteTopTalker := func() bool {
+		if stormTalker == "" {
+			return false
+		}
+		mutedObjects[stormTalker] = true
+		storms.forget(stormTalker)
+		updateStormBanner()
+		updateTableTitle()
+		return true
+	}
+
+	// lookupRestarts reads the restart count of an event's pod for the restarts panel.
+	lookupRestarts := func(event kube.Event) {
+		now := time.Now()
+		if event.Reason == "BackOff" {
+			restarts.noteBackOff(event.Cluster, event.Namespace, event.Name)
+		}
+		if !restarts.wantsLookup(event.Cluster, event.Namespace, event.Name, now) {
+			return
+		}
+		client := clientOf(event.Cluster)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			workload, count, err := watch.PodRestarts(ctx, client, event.Namespace, event.Name)
+			cancel()
+			app.QueueUpdateDraw(func() {
+				if err != nil {
+					restarts.failed(event.Cluster, event.Namespace, event.Name, time.Now())
+					return
+				}
+				restarts.update(event.Cluster, event.Namespace, event.Name, workload, count, time.Now())
+				updateTableTitle()
+			})
+		}()
+	}
+
+	// admitEvent applies the scope and mutes to an event and feeds the storm, DNS and
+	// restart detectors. It returns the event's object key, or false when the event is
+	// hidden.
+	admitEvent := func(event kube.Event) (string, bool) {
+		if scopeTree != nil && (event.Namespace != scopeNamespace ||
+			!scopeTree[drilldown.ObjectRef{Kind: event.Kind, Name: event.Name}]) {
+			return "", false
+		}
+		objectKey := fmt.Sprintf("%s/%s/%s", event.Namespace, event.Kind, event.Name)
+		if mutedObjects[objectKey] {
+			return "", false
+		}
+		storms.observe(objectKey, event.Time, time.Now())
+		updateStormBanner()
+
+		if drilldown.IsDNSFailure(event.Message) {
+			dnsFailures.observe("dns", event.Time, time.Now())
+			if _, count := dnsFailures.top(time.Now()); count == dnsFailureThreshold {
+				updateTableTitle()
+			}
+		}
+		if event.Kind == "Pod" && restartReasons[event.Reason] && time.Since(event.Time) <= restartEventWindow && clientOf(event.Cluster) != nil {
+			lookupRestarts(event)
+		}
+		return objectKey, true
+	}
+
+	// redrawEvent rewrites the row of allEvents[idx] in place after it changed.
+	redrawEvent := func(idx int) {
+		changed := allEvents[idx].row()
+		// A changed event may move to another row when the table is sorted.
+		if aggregateMode || wrapMessages || len(sorting) > 0 || !matchesFilter(changed, filterText) {
+			refreshTable()
+			return
+		}
+		for row, visible := range rowToVisibleEvent {
+			if visible >= len(visibleSources) || visibleSources[visible] != idx {
+				continue
+			}
+			visibleEvents[visible] = changed
+			renderRow(table, row+1, changed, currentColumns())
+			paintChanges(table, row+1, changes.active(changeKey(visible), time.Now()), currentColumns())
+		}
+	}
+
+	// updateEvent rewrites the row of allEvents[idx] with a repeat of its event, e.g. with
+	// a higher count and a later last-seen time.
+	updateEvent := func(idx int, event kube.Event) {
+		key := "uid:" + string(event.UID)
+		now := time.Now()
+		changes.note(key, allEvents[idx].row(), now)
+		allEvents[idx].update(event)
+		if changes.note(key, allEvents[idx].row(), now) {
+			fadeRowChanges()
+		}
+		redrawEvent(idx)
+	}
+
+	// annotateEvent puts the rollout details of an event, resolved after it was added, on
+	// its row, unless the row shows a later occurrence by now.
+	annotateEvent := func(event kube.Event) {
+		idx, ok := eventIndex[event.UID]
+		if !ok || event.UID == "" {
+			return
+		}
+		shown := allEvents[idx].event
+		if shown.Count != event.Count || !shown.Time.Equal(event.Time) || shown.Message == event.Message {
+			return
+		}
+		updateEvent(idx, event)
+	}
+
+	// addEvent appends an event from any source to the stream. An event whose UID already
+	// has a row updates that row instead. It runs on the UI goroutine.
+	addEvent := func(event kube.Event) {
+		idx, seen := eventIndex[event.UID]
+		seen = seen && event.UID != ""
+		if seen && !allEvents[idx].repeatedBy(event) {
+			// A re-list or replay of an occurrence the row already shows.
+			return
+		}
+		objectKey, ok := admitEvent(event)
+		if !ok {
+			return
+		}
+		if seen {
+			if autoScroll {
+				updateEvent(idx, event)
+			}
+			return
+		}
+
+		previous, replaced := incarnations.observe(objectKey, event.ObjectUID, event.Time)
+
+		entry := newStreamEvent(event, previous)
+		entry.setNoted(notes.get(event.Fingerprint) != "")
+		entry.band = bands[event.Cluster]
+
+		if autoScroll {
+			// A re-created object makes the rows of its predecessor history.
+			if replaced != "" {
+				for i := range allEvents {
+					if allEvents[i].event.ObjectUID == replaced {
+						allEvents[i].markPrevious()
+					}
+				}
+			}
+			allEvents = append(allEvents, entry)
+			eventIndex[event.UID] = len(allEvents) - 1
+			if aggregateMode || wrapMessages || len(sorting) > 0 || replaced != "" {
+				refreshTable()
+				if aggregateMode && table.GetRowCount() > 1 {
+					table.ScrollToBeginning()
+					table.Select(1, 0)
+				} else if table.GetRowCount() > 1 {
+					table.ScrollToEnd()
+					table.Select(table.GetRowCount()-1, 0)
+				}
+			} else {
+				if added := entry.row(); matchesFilter(added, filterText) &&
+					kube.InNamespaces(namespace, event.Namespace) {
+					visibleEvents = append(visibleEvents, added)
+					visibleSources = append(visibleSources, len(allEvents)-1)
+					row := table.GetRowCount()
+					renderRow(table, row, added, currentColumns())
+					rowToVisibleEvent = append(rowToVisibleEvent, len(visibleEvents)-1)
+					table.ScrollToEnd()
+					table.Select(table.GetRowCount()-1, 0)
+				}
+			}
+		}
+	}
+
+	// deleteEvent greys out the row of an event the cluster deleted, usually because it
+	// expired, so it stays as history; with dropDeleted set the row is removed instead.
+	deleteEvent := func(event kube.Event) {
+		idx, ok := eventIndex[event.UID]
+		if !ok || event.UID == "" || !autoScroll {
+			return
+		}
+		if dropDeleted {
+			allEvents[idx].hidden = true
+			refreshTable()
+			return
+		}
+		allEvents[idx].markDeleted()
+		redrawEvent(idx)
+	}
+
+	refreshInfo := func() {
+		clusterText := clusterName
+		if multiCluster {
+			clusterText = clusterStatusText(contexts, watchStatus.Contexts)
+		}
+		header.InfoView.SetText(infoText(clusterText, namespace, versionInfo.GitVersion, version, streamStatusText(watchStatus)))
+	}
+
+	hub.OnStatus(func(ns string, status kube.WatchStatus) {
+		app.QueueUpdateDraw(func() {
+			if !watches.Running() || ns != watches.Namespace() {
+				return
+			}
+			watchStatus = status
+			updateTableTitle()
+			refreshInfo()
+		})
+	})
+
+	// archiveEvent writes an event to arc, with a snapshot of its involved object when
+	// snapshots are enabled. It runs on the archive subscriber's own goroutine.
+	archiveEvent := func(arc *archive.Archive, event kube.Event) {
+		record := archive.Record{Event: event}
+		if cfg.Archive.Snapshots && arc.WantsSnapshot(event, time.Now()) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if snapshot, err := drilldown.ObjectSnapshot(ctx, clientOf(event.Cluster), event.Namespace, event.Kind, event.Name); err == nil {
+				record.Snapshot = snapshot
+			}
+			cancel()
+		}
+		_ = arc.AppendRecord(record)
+	}
+
+	renderRecentNamespaces := func() {
+		recentLines := []string{"[blue]<0> [white]All Namespaces"}
+		for i, ns := range recentNamespaces {
+			recentLines = append(recentLines, fmt.Sprintf("[blue]<%d> [white]%s", i+1, ns))
+		}
+		header.RecentNSBox.SetText(strings.Join(recentLines, "\n"))
+	}
+
+	// probeNamespaceAccess finds which of names deny reading events, without holding up
+	// the UI; other namespaces keep their earlier result.
+	probeNamespaceAccess := func(names []string) {
+		if len(names) == 0 {
+			return
+		}
+		go func() {
+			denied := watch.ProbeNamespaceAccess(context.Background(), kubeClient, names)
+			app.QueueUpdateDraw(func() {
+				for _, ns := range names {
+					if denied[ns] {
+						deniedNamespaces[ns] = true
+					} else {
+						delete(deniedNamespaces, ns)
+					}
+				}
+			})
+		}()
+	}
+
+	// onNamespaceChange keeps the namespace list and recent namespaces in step with the
+	// cluster and warns when the scope loses a namespace.
+	onNamespaceChange := func(change watch.NamespaceChange) {
+		switch {
+		case change.Deleted:
+			goneNamespaces[change.Name] = true
+			namespaceList = slices.DeleteFunc(namespaceList, func(ns string) bool { return ns == change.Name })
+			recentNamespaces = slices.DeleteFunc(recentNamespaces, func(ns string) bool { return ns == change.Name })
+			renderRecentNamespaces()
+		case change.Terminating:
+			if _, gone := goneNamespaces[change.Name]; !gone {
+				goneNamespaces[change.Name] = false
+			}
+		default:
+			// A namespace created again under a deleted one's name gets events again.
+			delete(goneNamespaces, change.Name)
+			if len(namespaceList) > 0 && !slices.Contains(namespaceList, change.Name) {
+				namespaceList = append(namespaceList, change.Name)
+				sort.Strings(namespaceList)
+				probeNamespaceAccess([]string{change.Name})
+			}
 		}
-		watchGeneration++
-		currentWatchGeneration := watchGeneration
+		updateNamespaceBanner()
+	}
+
+	// watchStatusChanges adds rows for the Pod and Deployment transitions of scope in
+	// every watched context until the returned func is called.
+	watchStatusChanges := func(scope string) context.CancelFunc {
+		ctx, cancel := context.WithCancel(context.Background())
+		targets := map[string]*kubernetes.Clientset{"": kubeClient}
+		if multiCluster {
+			targets = clients
+		}
+		for cluster, client := range targets {
+			_ = watch.StatusChanges(ctx, client, scope, func(event kube.Event) {
+				event.Cluster = cluster
+				app.QueueUpdateDraw(func() {
+					addEvent(event)
+				})
+			})
+		}
+		return cancel
+	}
 
+	var updateNamespace func(string)
+
+	updateNamespace = func(newNS string) {
 		if newNS == "" {
 			namespace = metav1.NamespaceAll
 		} else {
@@ -155,89 +768,225 @@ func StartUI(version string, overrideNamespace string) {
 				recentNamespaces = recentNamespaces[:3]
 			}
 		}
-		// Refresh RecentNSBox in header
-		var recentLines []string
-		recentLines = append(recentLines, "[blue]<0> [white]All Namespaces")
-		for i, ns := range recentNamespaces {
-			recentLines = append(recentLines, fmt.Sprintf("[blue]<%d> [white]%s", i+1, ns))
+		renderRecentNamespaces()
+		updateNamespaceBanner()
+		refreshInfo()
+		showNamespaceColumn = len(kube.SplitNamespaces(namespace)) != 1
+		updateTableTitle()
+
+		// All tabs share one watch; only restart it when it can no longer serve every tab.
+		wanted := namespace
+		if len(tabs) > 1 {
+			tabs[activeTab].namespace = namespace
+			wanted = watchNamespaceFor(tabs)
+		}
+		if watches.Running() && wanted == watches.Namespace() {
+			refreshTable()
+			return
+		}
+
+		if archiveCancel != nil {
+			archiveCancel()
+			archiveCancel = nil
 		}
-		header.RecentNSBox.SetText(strings.Join(recentLines, "\n"))
-		namespaceText := namespace
-		if namespace == "" {
-			namespaceText = "All namespaces"
-		}
-		header.InfoView.SetText(fmt.Sprintf(
-			"[yellow]Cluster:[-] %s\n"+
-				"[yellow]Namespace:[-] %s\n"+
-				"[yellow]K8s Rev:[-] %s\n"+
-				"[yellow]Kubeve Rev:[-] %s\n",
-			clusterName, namespaceText, versionInfo.GitVersion, version,
-		))
 		allEvents = nil
+		eventIndex = make(map[string]int)
+		changes.reset()
 		visibleEvents = nil
+		visibleSources = nil
 		rowToVisibleEvent = nil
-		showNamespaceColumn = namespace == metav1.NamespaceAll
 		refreshTable()
 
-		watchCtx, cancel := context.WithCancel(context.Background())
-		watchCancel = cancel
+		watchStatus = kube.WatchStatus{Connected: true}
+		refreshInfo()
+		// The archive reaches further back than the cluster's retention; events it
+		// shares with the watch's backfill keep one row.
+		if window := source.Backfill; window > 0 && eventArchive != nil {
+			archived, _ := archive.Read(archiveName, time.Now().Add(-window))
+			for _, event := range archived {
+				if kube.InNamespaces(wanted, event.Namespace) {
+					addEvent(event)
+				}
+			}
+		}
+		watches.Start(wanted)
+		if statusCancel != nil {
+			statusCancel()
+			statusCancel = nil
+		}
+		if opts.StatusChanges {
+			statusCancel = watchStatusChanges(wanted)
+		}
+		if eventArchive != nil {
+			arc := eventArchive
+			archiveCancel = hub.Subscribe(wanted, nil, func(event kube.Event) {
+				archiveEvent(arc, event)
+			}, nil)
+		}
+	}
 
-		go func(ns string, generation int) {
-			err := kube.WatchEvents(watchCtx, ns, func(event *corev1.Event) {
-				app.QueueUpdateDraw(func() {
-					if generation != watchGeneration {
-						return
-					}
+	// reauthenticate resumes after the user renewed their credentials. The server version
+	// and namespace list are fetched again in case they were missed at start.
+	reauthenticate := func() {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), client.StartupTimeout())
+			defer cancel()
+			var info *k8sversion.Info
+			if !cfg.Connection.SkipVersionCheck {
+				info, _ = client.ServerVersion(ctx, kubeClient)
+			}
+			names, nsErr := client.NamespaceNames(ctx, kubeClient)
+			app.QueueUpdateDraw(func() {
+				if info != nil {
+					versionInfo = info
+					refreshInfo()
+				}
+				if nsErr == nil && len(names) > 0 {
+					namespaceList = names
+					updateTableTitle()
+					probeNamespaceAccess(names)
+				}
+			})
+		}()
+		// A watch that has not failed yet may still hold the old credentials' error;
+		// stopping it makes its queued updates stale.
+		watches.Stop()
+		updateNamespace(namespace)
+	}
 
-					resource := fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name)
-					msg := fmt.Sprintf("%-25s │ %-60s │ %-10s │ %-20s │ %-10s │ %s\n",
-						event.LastTimestamp.Time.Format(time.RFC3339),
-						resource,
-						event.Type,
-						event.Reason,
-						event.Namespace,
-						event.Message,
-					)
-
-					if autoScroll {
-						allEvents = append(allEvents, msg)
-						if aggregateMode || wrapMessages {
-							refreshTable()
-							if aggregateMode && table.GetRowCount() > 1 {
-								table.ScrollToBeginning()
-								table.Select(1, 0)
-							} else if table.GetRowCount() > 1 {
-								table.ScrollToEnd()
-								table.Select(table.GetRowCount()-1, 0)
-							}
-						} else {
-							if matchesFilter(msg, filterText) &&
-								(namespace == metav1.NamespaceAll || event.Namespace == namespace) {
-								visibleEvents = append(visibleEvents, msg)
-								parts := strings.SplitN(msg, "│", 6)
-								if len(parts) == 6 {
-									row := table.GetRowCount()
-									renderRow(table, row, parts, currentColumns())
-									rowToVisibleEvent = append(rowToVisibleEvent, len(visibleEvents)-1)
-									table.ScrollToEnd()
-									table.Select(table.GetRowCount()-1, 0)
-								}
+	openAuthModal := func(authErr error) {
+		if authModalOpen {
+			return
+		}
+		authModalOpen = true
+		AuthModal(app, frame, table, authErr, reauthenticate, func() {
+			authModalOpen = false
+		})
+	}
+
+	// resolveCtx ends the rollout lookups still running when the UI stops.
+	resolveCtx, cancelResolve := context.WithCancel(context.Background())
+	defer cancelResolve()
+
+	// The watch manager delivers the changes of whichever namespace is watched on one
+	// channel; this goroutine hands them to the UI goroutine.
+	go func() {
+		for update := range watches.Updates() {
+			// Revisions and replica counts are only tracked in the first context.
+			local := update.Event.Cluster == "" || update.Event.Cluster == currentContext
+			if local && (update.Type == watch.EventAdded || update.Type == watch.EventUpdated) {
+				raw := update.Event
+				var pending bool
+				update.Event, pending = cachedRolloutDetails(raw, revisions, scales)
+				if pending {
+					// A lookup may wait on the API server, so it must not hold up the
+					// stream; the row gets the details as they come in.
+					resolveRolloutDetails(resolveCtx, raw, revisions, scales, func(event kube.Event) {
+						app.QueueUpdateDraw(func() {
+							if watches.IsCurrent(update) {
+								annotateEvent(event)
 							}
-						}
+						})
+					})
+				}
+			}
+			app.QueueUpdateDraw(func() {
+				if !watches.IsCurrent(update) {
+					return
+				}
+				switch update.Type {
+				case watch.EventAdded, watch.EventUpdated:
+					addEvent(update.Event)
+				case watch.EventDeleted:
+					deleteEvent(update.Event)
+				case watch.WatchClosed:
+					if update.Err == nil {
+						return
 					}
-				})
+					// The stream is stale until the watch is restarted.
+					watchStatus = kube.WatchStatus{Err: update.Err, Stopped: true}
+					refreshInfo()
+					updateTableTitle()
+					if kube.IsAuthError(update.Err) {
+						openAuthModal(update.Err)
+					}
+				}
 			})
-			if err != nil {
+		}
+	}()
+
+	// setScope limits the stream to an object and its descendants. The tree is re-resolved
+	// periodically so pods created by later rollouts stay in scope.
+	setScope := func(raw string) string {
+		if scopeCancel != nil {
+			scopeCancel()
+			scopeCancel = nil
+		}
+		if strings.TrimSpace(raw) == "" {
+			if scopeTree == nil {
+				return "No scope set"
+			}
+			scopeTree = nil
+			updateTableTitle()
+			updateNamespace(namespace)
+			return "Scope cleared"
+		}
+		ref, err := drilldown.ParseObjectRef(raw)
+		if err != nil {
+			updateTableTitle()
+			table.SetTitle(fmt.Sprintf("%s [red](%v)", table.GetTitle(), err))
+			return "Invalid scope"
+		}
+		// Ownership trees never cross namespaces; with several, the first one is searched.
+		scopeNs := metav1.NamespaceDefault
+		if namespaces := kube.SplitNamespaces(namespace); len(namespaces) > 0 {
+			scopeNs = namespaces[0]
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		scopeCancel = cancel
+
+		go func() {
+			ticker := time.NewTicker(scopeRefreshInterval)
+			defer ticker.Stop()
+			for first := true; ; first = false {
+				tree, err := drilldown.ResolveDescendants(ctx, kubeClient, scopeNs, ref)
+				if ctx.Err() != nil {
+					return
+				}
+				initial := first
 				app.QueueUpdateDraw(func() {
-					if generation != watchGeneration {
+					if ctx.Err() != nil {
 						return
 					}
-					updateTableTitle()
-					table.SetTitle(fmt.Sprintf("%s [red](watch error: %v)", table.GetTitle(), err))
+					if err != nil {
+						if initial {
+							updateTableTitle()
+							table.SetTitle(fmt.Sprintf("%s [red](scope %s: %v)", table.GetTitle(), ref, err))
+						}
+						return
+					}
+					scopeRoot = ref
+					scopeNamespace = scopeNs
+					scopeTree = tree
+					if initial {
+						updateNamespace(scopeNs)
+					} else {
+						updateTableTitle()
+					}
 				})
+				if err != nil && first {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
 			}
-		}(namespace, currentWatchGeneration)
+		}()
+		return "Resolving scope"
 	}
+
 	filter := NewFilter()
 
 	filterContainer := tview.NewFlex().AddItem(filter, 0, 1, true)
@@ -245,7 +994,7 @@ func StartUI(version string, overrideNamespace string) {
 	filterContainer.SetTitle("Filter").SetTitleAlign(tview.AlignLeft)
 
 	applyTheme := func(theme config.Theme) {
-		bgCol, textCol = parseThemeColors(theme)
+		bgCol, textCol, _ = parseThemeColors(theme)
 		tview.Styles.PrimitiveBackgroundColor = bgCol
 		tview.Styles.ContrastBackgroundColor = bgCol
 		tview.Styles.PrimaryTextColor = textCol
@@ -253,6 +1002,13 @@ func StartUI(version string, overrideNamespace string) {
 		frame.SetBackgroundColor(bgCol)
 		frame.SetBorderColor(textCol)
 		flex.SetBackgroundColor(bgCol)
+		stormBanner.SetBackgroundColor(bgCol)
+		namespaceBanner.SetBackgroundColor(bgCol)
+		tabBar.SetBackgroundColor(bgCol)
+		preview.view.SetBackgroundColor(bgCol)
+		preview.view.SetTextColor(textCol)
+		rowActions.view.SetBackgroundColor(bgCol)
+		rowActions.view.SetTextColor(textCol)
 		filterContainer.SetBackgroundColor(bgCol)
 		filterContainer.SetBorderColor(textCol)
 
@@ -287,6 +1043,7 @@ func StartUI(version string, overrideNamespace string) {
 	themeNames := config.ThemeNames()
 
 	setTheme := func(theme config.Theme) {
+		themePreview = ""
 		currentTheme = config.ResolveTheme(theme)
 		cfg.Theme = currentTheme
 		applyTheme(currentTheme)
@@ -297,7 +1054,7 @@ func StartUI(version string, overrideNamespace string) {
 	}
 
 	openThemeSelector := func() {
-		NamespacesModal(app, frame, table, themeNames, func(themeName string) {
+		NamespacesModal(app, frame, table, themeNames, nil, false, func(themeName string) {
 			theme, ok := config.ThemeByName(themeName)
 			if !ok {
 				return
@@ -341,6 +1098,10 @@ func StartUI(version string, overrideNamespace string) {
 		if strings.EqualFold(query, "all") || query == "*" {
 			return "", true
 		}
+		// Without permission to list namespaces, trust the name as typed.
+		if len(namespaceList) == 0 {
+			return query, true
+		}
 
 		for _, ns := range namespaceList {
 			if strings.EqualFold(ns, query) {
@@ -351,6 +1112,10 @@ func StartUI(version string, overrideNamespace string) {
 		best := ""
 		bestScore := 0
 		for _, ns := range namespaceList {
+			// Fuzzy queries only pick namespaces whose events can be read.
+			if deniedNamespaces[ns] {
+				continue
+			}
 			score, ok := fuzzyMatchScore(query, ns)
 			if ok && score > bestScore {
 				best = ns
@@ -387,8 +1152,36 @@ func StartUI(version string, overrideNamespace string) {
 		return config.ThemeByName(best)
 	}
 
-	toggleAutoScroll := func() {
-		autoScroll = !autoScroll
+	// previewTheme applies a built-in theme or a "<background> <text>" color pair without
+	// saving it; setTheme or the next preview replaces it.
+	previewTheme := func(raw string) string {
+		raw = strings.TrimSpace(raw)
+		theme, ok := resolveTheme(raw)
+		if !ok {
+			colors := strings.Fields(raw)
+			if len(colors) != 2 {
+				updateTableTitle()
+				table.SetTitle(fmt.Sprintf("%s [red](theme preview: expected a theme name or <background> <text>)", table.GetTitle()))
+				return "Nothing to preview"
+			}
+			theme = config.Theme{BackgroundColor: colors[0], TextColor: colors[1]}
+		}
+		if _, _, err := parseThemeColors(theme); err != nil {
+			updateTableTitle()
+			table.SetTitle(fmt.Sprintf("%s [red](theme preview: %v)", table.GetTitle(), err))
+			return "Invalid theme colors"
+		}
+		themePreview = theme.Name
+		if themePreview == "" {
+			themePreview = theme.BackgroundColor + " on " + theme.TextColor
+		}
+		applyTheme(theme)
+		updateTableTitle()
+		return "Previewing theme"
+	}
+
+	toggleAutoScroll := func() {
+		autoScroll = !autoScroll
 		filterText = filter.GetText()
 		updateTableTitle()
 	}
@@ -415,6 +1208,8 @@ func StartUI(version string, overrideNamespace string) {
 
 	toggleAggregate := func() {
 		aggregateMode = !aggregateMode
+		// Aggregated groups start over rather than flash what changed while they were off.
+		changes.reset()
 		updateTableTitle()
 		refreshTable()
 		if aggregateMode && table.GetRowCount() > 1 {
@@ -422,6 +1217,16 @@ func StartUI(version string, overrideNamespace string) {
 		}
 	}
 
+	toggleHeader := func() {
+		headerVisible = !headerVisible
+		if headerVisible {
+			flex.ResizeItem(header.Flex, headerRows, 0)
+		} else {
+			flex.ResizeItem(header.Flex, 0, 0)
+		}
+		updateTableTitle()
+	}
+
 	toggleWrap := func() {
 		wrapMessages = !wrapMessages
 		updateTableTitle()
@@ -438,6 +1243,138 @@ func StartUI(version string, overrideNamespace string) {
 		refreshTable()
 	}
 
+	enableArchive := func() string {
+		if eventArchive != nil {
+			return "Archiving is already enabled"
+		}
+		arc, err := archive.Open(archiveName)
+		if err != nil {
+			updateTableTitle()
+			table.SetTitle(fmt.Sprintf("%s [red](archive: %v)", table.GetTitle(), err))
+			return "Could not open archive"
+		}
+		eventArchive = arc
+		retentionNotice = ""
+		if watches.Running() {
+			archiveCancel = hub.Subscribe(watches.Namespace(), nil, func(event kube.Event) {
+				archiveEvent(arc, event)
+			}, nil)
+		}
+		cfg.Archive.Enabled = true
+		updateTableTitle()
+		if err := config.Save(cfg); err != nil {
+			table.SetTitle(fmt.Sprintf("%s [red](config save error: %v)", table.GetTitle(), err))
+		}
+		return "Archiving events to " + archive.Path(archiveName)
+	}
+
+	annotate := func(row tableRow) *config.Annotation {
+		if found, ok := dictionary.lookup(row.event.Reason, strings.TrimSpace(row.event.Message)); ok {
+			return &found
+		}
+		return nil
+	}
+
+	onDrillDownClosed := func() {
+		if restricted := len(drilldown.RestrictedActions()); restricted != restrictedCount {
+			restrictedCount = restricted
+			updateTableTitle()
+		}
+	}
+
+	// openDetails opens the drill-down of an event row, showing note if it has one.
+	openDetails := func(row tableRow, note string) {
+		DetailsModal(app, frame, table, row, clientForRow(row), eventArchive, annotate(row), note, analyzer, onDrillDownClosed)
+	}
+
+	openTriage := func() {
+		preview.hide()
+		previewPinned = false
+		TriageModal(app, frame, table, clientForRow, eventArchive, triage, func() []triageItem {
+			return triage.pending(allEvents, namespace, time.Now())
+		}, annotate, notes, onDrillDownClosed)
+	}
+
+	currentTab := func() tabView {
+		return tabView{
+			namespace:    namespace,
+			filterText:   filterText,
+			autoScroll:   autoScroll,
+			columns:      currentColumns(),
+			wrapMessages: wrapMessages,
+			sorting:      sorting,
+		}
+	}
+
+	renderTabBar := func() {
+		if len(tabs) < 2 {
+			flex.ResizeItem(tabBar, 0, 0)
+			return
+		}
+		tabBar.SetText(tabBarText(tabs, activeTab))
+		flex.ResizeItem(tabBar, 1, 0)
+	}
+
+	switchTab := func(idx int) bool {
+		if idx < 0 || idx >= len(tabs) || idx == activeTab {
+			return false
+		}
+		tabs[activeTab] = currentTab()
+		activeTab = idx
+		tab := tabs[idx]
+		filterText = tab.filterText
+		filter.SetText(tab.filterText)
+		autoScroll = tab.autoScroll
+		wrapMessages = tab.wrapMessages
+		sorting = tab.sorting
+		showTimestampColumn = tab.columns.Timestamp
+		showStatusColumn = tab.columns.Status
+		showActionColumn = tab.columns.Action
+		showResourceColumn = tab.columns.Resource
+		aggregateMode = tab.columns.Aggregate
+		updateNamespace(tab.namespace)
+		showNamespaceColumn = tab.columns.Namespace
+		refreshTable()
+		updateTableTitle()
+		renderTabBar()
+		if table.GetRowCount() > 1 {
+			selectTableRow(table.GetRowCount() - 1)
+		}
+		return true
+	}
+
+	newTab := func(ns string) string {
+		if len(tabs) >= maxTabs {
+			return fmt.Sprintf("At most %d tabs", maxTabs)
+		}
+		tabs[activeTab] = currentTab()
+		tab := currentTab()
+		tab.namespace = ns
+		tabs = append(tabs, tab)
+		switchTab(len(tabs) - 1)
+		return fmt.Sprintf("Opened tab %d", len(tabs))
+	}
+
+	closeTab := func() string {
+		if len(tabs) < 2 {
+			return "Cannot close the last tab"
+		}
+		closing := activeTab
+		next := closing - 1
+		if next < 0 {
+			next = 1
+		}
+		switchTab(next)
+		tabs = append(tabs[:closing], tabs[closing+1:]...)
+		if activeTab > closing {
+			activeTab--
+		}
+		// The remaining tabs may fit a narrower watch again.
+		updateNamespace(namespace)
+		renderTabBar()
+		return "Tab closed"
+	}
+
 	buildJumpTargets := func() []CommandPaletteJump {
 		firstRowByEvent := make(map[int]int)
 		for rowOffset, eventIdx := range rowToVisibleEvent {
@@ -459,25 +1396,11 @@ func StartUI(version string, overrideNamespace string) {
 		jumps := make([]CommandPaletteJump, 0, len(eventIndexes))
 		for _, eventIdx := range eventIndexes {
 			row := firstRowByEvent[eventIdx]
-			line := strings.TrimSpace(visibleEvents[eventIdx])
-			label := shortText(line, 120)
-			detail := fmt.Sprintf("row %d", row)
-
-			parts := strings.SplitN(visibleEvents[eventIdx], "│", 6)
-			if len(parts) == 6 {
-				timestamp := strings.TrimSpace(parts[0])
-				resource := strings.TrimSpace(parts[1])
-				reason := strings.TrimSpace(parts[3])
-				namespace := strings.TrimSpace(parts[4])
-				message := strings.TrimSpace(parts[5])
-				label = shortText(fmt.Sprintf("%s  %s  %s", resource, reason, message), 120)
-				detail = shortText(fmt.Sprintf("row %d • %s • ns=%s", row, timestamp, namespace), 120)
-			}
-
+			event := visibleEvents[eventIdx]
 			jumps = append(jumps, CommandPaletteJump{
-				Label:  label,
-				Detail: detail,
-				Search: line,
+				Label:  shortText(fmt.Sprintf("%s  %s  %s", event.resource(), event.event.Reason, event.message()), 120),
+				Detail: shortText(fmt.Sprintf("row %d • %s • ns=%s", row, event.timeText(), event.scope()), 120),
+				Search: event.text(),
 				Row:    row,
 			})
 		}
@@ -503,7 +1426,7 @@ func StartUI(version string, overrideNamespace string) {
 			if eventIdx < 0 || eventIdx >= len(visibleEvents) {
 				continue
 			}
-			score, ok := fuzzyMatchScore(query, visibleEvents[eventIdx])
+			score, ok := fuzzyMatchScore(query, visibleEvents[eventIdx].text())
 			if !ok {
 				continue
 			}
@@ -520,25 +1443,144 @@ func StartUI(version string, overrideNamespace string) {
 		return true
 	}
 
+	// restartWatch restarts the watch so settings that apply when it starts take effect.
+	restartWatch := func() {
+		hub.SetSource(source)
+		// Unsubscribing everything stops the shared watch, so the next one uses the new settings.
+		watches.Stop()
+		updateNamespace(namespace)
+	}
+
+	// toggleWarningsOnly makes the API server send only Warning events, or all again. The
+	// watch restarts, since the selector is part of the list and watch requests.
+	toggleWarningsOnly := func() string {
+		source.WarningsOnly = !source.WarningsOnly
+		restartWatch()
+		if source.WarningsOnly {
+			return "Watching warnings only"
+		}
+		return "Watching all events"
+	}
+
+	settings := []runtimeSetting{
+		{
+			name:        "stormThreshold",
+			description: "Events of one object within the storm window that raise the storm banner.",
+			get:         func() string { return strconv.Itoa(stormThreshold) },
+			set: func(value string) error {
+				n, err := parseSettingInt(value, 1)
+				if err != nil {
+					return err
+				}
+				stormThreshold = n
+				updateStormBanner()
+				return nil
+			},
+		},
+		{
+			name:        "stormWindow",
+			description: "Sliding window events are counted in for the storm banner.",
+			get:         func() string { return format.Duration(storms.window) },
+			set: func(value string) error {
+				d, err := parseSettingDuration(value)
+				if err != nil {
+					return err
+				}
+				if d < time.Second {
+					return fmt.Errorf("must be at least 1s")
+				}
+				storms.window = d.Truncate(time.Second)
+				updateStormBanner()
+				return nil
+			},
+		},
+		{
+			name:        "tailLines",
+			description: "Log lines the drill-down shows of a pod.",
+			get:         func() string { return strconv.FormatInt(drilldown.LogTailLines(), 10) },
+			set: func(value string) error {
+				n, err := parseSettingInt(value, 1)
+				if err != nil {
+					return err
+				}
+				drilldown.SetLogTailLines(int64(n))
+				return nil
+			},
+		},
+		{
+			name:        "backfill",
+			description: "How far back existing events are shown when the watch starts; restarts the watch.",
+			get:         func() string { return source.Backfill.String() },
+			set: func(value string) error {
+				d, err := parseSettingDuration(value)
+				if err != nil {
+					return err
+				}
+				source.Backfill = d
+				restartWatch()
+				return nil
+			},
+		},
+		{
+			name:        "listLimit",
+			description: "Existing events listed when the watch starts, 0 for all; restarts the watch.",
+			get:         func() string { return strconv.Itoa(source.ListLimit) },
+			set: func(value string) error {
+				n, err := parseSettingInt(value, 0)
+				if err != nil {
+					return err
+				}
+				source.ListLimit = n
+				restartWatch()
+				return nil
+			},
+		},
+		{
+			name:        "dropDeleted",
+			description: "Remove the rows of deleted events instead of greying them out.",
+			get:         func() string { return strconv.FormatBool(dropDeleted) },
+			set: func(value string) error {
+				drop, err := strconv.ParseBool(value)
+				if err != nil {
+					return fmt.Errorf("%q is not true or false", value)
+				}
+				dropDeleted = drop
+				for i := range allEvents {
+					allEvents[i].hidden = drop && allEvents[i].deleted
+				}
+				refreshTable()
+				return nil
+			},
+		},
+	}
+
 	openCommandPalette := func() {
 		commands := []CommandPaletteCommand{
 			{
 				Name:        "ns",
 				Aliases:     []string{"namespace"},
-				Description: "Switch namespace: ns <name> (or ns all).",
+				Description: "Switch namespace: ns <name>, ns <a,b> (or ns all).",
 				AcceptsArg:  true,
 				Run: func(arg string) string {
 					if strings.TrimSpace(arg) == "" {
-						NamespacesModal(app, frame, table, namespaceList, updateNamespace)
+						NamespacesModal(app, frame, table, namespaceList, deniedNamespaces, cfg.Flags.HideInaccessibleNamespaces, updateNamespace)
 						return "Opened namespace selector"
 					}
-					ns, ok := resolveNamespace(arg)
-					if !ok {
-						updateTableTitle()
-						table.SetTitle(fmt.Sprintf("%s [red](namespace not found: %s)", table.GetTitle(), strings.TrimSpace(arg)))
-						return "Namespace not found"
+					var resolved []string
+					for _, query := range strings.Split(arg, ",") {
+						ns, ok := resolveNamespace(query)
+						if !ok {
+							updateTableTitle()
+							table.SetTitle(fmt.Sprintf("%s [red](namespace not found: %s)", table.GetTitle(), strings.TrimSpace(query)))
+							return "Namespace not found"
+						}
+						if ns == metav1.NamespaceAll {
+							resolved = nil
+							break
+						}
+						resolved = append(resolved, ns)
 					}
-					updateNamespace(ns)
+					updateNamespace(kube.JoinNamespaces(resolved))
 					return "Namespace updated"
 				},
 			},
@@ -554,13 +1596,16 @@ func StartUI(version string, overrideNamespace string) {
 			{
 				Name:        "theme",
 				Aliases:     []string{"th"},
-				Description: "Select built-in theme: theme <name>.",
+				Description: "Select built-in theme: theme <name>, or try one: theme preview <name | background text>.",
 				AcceptsArg:  true,
 				Run: func(arg string) string {
 					if strings.TrimSpace(arg) == "" {
 						openThemeSelector()
 						return "Opened theme selector"
 					}
+					if fields := strings.Fields(arg); fields[0] == "preview" {
+						return previewTheme(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(arg), "preview")))
+					}
 					theme, ok := resolveTheme(arg)
 					if !ok {
 						updateTableTitle()
@@ -621,6 +1666,182 @@ func StartUI(version string, overrideNamespace string) {
 					return "Aggregate toggled"
 				},
 			},
+			{
+				Name:        "set",
+				Description: "Change a runtime setting: set stormThreshold=50 (empty lists the settings).",
+				AcceptsArg:  true,
+				Run: func(arg string) string {
+					if strings.TrimSpace(arg) == "" {
+						SettingsModal(app, frame, table, settings)
+						return "Opened settings"
+					}
+					setting, err := applySetting(settings, arg)
+					updateTableTitle()
+					if err != nil {
+						table.SetTitle(fmt.Sprintf("%s [red](%v)", table.GetTitle(), err))
+						return "Invalid setting"
+					}
+					table.SetTitle(fmt.Sprintf("%s [gray](%s = %s)", table.GetTitle(), setting.name, escapeTViewText(setting.get())))
+					return "Setting updated"
+				},
+			},
+			{
+				Name:        "sort",
+				Description: "Sort rows by keys, e.g. sort namespace,-time; - sorts descending (empty restores the default).",
+				AcceptsArg:  true,
+				Run: func(arg string) string {
+					order, err := parseSortOrder(arg)
+					if err != nil {
+						updateTableTitle()
+						table.SetTitle(fmt.Sprintf("%s [red](%v)", table.GetTitle(), err))
+						return "Invalid sort"
+					}
+					sorting = order
+					updateTableTitle()
+					refreshTable()
+					if len(sorting) == 0 {
+						return "Default order"
+					}
+					return "Sorted by " + sorting.String()
+				},
+			},
+			{
+				Name:        "for",
+				Description: "Scope to an object and its descendants: for deployment/foo (empty clears).",
+				AcceptsArg:  true,
+				Run:         setScope,
+			},
+			{
+				Name:        "fields",
+				Aliases:     []string{"field-selector"},
+				Description: "Restart the watch with a field selector: fields involvedObject.kind=Pod (empty clears).",
+				AcceptsArg:  true,
+				Run: func(arg string) string {
+					selector, err := watch.ParseFieldSelector(arg)
+					if err != nil {
+						return err.Error()
+					}
+					source.FieldSelector = selector
+					restartWatch()
+					if selector != "" {
+						return "Watching events with " + selector
+					}
+					return "Field selector cleared"
+				},
+			},
+			{
+				Name:        "warnings",
+				Aliases:     []string{"warnings-only"},
+				Description: "Toggle server-side warnings only: the watch restarts with a type=Warning field selector.",
+				Run: func(arg string) string {
+					return toggleWarningsOnly()
+				},
+			},
+			{
+				Name:        "triage",
+				Description: "Review unacknowledged warnings one at a time, oldest first.",
+				Run: func(arg string) string {
+					openTriage()
+					return "Opened triage queue"
+				},
+			},
+			{
+				Name:        "mute",
+				Description: "Mute the object flooding the stream.",
+				Run: func(arg string) string {
+					if !muteTopTalker() {
+						return "No event storm to mute"
+					}
+					return "Top talker muted"
+				},
+			},
+			{
+				Name:        "unmute",
+				Description: "Unmute all muted objects.",
+				Run: func(arg string) string {
+					mutedObjects = make(map[string]bool)
+					updateTableTitle()
+					return "Unmuted all objects"
+				},
+			},
+			{
+				Name:        "open",
+				Aliases:     []string{"paste"},
+				Description: "Drill into a pasted event: open <kubectl events line or JSON>.",
+				AcceptsArg:  true,
+				Run: func(arg string) string {
+					fallbackNs := namespace
+					if fallbackNs == metav1.NamespaceAll {
+						fallbackNs = metav1.NamespaceDefault
+					}
+					pasted, err := parsePastedEvent(arg, fallbackNs, time.Now())
+					if err != nil {
+						updateTableTitle()
+						table.SetTitle(fmt.Sprintf("%s [red](open: %v)", table.GetTitle(), err))
+						return "Could not parse pasted event"
+					}
+					openDetails(pasted, "")
+					return "Opened pasted event"
+				},
+			},
+			{
+				Name:        "dns",
+				Description: "Check CoreDNS health (suggested when events report DNS failures).",
+				Run: func(arg string) string {
+					_, count := dnsFailures.top(time.Now())
+					DNSModal(app, frame, table, kubeClient, count, updateTableTitle)
+					return "Opened DNS check"
+				},
+			},
+			{
+				Name:        "restarts",
+				Description: "Show the workloads whose containers restarted most during the session.",
+				Run: func(arg string) string {
+					RestartsModal(app, frame, table, restarts.workloads, updateTableTitle)
+					return "Opened restarts"
+				},
+			},
+			{
+				Name:        "archive",
+				Description: "Keep a local copy of watched events beyond the cluster's event TTL.",
+				Run: func(arg string) string {
+					return enableArchive()
+				},
+			},
+			{
+				Name:        "tab",
+				Aliases:     []string{"tabnew"},
+				Description: "Open a new tab: tab <namespace> (defaults to the current one).",
+				AcceptsArg:  true,
+				Run: func(arg string) string {
+					ns := namespace
+					if strings.TrimSpace(arg) != "" {
+						resolved, ok := resolveNamespace(arg)
+						if !ok {
+							updateTableTitle()
+							table.SetTitle(fmt.Sprintf("%s [red](namespace not found: %s)", table.GetTitle(), strings.TrimSpace(arg)))
+							return "Namespace not found"
+						}
+						ns = resolved
+					}
+					return newTab(ns)
+				},
+			},
+			{
+				Name:        "tabclose",
+				Description: "Close the current tab.",
+				Run: func(arg string) string {
+					return closeTab()
+				},
+			},
+			{
+				Name:        "header",
+				Description: "Toggle the header; cluster and namespace move to the table title.",
+				Run: func(arg string) string {
+					toggleHeader()
+					return "Header toggled"
+				},
+			},
 			{
 				Name:        "autoscroll",
 				Aliases:     []string{"follow"},
@@ -637,26 +1858,138 @@ func StartUI(version string, overrideNamespace string) {
 		})
 	}
 
+	// eventRowAt returns the event row shown by a table row, which is false when the row
+	// holds no event.
+	eventRowAt := func(row int) (tableRow, bool) {
+		if row <= 0 || row-1 >= len(rowToVisibleEvent) {
+			return tableRow{}, false
+		}
+		idx := rowToVisibleEvent[row-1]
+		if idx < 0 || idx >= len(visibleEvents) {
+			return tableRow{}, false
+		}
+		return visibleEvents[idx], true
+	}
+
+	// rowEvent returns the index in allEvents of the event of a table row, the latest
+	// of its group for aggregated rows, or -1 when the row holds no event.
+	rowEvent := func(row int) int {
+		if row <= 0 || row-1 >= len(rowToVisibleEvent) {
+			return -1
+		}
+		idx := rowToVisibleEvent[row-1]
+		if idx < 0 || idx >= len(visibleEvents) {
+			return -1
+		}
+		if idx < len(visibleSources) {
+			return visibleSources[idx]
+		}
+		return latestEventOf(allEvents, visibleEvents[idx])
+	}
+
+	rowNote := func(row int) string {
+		if idx := rowEvent(row); idx >= 0 {
+			return notes.get(allEvents[idx].event.Fingerprint)
+		}
+		return ""
+	}
+
+	// editNote attaches a note to the event of a table row and to its repeats.
+	editNote := func(row int) {
+		idx := rowEvent(row)
+		if idx < 0 {
+			return
+		}
+		fingerprint := allEvents[idx].event.Fingerprint
+		NoteModal(app, frame, table, allEvents[idx].row(), notes.get(fingerprint), func(text string) {
+			notes.set(fingerprint, text)
+			noted := notes.get(fingerprint) != ""
+			for i := range allEvents {
+				if allEvents[i].event.Fingerprint == fingerprint {
+					allEvents[i].setNoted(noted)
+				}
+			}
+			refreshTable()
+			selectTableRow(row)
+		})
+	}
+
+	// previewRow shows the event of a table row in the preview popup, or hides the popup
+	// when the row holds no event.
+	previewRow := func(row int) {
+		event, ok := eventRowAt(row)
+		if !ok {
+			preview.hide()
+			return
+		}
+		preview.show(row, event)
+	}
+
+	runRowAction := func(action rowAction, event tableRow) {
+		preview.hide()
+		previewPinned = false
+		switch action.name {
+		case rowActionLogs:
+			LogsModal(app, clientForRow(event), event.event.Namespace, event.event.Kind, event.event.Name, LogsRecent, func() {
+				app.SetRoot(frame, true).SetFocus(table)
+			})
+		case rowActionDescribe:
+			row, _ := table.GetSelection()
+			openDetails(event, rowNote(row))
+		case rowActionFilter:
+			setFilterValue(event.resource())
+		}
+	}
+
 	handleInput := func(event *tcell.EventKey) *tcell.EventKey {
-		// If filter is focused, let normal typing work and ignore shortcuts.
-		if app.GetFocus() == filter {
+		// If filter is focused, let normal typing work and ignore shortcuts. Modals
+		// replace the frame and handle their own keys.
+		if app.GetFocus() == filter || !frame.HasFocus() {
 			return event
 		}
-		switch {
-		case event.Key() == tcell.KeyCtrlS:
+		if rowActions.visible() {
+			menuEvent := rowActions.event
+			action, ok := rowActions.handle(event)
+			if ok {
+				runRowAction(action, menuEvent)
+			}
+			return nil
+		}
+		if preview.visible() && !isNavigationKey(event) && !keys.Is(event, ActionPreview) {
+			preview.hide()
+			previewPinned = false
+		}
+		switch keys.Action(event) {
+		case ActionPreview:
+			if previewPinned {
+				preview.hide()
+				previewPinned = false
+			} else {
+				row, _ := table.GetSelection()
+				previewRow(row)
+				previewPinned = preview.visible()
+			}
+			return nil
+		case ActionRowActions:
+			row, _ := table.GetSelection()
+			if selected, ok := eventRowAt(row); ok {
+				rowActions.show(row, selected)
+			}
+			return nil
+		case ActionAutoscroll:
 			toggleAutoScroll()
 			return nil
-		case event.Key() == tcell.KeyCtrlB:
+		case ActionLastEvent:
 			table.ScrollToEnd()
 			table.Select(table.GetRowCount()-1, 0)
 			return nil
-		case event.Key() == tcell.KeyCtrlT:
+		case ActionThemePicker:
 			openThemeSelector()
 			return nil
-		case event.Rune() == ':':
+		case ActionCommandPalette:
 			openCommandPalette()
 			return nil
-		case event.Rune() == '/':
+		case ActionFilter:
 			if filterVisible {
 				flex.ResizeItem(filterContainer, 0, 0)
 				filterVisible = false
@@ -668,96 +2001,229 @@ func StartUI(version string, overrideNamespace string) {
 				app.SetFocus(filter)
 			}
 			return nil
-		case event.Key() == tcell.KeyCtrlN:
-			NamespacesModal(app, frame, table, namespaceList, updateNamespace)
+		case ActionNamespaces:
+			NamespacesModal(app, frame, table, namespaceList, deniedNamespaces, cfg.Flags.HideInaccessibleNamespaces, updateNamespace)
 			return nil
-		case event.Rune() == 'T':
+		case ActionTimestamp:
 			toggleTimestamp()
 			return nil
-		case event.Rune() == 'A':
+		case ActionAction:
 			toggleAction()
 			return nil
-		case event.Rune() == 'S':
+		case ActionStatus:
 			toggleStatus()
 			return nil
-		case event.Rune() == 'R':
+		case ActionResource:
 			toggleResource()
 			return nil
-		case event.Rune() == 'G':
+		case ActionAggregate:
 			toggleAggregate()
 			return nil
-		case event.Rune() == 'w':
+		case ActionWarningsOnly:
+			toggleWarningsOnly()
+			return nil
+		case ActionWrap:
 			toggleWrap()
 			return nil
-		case event.Rune() == 'q', event.Key() == tcell.KeyCtrlC:
-			if watchCancel != nil {
-				watchCancel()
-			}
+		case ActionMute:
+			muteTopTalker()
+			return nil
+		case ActionHeader:
+			toggleHeader()
+			return nil
+		case ActionTriage:
+			openTriage()
+			return nil
+		case ActionNote:
+			row, _ := table.GetSelection()
+			preview.hide()
+			previewPinned = false
+			editNote(row)
+			return nil
+		case ActionQuit:
+			watches.Stop()
 			app.Stop()
 			return nil
-		default:
-			if event.Rune() >= '0' && event.Rune() <= '3' {
-				switch event.Rune() {
-				case '0':
-					updateNamespace("")
-				default:
-					idx := int(event.Rune() - '1')
-					if idx >= 0 && idx < len(recentNamespaces) {
-						updateNamespace(recentNamespaces[idx])
-					}
+		}
+		switch {
+		case event.Modifiers()&tcell.ModAlt != 0 && event.Rune() >= '1' && event.Rune() <= '9':
+			switchTab(int(event.Rune() - '1'))
+			return nil
+		case event.Rune() >= '0' && event.Rune() <= '3':
+			switch event.Rune() {
+			case '0':
+				updateNamespace("")
+			default:
+				idx := int(event.Rune() - '1')
+				if idx >= 0 && idx < len(recentNamespaces) {
+					updateNamespace(recentNamespaces[idx])
 				}
-				return nil
 			}
-			return event
+			return nil
 		}
+		return event
 	}
 
 	app.SetInputCapture(handleInput)
+	table.SetSelectionChangedFunc(func(row, column int) {
+		rowActions.hide()
+		if previewPinned {
+			previewRow(row)
+		}
+	})
+	app.SetMouseCapture(func(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+		if action != tview.MouseMove || previewPinned || !table.HasFocus() {
+			return event, action
+		}
+		row := -1
+		if x, y := event.Position(); table.InInnerRect(x, y) {
+			row, _ = table.CellAt(x, y)
+		}
+		shown := preview.row
+		previewRow(row)
+		if preview.row == shown {
+			return event, action
+		}
+		// Consuming the move makes tview redraw with the new preview.
+		return nil, action
+	})
 	table.SetSelectedFunc(func(row int, column int) {
 		if row <= 0 || row-1 >= len(rowToVisibleEvent) {
 			return
 		}
-		idx := rowToVisibleEvent[row-1]
-		if idx >= 0 && idx < len(visibleEvents) {
-			parts := strings.SplitN(visibleEvents[idx], "│", 6)
-			DetailsModal(app, frame, table, parts, kubeClient)
+		preview.hide()
+		previewPinned = false
+		if event, ok := eventRowAt(row); ok {
+			openDetails(event, rowNote(row))
 		}
 	})
 
+	if cfg.Archive.Enabled {
+		if arc, err := archive.Open(archiveName); err == nil {
+			eventArchive = arc
+		} else {
+			retentionNotice = fmt.Sprintf("[red](archive: %v)", err)
+		}
+	} else {
+		// Warn when the cluster drops events quickly; archiving keeps them locally.
+		go func(namespaces []string) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			// The event TTL is cluster-wide, so one namespace is enough to sample it.
+			ns := metav1.NamespaceAll
+			if len(namespaces) > 0 {
+				ns = namespaces[0]
+			}
+			retention, ok, err := watch.ObservedRetention(ctx, kubeClient, ns)
+			if err != nil || !ok || retention >= time.Duration(cfg.Archive.RetentionWarningMinutes)*time.Minute {
+				return
+			}
+			app.QueueUpdateDraw(func() {
+				if eventArchive != nil {
+					return
+				}
+				retentionNotice = fmt.Sprintf("[yellow]Cluster keeps events ~%s, :archive to keep history", format.Duration(retention))
+				updateTableTitle()
+			})
+		}(kube.SplitNamespaces(namespace))
+	}
+
 	updateTableTitle()
 	updateNamespace(namespace)
+	tabs = []tabView{currentTab()}
+	nsWatchCtx, nsWatchCancel := context.WithCancel(context.Background())
+	defer nsWatchCancel()
+	_ = watch.NamespaceChanges(nsWatchCtx, kubeClient, func(change watch.NamespaceChange) {
+		app.QueueUpdateDraw(func() {
+			onNamespaceChange(change)
+		})
+	})
+	auditCtx, auditCancel := context.WithCancel(context.Background())
+	defer auditCancel()
+	if opts.AuditLog != "" {
+		go func() {
+			err := audit.TailFile(auditCtx, opts.AuditLog, opts.AuditFilter, func(event kube.Event) {
+				app.QueueUpdateDraw(func() {
+					addEvent(event)
+				})
+			})
+			if err != nil {
+				app.QueueUpdateDraw(func() {
+					updateTableTitle()
+					table.SetTitle(fmt.Sprintf("%s [red](audit log: %v)", table.GetTitle(), err))
+				})
+			}
+		}()
+	}
+	if themeErr != nil {
+		table.SetTitle(fmt.Sprintf("%s [red](theme: %v)", table.GetTitle(), themeErr))
+	}
+	if len(dictionaryErrs) > 0 {
+		table.SetTitle(fmt.Sprintf("%s [red](%v)", table.GetTitle(), dictionaryErrs[0]))
+	}
+	if len(keyErrs) > 0 {
+		table.SetTitle(fmt.Sprintf("%s [red](%v)", table.GetTitle(), keyErrs[0]))
+	}
+	if sortErr != nil {
+		table.SetTitle(fmt.Sprintf("%s [red](sort: %v)", table.GetTitle(), sortErr))
+	}
+	if opts.For != "" {
+		setScope(opts.For)
+	}
 
-	flex.AddItem(header.Flex, 7, 0, false).
+	headerHeight := 0
+	if headerVisible {
+		headerHeight = headerRows
+	}
+	flex.AddItem(header.Flex, headerHeight, 0, false).
+		AddItem(stormBanner, 0, 0, false).
+		AddItem(namespaceBanner, 0, 0, false).
+		AddItem(tabBar, 0, 0, false).
 		AddItem(table, 0, 1, false).
 		AddItem(filterContainer, 0, 0, false)
 
 	app.SetRoot(frame, true)
 	app.SetFocus(table)
+	probeNamespaceAccess(namespaceList)
+	if !cfg.Connection.SkipVersionCheck {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), client.StartupTimeout())
+			defer cancel()
+			info, err := client.ServerVersion(ctx, kubeClient)
+			app.QueueUpdateDraw(func() {
+				switch {
+				case err == nil:
+					versionInfo = info
+				case ctx.Err() != nil:
+					versionInfo = &k8sversion.Info{GitVersion: "unknown (timed out)"}
+				default:
+					versionInfo = &k8sversion.Info{GitVersion: "unknown"}
+				}
+				refreshInfo()
+				// Expired credentials, e.g. an SSO session, are recoverable: the auth
+				// modal resumes once the user has logged in again.
+				if kube.IsAuthError(err) {
+					openAuthModal(err)
+				}
+			})
+		}()
+	}
 	if err := app.Run(); err != nil {
-		if watchCancel != nil {
-			watchCancel()
+		watches.Stop()
+		if recorder != nil {
+			_ = recorder.Close()
 		}
 		panic(err)
 	}
-	if watchCancel != nil {
-		watchCancel()
+	watches.Stop()
+	if eventArchive != nil {
+		_ = eventArchive.Close()
 	}
-}
-
-func parseThemeColors(theme config.Theme) (tcell.Color, tcell.Color) {
-	bg := parseHexColor(theme.BackgroundColor, tcell.ColorBlack)
-	text := parseHexColor(theme.TextColor, tcell.ColorWhite)
-	return bg, text
-}
-
-func parseHexColor(raw string, fallback tcell.Color) tcell.Color {
-	value := strings.TrimSpace(strings.TrimPrefix(raw, "#"))
-	if len(value) != 6 {
-		return fallback
-	}
-	parsed, err := strconv.ParseInt(value, 16, 32)
-	if err != nil {
-		return fallback
+	if recorder != nil {
+		if err := recorder.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing session recording: %v\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "Session recorded to %s\n", opts.Record)
+		}
 	}
-	return tcell.ColorIsRGB | tcell.ColorValid | tcell.Color(parsed)
 }
@@ -0,0 +1,1010 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a0xAi/kubeve/config"
+	"github.com/a0xAi/kubeve/internal/testcluster"
+	"github.com/a0xAi/kubeve/kube/drilldown"
+	"github.com/a0xAi/kubeve/kube/watch"
+	"github.com/gdamore/tcell/v2"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	screenWidth  = 240
+	screenHeight = 40
+)
+
+// startTestUI runs the UI on a simulated screen against a fake API server and returns
+// once the event watch is established. The UI is stopped when the test ends.
+func startTestUI(t *testing.T) (*testcluster.Cluster, *lockedScreen) {
+	t.Helper()
+	cluster := testcluster.Start(t, "default")
+	return cluster, startTestUIOn(t, cluster)
+}
+
+// startTestUIOn is startTestUI for a cluster that already has events.
+func startTestUIOn(t *testing.T, cluster *testcluster.Cluster) *lockedScreen {
+	t.Helper()
+	return startTestUIWith(t, cluster, StartOptions{Namespace: "default"})
+}
+
+// startTestUIWith is startTestUIOn with opts.
+func startTestUIWith(t *testing.T, cluster *testcluster.Cluster, opts StartOptions) *lockedScreen {
+	t.Helper()
+	screen := newTestScreen()
+	opts.Screen = screen
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		StartUI("test", opts)
+	}()
+	t.Cleanup(func() {
+		screen.InjectKey(tcell.KeyCtrlC, 0, tcell.ModNone)
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Error("UI did not stop")
+		}
+	})
+
+	cluster.WaitForWatch(t)
+	waitForScreen(t, screen, "Autoscroll", func(string) bool { return true })
+	screen.resize(screenWidth, screenHeight)
+	return screen
+}
+
+// lockedScreen is a simulation screen whose contents can be read while the UI draws:
+// GetContents returns a copy taken between two draws rather than the screen's buffer.
+type lockedScreen struct {
+	tcell.SimulationScreen
+	mu sync.Mutex
+}
+
+func newTestScreen() *lockedScreen {
+	return &lockedScreen{SimulationScreen: tcell.NewSimulationScreen("UTF-8")}
+}
+
+func (s *lockedScreen) Init() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.SimulationScreen.Init()
+}
+
+func (s *lockedScreen) Show() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.SimulationScreen.Show()
+}
+
+func (s *lockedScreen) Sync() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.SimulationScreen.Sync()
+}
+
+func (s *lockedScreen) GetContents() ([]tcell.SimCell, int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cells, width, height := s.SimulationScreen.GetContents()
+	return slices.Clone(cells), width, height
+}
+
+// resize resizes the screen between two draws, like a terminal would, and tells the
+// UI about it.
+func (s *lockedScreen) resize(width, height int) {
+	s.mu.Lock()
+	s.SimulationScreen.SetSize(width, height)
+	s.mu.Unlock()
+	_ = s.PostEvent(tcell.NewEventResize(width, height))
+}
+
+// screenLines returns the text on screen, one string per row.
+func screenLines(screen tcell.SimulationScreen) []string {
+	cells, width, height := screen.GetContents()
+	lines := make([]string, 0, height)
+	for row := 0; row < height; row++ {
+		var line strings.Builder
+		for _, cell := range cells[row*width : (row+1)*width] {
+			if len(cell.Runes) == 0 {
+				line.WriteByte(' ')
+				continue
+			}
+			line.WriteString(string(cell.Runes))
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// waitForScreen waits until the screen shows want and ok accepts its text.
+func waitForScreen(t *testing.T, screen tcell.SimulationScreen, want string, ok func(text string) bool) []string {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	var lines []string
+	for time.Now().Before(deadline) {
+		lines = screenLines(screen)
+		text := strings.Join(lines, "\n")
+		if strings.Contains(text, want) && ok(text) {
+			return lines
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("screen never showed %q:\n%s", want, strings.Join(lines, "\n"))
+	return nil
+}
+
+func linesContaining(lines []string, text string) []int {
+	var rows []int
+	for i, line := range lines {
+		if strings.Contains(line, text) {
+			rows = append(rows, i)
+		}
+	}
+	return rows
+}
+
+func TestStreamShowsWatchedEventsInOrder(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Normal", "Pulled", "image pulled for api-0"))
+	cluster.Emit(testcluster.PodEvent("default", "api-1", "Warning", "BackOff", "back-off restarting api-1"))
+
+	lines := waitForScreen(t, screen, "back-off restarting api-1", func(text string) bool {
+		return strings.Contains(text, "image pulled for api-0")
+	})
+	first := linesContaining(lines, "image pulled for api-0")
+	second := linesContaining(lines, "back-off restarting api-1")
+	if len(first) != 1 || len(second) != 1 || first[0] >= second[0] {
+		t.Fatalf("want one row per event in arrival order, got rows %v and %v", first, second)
+	}
+}
+
+func TestNewerServerStreamsEventsAsJSON(t *testing.T) {
+	cluster := testcluster.Start(t, "default")
+	// Newer than the client libraries, so events are read as unstructured JSON.
+	cluster.SetMinorVersion("99")
+	screen := startTestUIOn(t, cluster)
+
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Warning", "BackOff", "back-off restarting api-0"))
+	waitForScreen(t, screen, "back-off restarting api-0", func(text string) bool {
+		return strings.Contains(text, "Pod/api-0")
+	})
+}
+
+func TestUpdatedEventRewritesOnlyItsRow(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	// Two distinct events that render to identical rows.
+	event := testcluster.PodEvent("default", "web-0", "Warning", "Unhealthy", "readiness probe failed")
+	cluster.Emit(event)
+	second := cluster.Emit(event)
+	waitForScreen(t, screen, "readiness probe failed", func(text string) bool {
+		return strings.Count(text, "readiness probe failed") == 2
+	})
+
+	second.Series = &eventsv1.EventSeries{Count: 5, LastObservedTime: second.EventTime}
+	cluster.Update(second)
+
+	lines := waitForScreen(t, screen, "x5", func(string) bool { return true })
+	if rows := linesContaining(lines, "readiness probe failed"); len(rows) != 2 {
+		t.Fatalf("want the update to keep two rows, got %d", len(rows))
+	}
+	if rows := linesContaining(lines, "x5"); len(rows) != 1 {
+		t.Fatalf("want only the updated event's row to show its count, got %d rows", len(rows))
+	}
+}
+
+func TestEnterOpensDrillDownOfSelectedRow(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	for _, note := range []string{"first message", "second message", "third message"} {
+		cluster.Emit(testcluster.PodEvent("default", "worker", "Normal", "Started", note))
+	}
+	waitForScreen(t, screen, "third message", func(text string) bool {
+		return strings.Contains(text, "first message") && strings.Contains(text, "second message")
+	})
+
+	// The newest row is selected while autoscrolling; select the one above it.
+	screen.InjectKey(tcell.KeyUp, 0, tcell.ModNone)
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+
+	lines := waitForScreen(t, screen, "Event Drill-Down", func(string) bool { return true })
+	text := strings.Join(lines, "\n")
+	if !strings.Contains(text, "second message") {
+		t.Fatalf("drill-down does not show the selected event:\n%s", text)
+	}
+	if strings.Contains(text, "third message") || strings.Contains(text, "first message") {
+		t.Fatalf("drill-down shows another event:\n%s", text)
+	}
+}
+
+func TestRowActionsFilterToObject(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Normal", "Pulled", "image pulled for api-0"))
+	cluster.Emit(testcluster.PodEvent("default", "web-0", "Warning", "BackOff", "back-off restarting web-0"))
+	waitForScreen(t, screen, "back-off restarting web-0", func(text string) bool {
+		return strings.Contains(text, "image pulled for api-0")
+	})
+
+	screen.InjectKey(tcell.KeyRune, 'a', tcell.ModNone)
+	waitForScreen(t, screen, "Filter to object", func(text string) bool {
+		return strings.Contains(text, "Logs") && strings.Contains(text, "Describe")
+	})
+	screen.InjectKey(tcell.KeyRune, 'f', tcell.ModNone)
+
+	waitForScreen(t, screen, "Filter: Pod/web-0", func(text string) bool {
+		return !strings.Contains(text, "image pulled for api-0") && !strings.Contains(text, "Filter to object")
+	})
+}
+
+func TestNoteMarksRowAndShowsInDrillDown(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Normal", "Pulled", "image pulled for api-0"))
+	cluster.Emit(testcluster.PodEvent("default", "web-0", "Warning", "BackOff", "back-off restarting web-0"))
+	waitForScreen(t, screen, "back-off restarting web-0", func(text string) bool {
+		return strings.Contains(text, "image pulled for api-0")
+	})
+
+	screen.InjectKey(tcell.KeyRune, 'm', tcell.ModNone)
+	waitForScreen(t, screen, "Note: Pod/web-0", func(string) bool { return true })
+	for _, r := range "paged on-call" {
+		screen.InjectKey(tcell.KeyRune, r, tcell.ModNone)
+	}
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+
+	lines := waitForScreen(t, screen, noteMarker+"back-off restarting web-0", func(string) bool { return true })
+	if rows := linesContaining(lines, noteMarker); len(rows) != 1 {
+		t.Fatalf("%d rows are marked as noted, want 1:\n%s", len(rows), strings.Join(lines, "\n"))
+	}
+
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	waitForScreen(t, screen, "Event Drill-Down", func(text string) bool {
+		return strings.Contains(text, "Note:") && strings.Contains(text, "paged on-call")
+	})
+}
+
+func TestBackfillShowsRecentExistingEvents(t *testing.T) {
+	cluster := testcluster.Start(t, "default")
+	for _, existing := range []struct {
+		note string
+		age  time.Duration
+	}{
+		{"pulled an hour ago", time.Hour},
+		{"pulled five hours ago", 5 * time.Hour},
+		{"pulled just now", 0},
+	} {
+		event := testcluster.PodEvent("default", "batch", "Normal", "Pulled", existing.note)
+		event.EventTime = metav1.NewMicroTime(time.Now().Add(-existing.age))
+		cluster.Emit(event)
+	}
+
+	screen := startTestUIWith(t, cluster, StartOptions{Namespace: "default", Watch: watch.Source{Backfill: 2 * time.Hour}})
+	lines := waitForScreen(t, screen, "pulled an hour ago", func(text string) bool {
+		return strings.Contains(text, "pulled just now")
+	})
+	if rows := linesContaining(lines, "pulled five hours ago"); len(rows) != 0 {
+		t.Fatal("event older than the backfill window is shown")
+	}
+	older, newer := linesContaining(lines, "pulled an hour ago"), linesContaining(lines, "pulled just now")
+	if older[0] >= newer[0] {
+		t.Fatal("backfilled events are not shown oldest first")
+	}
+}
+
+func TestWarningsOnlyToggleRestartsTheWatchWithItsSelector(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	screen.InjectKey(tcell.KeyCtrlW, 0, tcell.ModCtrl)
+	waitForScreen(t, screen, "Warnings only", func(string) bool { return true })
+	cluster.WaitForWatchSelecting(t, "type=Warning")
+
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Normal", "Pulled", "image pulled for api-0"))
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Warning", "BackOff", "back-off restarting api-0"))
+	lines := waitForScreen(t, screen, "back-off restarting api-0", func(string) bool { return true })
+	if rows := linesContaining(lines, "image pulled for api-0"); len(rows) != 0 {
+		t.Fatalf("a Normal event is shown while watching warnings only:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestRolloutLookupsDoNotHoldUpTheStream(t *testing.T) {
+	cluster, screen := startTestUI(t)
+	cluster.AddCustomObject("replicasets", true, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "ReplicaSet",
+		"metadata": map[string]any{
+			"name": "web-5f7b8", "namespace": "default", "uid": "rs-uid",
+			"annotations": map[string]any{"deployment.kubernetes.io/revision": "3"},
+		},
+	}})
+
+	// The fake server does not serve Deployment lists, so the replica counts wait out
+	// the lookup timeout.
+	start := time.Now()
+	scaled := testcluster.PodEvent("default", "web", "Normal", "ScalingReplicaSet", "Scaled up replica set web-5f7b8 to 3")
+	scaled.Regarding = corev1.ObjectReference{Kind: "Deployment", Namespace: "default", Name: "web", UID: "deploy-uid", APIVersion: "apps/v1"}
+	cluster.Emit(scaled)
+	cluster.Emit(testcluster.PodEvent("default", "web-0", "Warning", "BackOff", "back-off restarting web-0"))
+	waitForScreen(t, screen, "(rev 3) Scaled up replica set web-5f7b8 to 3", func(text string) bool {
+		return strings.Contains(text, "back-off restarting web-0")
+	})
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("the events took %s to show behind a rollout event", elapsed)
+	}
+}
+
+func TestDeletedEventIsGreyedOut(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	expired := cluster.Emit(testcluster.PodEvent("default", "job-0", "Normal", "Completed", "job finished"))
+	cluster.Emit(testcluster.PodEvent("default", "job-1", "Normal", "Started", "job started"))
+	waitForScreen(t, screen, "job started", func(text string) bool {
+		return strings.Contains(text, "job finished")
+	})
+
+	cluster.Delete(expired)
+
+	lines := waitForScreen(t, screen, "(deleted) job finished", func(string) bool { return true })
+	if rows := linesContaining(lines, "job started"); len(rows) != 1 {
+		t.Fatal("deleting one event changed the other's row")
+	}
+	if rows := linesContaining(lines, "(deleted) job started"); len(rows) != 0 {
+		t.Fatal("the remaining event is marked deleted")
+	}
+}
+
+func TestDrillDownShowsMessageJSON(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Warning", "FailedCreate",
+		`admission webhook "policy.example.com" denied the request: {"allowed":false,"status":{"code":403,"reason":"image not signed"}}`))
+	waitForScreen(t, screen, "admission webhook", func(string) bool { return true })
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	waitForScreen(t, screen, "v to view the message's JSON", func(string) bool { return true })
+
+	screen.InjectKey(tcell.KeyRune, 'v', tcell.ModNone)
+	lines := waitForScreen(t, screen, `"reason": "image not signed"`, func(string) bool { return true })
+	if rows := linesContaining(lines, `"allowed": false,`); len(rows) != 1 {
+		t.Fatalf("JSON is not pretty-printed:\n%s", strings.Join(lines, "\n"))
+	}
+
+	// Fold the "status" object.
+	screen.InjectKey(tcell.KeyDown, 0, tcell.ModNone)
+	screen.InjectKey(tcell.KeyDown, 0, tcell.ModNone)
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	lines = waitForScreen(t, screen, `"status": {… 2 keys}`, func(string) bool { return true })
+	if rows := linesContaining(lines, "image not signed"); len(rows) != 0 {
+		t.Fatal("folded object still shows its content")
+	}
+
+	screen.InjectKey(tcell.KeyRune, 'q', tcell.ModNone)
+	waitForScreen(t, screen, "Event Drill-Down", func(string) bool { return true })
+}
+
+func TestMultiLineMessageStaysOneRow(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Warning", "BackOff", "probe failed:\n  exit code 1\r\n\nconnection refused"))
+	cluster.Emit(testcluster.PodEvent("default", "api-1", "Normal", "Pulled", "pulled after the multi-line one"))
+	lines := waitForScreen(t, screen, "probe failed: ↵ exit code 1 ↵ connection refused", func(text string) bool {
+		return strings.Contains(text, "pulled after the multi-line one")
+	})
+	if rows := linesContaining(lines, "connection refused"); len(rows) != 1 {
+		t.Fatalf("multi-line message spans several rows:\n%s", strings.Join(lines, "\n"))
+	}
+	if rows := linesContaining(lines, "api-1"); len(rows) != 1 {
+		t.Fatalf("row after the multi-line message is not intact:\n%s", strings.Join(lines, "\n"))
+	}
+
+	screen.InjectKey(tcell.KeyUp, 0, tcell.ModNone)
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	lines = waitForScreen(t, screen, "Event Drill-Down", func(text string) bool {
+		return strings.Contains(text, "connection refused")
+	})
+	if rows := linesContaining(lines, "↵"); len(rows) != 0 {
+		t.Fatalf("drill-down shows the flattened message:\n%s", strings.Join(lines, "\n"))
+	}
+	if rows := linesContaining(lines, "exit code 1"); len(rows) != 1 || len(linesContaining(lines, "connection refused")) != 1 || rows[0] == linesContaining(lines, "connection refused")[0] {
+		t.Fatalf("drill-down does not keep the message's lines:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestDrillDownOfCustomResourceShowsYAMLAndOwners(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.AddCustomObject("certificates", true, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata":   map[string]any{"name": "web-tls", "namespace": "default", "uid": "cert-uid"},
+		"spec":       map[string]any{"secretName": "web-tls"},
+	}})
+	cluster.AddCustomObject("certificaterequests", true, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "CertificateRequest",
+		"metadata": map[string]any{
+			"name":      "web-tls-1",
+			"namespace": "default",
+			"ownerReferences": []any{map[string]any{
+				"apiVersion": "cert-manager.io/v1", "kind": "Certificate", "name": "web-tls", "uid": "cert-uid", "controller": true,
+			}},
+		},
+		"spec":   map[string]any{"issuerRef": map[string]any{"name": "letsencrypt"}},
+		"status": map[string]any{"failureTime": "2026-01-01T00:00:00Z"},
+	}})
+	event := testcluster.PodEvent("default", "web-tls-1", "Warning", "Failed", "order failed for web-tls-1")
+	event.Regarding.Kind, event.Regarding.APIVersion = "CertificateRequest", "cert-manager.io/v1"
+	cluster.Emit(event)
+	waitForScreen(t, screen, "order failed for web-tls-1", func(string) bool { return true })
+
+	screen.resize(screenWidth, 80)
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	lines := waitForScreen(t, screen, "Owner chain:", func(text string) bool {
+		return strings.Contains(text, "- Certificate/web-tls")
+	})
+	text := strings.Join(lines, "\n")
+	for _, want := range []string{"API version: cert-manager.io/v1", "issuerRef:", "name: letsencrypt", "failureTime:"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("drill-down lacks %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestPodDrillDownDescribesContainersAndScheduling(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.AddPod(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"disktype": "ssd"},
+			Tolerations:  []corev1.Toleration{{Key: "dedicated", Value: "api", Effect: corev1.TaintEffectNoSchedule}},
+			Containers: []corev1.Container{{
+				Name:  "app",
+				Image: "example/api:1.2",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+				},
+				LivenessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+					HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt32(8080)},
+				}},
+			}},
+			Volumes: []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "api-config"}},
+			}}},
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: corev1.PodQOSBurstable,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 4,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					Reason: "OOMKilled", ExitCode: 137,
+				}},
+			}},
+		},
+	})
+	cluster.AddCustomObject("pods", true, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "metrics.k8s.io/v1beta1",
+		"kind":       "PodMetrics",
+		"metadata":   map[string]any{"name": "api-0", "namespace": "default"},
+		"containers": []any{map[string]any{
+			"name":  "app",
+			"usage": map[string]any{"cpu": "4567890n", "memory": "123456Ki"},
+		}},
+	}})
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Warning", "BackOff", "back-off restarting api-0"))
+	waitForScreen(t, screen, "back-off restarting api-0", func(string) bool { return true })
+
+	screen.resize(screenWidth, 80)
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	lines := waitForScreen(t, screen, "QoS class: Burstable", func(text string) bool {
+		return strings.Contains(text, "Volumes:")
+	})
+	text := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"Node selector: disktype=ssd",
+		"- dedicated=api:NoSchedule",
+		"State: Waiting (CrashLoopBackOff)",
+		"Last state: Terminated (OOMKilled), exit code 137",
+		"Limits: memory=128Mi",
+		"Usage: cpu=5m, memory=121Mi (94% of limit)",
+		"Liveness: http-get http://:8080/healthz delay=0s timeout=1s period=10s #success=1 #failure=3",
+		"- config: ConfigMap api-config",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("pod describe lacks %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestPodDrillDownShowsEventsOfOwnerChain(t *testing.T) {
+	cluster := testcluster.Start(t, "default")
+	cluster.ServeCoreEvents()
+	screen := startTestUIOn(t, cluster)
+
+	controller := true
+	cluster.AddCustomObject("deployments", true, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "api", "namespace": "default", "uid": "deploy-uid"},
+	}})
+	cluster.AddCustomObject("replicasets", true, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "ReplicaSet",
+		"metadata": map[string]any{
+			"name": "api-7d9c4", "namespace": "default", "uid": "rs-uid",
+			"ownerReferences": []any{map[string]any{
+				"apiVersion": "apps/v1", "kind": "Deployment", "name": "api", "uid": "deploy-uid", "controller": true,
+			}},
+		},
+	}})
+	cluster.AddPod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "api-7d9c4-x2x8q", Namespace: "default", UID: "pod-default-api-7d9c4-x2x8q",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "api-7d9c4", UID: "rs-uid", Controller: &controller,
+		}},
+	}})
+
+	stalled := testcluster.PodEvent("default", "api", "Warning", "ProgressDeadlineExceeded", `ReplicaSet "api-7d9c4" has timed out progressing.`)
+	stalled.Regarding = corev1.ObjectReference{Kind: "Deployment", Namespace: "default", Name: "api", UID: "deploy-uid", APIVersion: "apps/v1"}
+	cluster.Emit(stalled)
+	created := testcluster.PodEvent("default", "api-7d9c4", "Normal", "SuccessfulCreate", "Created pod: api-7d9c4-x2x8q")
+	created.Regarding = corev1.ObjectReference{Kind: "ReplicaSet", Namespace: "default", Name: "api-7d9c4", UID: "rs-uid", APIVersion: "apps/v1"}
+	cluster.Emit(created)
+	cluster.Emit(testcluster.PodEvent("default", "api-7d9c4-x2x8q", "Warning", "BackOff", "back-off restarting api-7d9c4-x2x8q"))
+	waitForScreen(t, screen, "back-off restarting api-7d9c4-x2x8q", func(string) bool { return true })
+
+	screen.resize(screenWidth, 80)
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	lines := waitForScreen(t, screen, "Related events (owners and owned objects):", func(string) bool { return true })
+	rs := linesContaining(lines, "Normal/SuccessfulCreate ReplicaSet/api-7d9c4: Created pod")
+	deploy := linesContaining(lines, "Warning/ProgressDeadlineExceeded Deployment/api: ReplicaSet")
+	if len(rs) != 1 || len(deploy) != 1 || rs[0] > deploy[0] {
+		t.Fatalf("want the ReplicaSet's and Deployment's events newest first, got rows %v and %v:\n%s", rs, deploy, strings.Join(lines, "\n"))
+	}
+}
+
+func TestDrillDownOpensRelatedObjects(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	controller := true
+	cluster.AddCustomObject("deployments", true, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "api", "namespace": "default", "uid": "deploy-uid"},
+	}})
+	cluster.AddCustomObject("replicasets", true, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "ReplicaSet",
+		"metadata": map[string]any{
+			"name": "api-7d9c4", "namespace": "default", "uid": "rs-uid",
+			"ownerReferences": []any{map[string]any{
+				"apiVersion": "apps/v1", "kind": "Deployment", "name": "api", "uid": "deploy-uid", "controller": true,
+			}},
+		},
+	}})
+	cluster.AddPod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "api-7d9c4-x2x8q", Namespace: "default", UID: "pod-default-api-7d9c4-x2x8q",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "api-7d9c4", UID: "rs-uid", Controller: &controller,
+		}},
+	}})
+	cluster.Emit(testcluster.PodEvent("default", "api-7d9c4-x2x8q", "Warning", "BackOff", "back-off restarting api-7d9c4-x2x8q"))
+	waitForScreen(t, screen, "back-off restarting api-7d9c4-x2x8q", func(string) bool { return true })
+
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	lines := waitForScreen(t, screen, "Related Objects", func(text string) bool {
+		return strings.Contains(text, "Deployment/api")
+	})
+	rs := linesContaining(lines, "ReplicaSet/api-7d9c4 ")
+	deploy := linesContaining(lines, "Deployment/api ")
+	if len(rs) == 0 || len(deploy) == 0 || rs[len(rs)-1] > deploy[len(deploy)-1] {
+		t.Fatalf("want the ReplicaSet listed before the Deployment:\n%s", strings.Join(lines, "\n"))
+	}
+
+	screen.InjectKey(tcell.KeyTab, 0, tcell.ModNone)
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	waitForScreen(t, screen, "Trail:     Pod/api-7d9c4-x2x8q › ReplicaSet/api-7d9c4", func(text string) bool {
+		return strings.Contains(text, "Esc/q to go back")
+	})
+
+	screen.InjectKey(tcell.KeyEsc, 0, tcell.ModNone)
+	waitForScreen(t, screen, "Event Drill-Down", func(text string) bool {
+		return strings.Contains(text, "back-off restarting api-7d9c4-x2x8q") && !strings.Contains(text, "Trail:")
+	})
+}
+
+// widgetAdapter is a drill-down adapter registered from outside the drilldown package.
+type widgetAdapter struct{}
+
+func (widgetAdapter) Describe(_ context.Context, _ *kubernetes.Clientset, namespace, name string) string {
+	return "Widget " + namespace + "/" + name + " spins at 3 rpm"
+}
+
+func (widgetAdapter) Related(context.Context, *kubernetes.Clientset, string, string) ([]string, string) {
+	return []string{"Gear/left", "Gear/right"}, ""
+}
+
+func (widgetAdapter) Logs(context.Context, *kubernetes.Clientset, string, string, string) string {
+	return "widget log line"
+}
+
+func TestDrillDownUsesRegisteredAdapter(t *testing.T) {
+	drilldown.Register(widgetAdapter{}, "Widget", "wdg")
+	cluster, screen := startTestUI(t)
+
+	event := testcluster.PodEvent("default", "spinner", "Warning", "Stalled", "widget spinner stalled")
+	event.Regarding = corev1.ObjectReference{Kind: "Widget", Namespace: "default", Name: "spinner", APIVersion: "example.com/v1"}
+	cluster.Emit(event)
+	waitForScreen(t, screen, "widget spinner stalled", func(string) bool { return true })
+
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	lines := waitForScreen(t, screen, "Widget default/spinner spins at 3 rpm", func(text string) bool {
+		return strings.Contains(text, "widget log line")
+	})
+	text := strings.Join(lines, "\n")
+	if !strings.Contains(text, "Gear/left") || !strings.Contains(text, "]/[ for more related resources") {
+		t.Fatalf("want the adapter's related pages:\n%s", text)
+	}
+}
+
+func TestDrillDownShowsObjectYAML(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.AddPod(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "web-0", Namespace: "default",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "web", Image: "nginx:1.27", Args: []string{"--port=8080"},
+		}}},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	})
+	cluster.Emit(testcluster.PodEvent("default", "web-0", "Warning", "FailedScheduling", "0/3 nodes are available"))
+	waitForScreen(t, screen, "0/3 nodes are available", func(string) bool { return true })
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	waitForScreen(t, screen, "y to view the object's YAML", func(string) bool { return true })
+
+	screen.InjectKey(tcell.KeyRune, 'y', tcell.ModNone)
+	lines := waitForScreen(t, screen, "YAML: Pod/web-0", func(text string) bool {
+		return strings.Contains(text, "kind: Pod")
+	})
+	text := strings.Join(lines, "\n")
+	for _, want := range []string{"image: nginx:1.27", "- --port=8080", "phase: Pending"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("YAML lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "managedFields") {
+		t.Fatalf("YAML shows managedFields:\n%s", text)
+	}
+
+	screen.InjectKey(tcell.KeyEsc, 0, tcell.ModNone)
+	waitForScreen(t, screen, "Event Drill-Down", func(string) bool { return true })
+}
+
+func TestDrillDownShowsPreviousContainerLogs(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.AddPod(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "api", Image: "api:2"}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "api",
+				RestartCount: 4,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					ExitCode: 2, Reason: "Error",
+				}},
+			}},
+		},
+	})
+	cluster.SetLogs("default", "api-0", "api", false, "starting api\n")
+	cluster.SetLogs("default", "api-0", "api", true, "starting api\npanic: missing DATABASE_URL\n")
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Warning", "BackOff", "Back-off restarting failed container api in pod api-0"))
+	waitForScreen(t, screen, "Back-off restarting", func(string) bool { return true })
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	waitForScreen(t, screen, "Recent Logs", func(string) bool { return true })
+
+	screen.InjectKey(tcell.KeyRune, 'p', tcell.ModNone)
+	waitForScreen(t, screen, "Previous logs: Pod/api-0", func(text string) bool {
+		return strings.Contains(text, "panic: missing DATABASE_URL") &&
+			strings.Contains(text, "Terminated (Error), exit code 2")
+	})
+
+	screen.InjectKey(tcell.KeyRune, 'p', tcell.ModNone)
+	lines := waitForScreen(t, screen, "Logs: Pod/api-0", func(text string) bool {
+		return !strings.Contains(text, "Previous logs")
+	})
+	if rows := linesContaining(lines, "panic"); len(rows) != 0 {
+		t.Fatalf("current logs show the previous run:\n%s", strings.Join(lines, "\n"))
+	}
+
+	screen.InjectKey(tcell.KeyEsc, 0, tcell.ModNone)
+	waitForScreen(t, screen, "Event Drill-Down", func(string) bool { return true })
+}
+
+func TestDrillDownFollowsLogsUntilClosed(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.AddPod(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "api", Image: "api:2"}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "api",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}},
+		},
+	})
+	cluster.SetLogs("default", "api-0", "api", false, "starting api\n")
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Warning", "Unhealthy", "Readiness probe failed for api-0"))
+	waitForScreen(t, screen, "Readiness probe failed", func(string) bool { return true })
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	waitForScreen(t, screen, "Recent Logs", func(string) bool { return true })
+
+	screen.InjectKey(tcell.KeyRune, 'f', tcell.ModNone)
+	waitForScreen(t, screen, "Following logs: Pod/api-0", func(text string) bool {
+		return strings.Contains(text, "starting api") && strings.Contains(text, "Following")
+	})
+	cluster.AppendLogs("default", "api-0", "api", "GET /healthz 200\n")
+	waitForScreen(t, screen, "GET /healthz 200", func(string) bool { return true })
+
+	screen.InjectKey(tcell.KeyRune, ' ', tcell.ModNone)
+	waitForScreen(t, screen, "Paused, 0 new lines", func(string) bool { return true })
+	cluster.AppendLogs("default", "api-0", "api", "GET /orders 500\n")
+	waitForScreen(t, screen, "Paused, 1 new lines", func(text string) bool {
+		return !strings.Contains(text, "GET /orders 500")
+	})
+	screen.InjectKey(tcell.KeyRune, ' ', tcell.ModNone)
+	waitForScreen(t, screen, "GET /orders 500", func(text string) bool {
+		return !strings.Contains(text, "Paused")
+	})
+
+	screen.InjectKey(tcell.KeyEsc, 0, tcell.ModNone)
+	waitForScreen(t, screen, "Event Drill-Down", func(string) bool { return true })
+	deadline := time.Now().Add(5 * time.Second)
+	for cluster.LogFollowers() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("closing the logs view left the log stream open")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestWorkloadLogsMergePodsByTime(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.AddCustomObject("deployments", true, &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]any{"name": "api", "namespace": "default"},
+		"spec": map[string]any{
+			"selector": map[string]any{"matchLabels": map[string]any{"app": "api"}},
+		},
+	}})
+	for _, name := range []string{"api-0", "api-1"} {
+		cluster.AddPod(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "api"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "api", Image: "api:2"}}},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "api",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				}},
+			},
+		})
+	}
+	cluster.SetLogs("default", "api-0", "api", false,
+		"2026-01-01T10:00:01Z connecting to db\n2026-01-01T10:00:03Z db timeout\n")
+	cluster.SetLogs("default", "api-1", "api", false, "2026-01-01T10:00:02Z serving on :8080\n")
+	event := testcluster.PodEvent("default", "api", "Warning", "ProgressDeadlineExceeded", "deployment api exceeded its progress deadline")
+	event.Regarding.Kind, event.Regarding.APIVersion = "Deployment", "apps/v1"
+	cluster.Emit(event)
+	waitForScreen(t, screen, "exceeded its progress deadline", func(string) bool { return true })
+
+	screen.InjectKey(tcell.KeyRune, 'a', tcell.ModNone)
+	waitForScreen(t, screen, "Filter to object", func(string) bool { return true })
+	screen.InjectKey(tcell.KeyRune, 'l', tcell.ModNone)
+
+	lines := waitForScreen(t, screen, "Pods: api-0, api-1 (merged by time)", func(text string) bool {
+		return strings.Contains(text, "db timeout")
+	})
+	first := linesContaining(lines, "[pod/api-0/api] 2026-01-01T10:00:01Z connecting to db")
+	second := linesContaining(lines, "[pod/api-1/api] 2026-01-01T10:00:02Z serving on :8080")
+	third := linesContaining(lines, "[pod/api-0/api] 2026-01-01T10:00:03Z db timeout")
+	if len(first) != 1 || len(second) != 1 || len(third) != 1 || first[0] >= second[0] || second[0] >= third[0] {
+		t.Fatalf("want the pods' lines interleaved by time:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestExpiredCredentialsAtStartResumeAfterRetry(t *testing.T) {
+	cluster := testcluster.Start(t, "default")
+	cluster.SetUnauthorized(true)
+	screen := newTestScreen()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		StartUI("test", StartOptions{Namespace: "default", Screen: screen})
+	}()
+	t.Cleanup(func() {
+		screen.InjectKey(tcell.KeyCtrlC, 0, tcell.ModNone)
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Error("UI did not stop")
+		}
+	})
+
+	waitForScreen(t, screen, "Credentials rejected or unavailable", func(string) bool { return true })
+	cluster.SetUnauthorized(false)
+	screen.InjectKey(tcell.KeyRune, 'r', tcell.ModNone)
+	cluster.WaitForWatch(t)
+
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Normal", "Pulled", "pulled after login"))
+	waitForScreen(t, screen, "pulled after login", func(text string) bool {
+		return strings.Contains(text, "v1.33.0") && strings.Contains(text, "Connected")
+	})
+}
+
+// backgroundAt returns the background color of the screen cell where text starts.
+func backgroundAt(screen tcell.SimulationScreen, text string) (tcell.Color, bool) {
+	cells, width, _ := screen.GetContents()
+	for row, line := range screenLines(screen) {
+		if col := strings.Index(line, text); col >= 0 {
+			// Columns are counted in cells; the lines before col may hold wide runes.
+			col = len([]rune(line[:col]))
+			_, bg, _ := cells[row*width+col].Style.Decompose()
+			return bg, true
+		}
+	}
+	return tcell.ColorDefault, false
+}
+
+func TestAggregatedRowHighlightsChangedCells(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Warning", "BackOff", "back-off restarting api-0 (1)"))
+	waitForScreen(t, screen, "back-off restarting api-0 (1)", func(string) bool { return true })
+	screen.InjectKey(tcell.KeyRune, 'G', tcell.ModNone)
+	waitForScreen(t, screen, "LAST MESSAGE", func(string) bool { return true })
+	if bg, _ := backgroundAt(screen, "back-off restarting api-0 (1)"); bg == changeHighlightColor {
+		t.Fatal("a new aggregated row is highlighted")
+	}
+
+	// Later by a few seconds, since last-seen times have a resolution of one.
+	repeat := testcluster.PodEvent("default", "api-0", "Warning", "BackOff", "back-off restarting api-0 (2)")
+	repeat.EventTime = metav1.NewMicroTime(time.Now().Add(3 * time.Second))
+	cluster.Emit(repeat)
+	waitForScreen(t, screen, "back-off restarting api-0 (2)", func(string) bool {
+		bg, _ := backgroundAt(screen, "back-off restarting api-0 (2)")
+		return bg == changeHighlightColor
+	})
+	waitForScreen(t, screen, "back-off restarting api-0 (2)", func(string) bool {
+		bg, _ := backgroundAt(screen, "back-off restarting api-0 (2)")
+		return bg != changeHighlightColor
+	})
+}
+
+func TestSmallTerminalShowsResizeBannerUntilEnlarged(t *testing.T) {
+	cluster, screen := startTestUI(t)
+
+	cluster.Emit(testcluster.PodEvent("default", "api-0", "Warning", "BackOff", "back-off restarting api-0"))
+	waitForScreen(t, screen, "back-off restarting api-0", func(string) bool { return true })
+
+	screen.resize(40, 10)
+	waitForScreen(t, screen, "Resize to at least 60x12", func(text string) bool {
+		return strings.Contains(text, "40x10") && !strings.Contains(text, "back-off")
+	})
+
+	screen.resize(screenWidth, screenHeight)
+	waitForScreen(t, screen, "back-off restarting api-0", func(text string) bool {
+		return !strings.Contains(text, "Resize to at least")
+	})
+}
+
+func TestDeletedNamespaceShowsBannerAndLeavesRecent(t *testing.T) {
+	cluster := testcluster.Start(t, "default", "team-a")
+	screen := startTestUIOn(t, cluster)
+	waitForScreen(t, screen, "<1> default", func(string) bool { return true })
+
+	cluster.WaitForNamespaceWatch(t)
+	cluster.DeleteNamespace("default")
+	waitForScreen(t, screen, "Namespace default was deleted", func(text string) bool {
+		return !strings.Contains(text, "<1> default")
+	})
+
+	screen.InjectKey(tcell.KeyRune, '0', tcell.ModNone)
+	waitForScreen(t, screen, "<0> All Namespaces", func(text string) bool {
+		return !strings.Contains(text, "was deleted")
+	})
+}
+
+func TestNamespacePickerMarksNamespacesWithoutEventAccess(t *testing.T) {
+	cluster := testcluster.Start(t, "default", "team-a", "team-b")
+	cluster.DenyEvents("team-b")
+	screen := startTestUIOn(t, cluster)
+
+	// The probe runs in the background; the picker shows what it knew when opened.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		screen.InjectKey(tcell.KeyCtrlN, 0, tcell.ModNone)
+		lines := waitForScreen(t, screen, "team-a", func(string) bool { return true })
+		text := strings.Join(lines, "\n")
+		if strings.Contains(text, "team-b (no access to events)") {
+			if strings.Contains(text, "team-a (no access") || strings.Contains(text, "default (no access") {
+				t.Fatalf("readable namespaces are marked:\n%s", text)
+			}
+			screen.InjectKey(tcell.KeyEsc, 0, tcell.ModNone)
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("team-b is not marked:\n%s", text)
+		}
+		screen.InjectKey(tcell.KeyEsc, 0, tcell.ModNone)
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// runCommand types command into the command palette and runs it.
+func runCommand(screen *lockedScreen, command string) {
+	screen.InjectKey(tcell.KeyRune, ':', tcell.ModNone)
+	for _, r := range command {
+		screen.InjectKey(tcell.KeyRune, r, tcell.ModNone)
+	}
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+}
+
+func TestSetOverridesAreNotSavedWithTheTheme(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	_, screen := startTestUI(t)
+
+	runCommand(screen, "set stormThreshold=3")
+	waitForScreen(t, screen, "stormThreshold = 3", func(string) bool { return true })
+	runCommand(screen, "set dropDeleted=true")
+	waitForScreen(t, screen, "dropDeleted = true", func(string) bool { return true })
+	runCommand(screen, "theme midnight")
+
+	var saved config.Config
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		data, err := os.ReadFile(config.Path())
+		if err == nil {
+			if saved, err = config.Parse(data); err != nil {
+				t.Fatal(err)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the theme was not saved: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if saved.Theme.Name != "midnight" {
+		t.Fatalf("saved theme %q, want midnight", saved.Theme.Name)
+	}
+	if saved.Noise != config.Default.Noise || saved.Flags.DropDeleted {
+		t.Fatalf("runtime settings were saved with the theme: %+v, dropDeleted %v", saved.Noise, saved.Flags.DropDeleted)
+	}
+}